@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	"fyne.io/fyne/v2/app"
+
+	"github.com/bahelit/ctrl_plus_revise/internal/gui/glossary"
+	"github.com/bahelit/ctrl_plus_revise/internal/gui/workflows"
+	"github.com/bahelit/ctrl_plus_revise/internal/ollama"
+	"github.com/bahelit/ctrl_plus_revise/pkg/workflow"
+)
+
+// cliOptions are the command line flags, without flags the app starts in the
+// system tray.
+type cliOptions struct {
+	workflow string
+	input    string
+}
+
+func parseFlags() cliOptions {
+	var opts cliOptions
+	flag.StringVar(&opts.workflow, "workflow", "", "run the named workflow on the input and print the result")
+	flag.StringVar(&opts.input, "input", "-", "file the workflow reads, - reads standard input")
+	flag.Parse()
+	return opts
+}
+
+// runWorkflow runs a workflow from the command line and returns the exit code,
+// the result of every step is logged and the output is printed.
+func runWorkflow(opts cliOptions) int {
+	cliApp := app.NewWithID("com.ctrlplusrevise.app")
+	wf, ok := workflows.Find(cliApp, opts.workflow)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Unknown workflow %q, create it in Settings > Workflows\n", opts.workflow)
+		return 2
+	}
+	input, err := readInput(opts.input)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Unable to read the input:", err)
+		return 2
+	}
+
+	ollamaClient := ollama.CheckOllamaConnection(cliApp, nil, nil)
+	if ollamaClient == nil {
+		fmt.Fprintln(os.Stderr, "Unable to connect to Ollama")
+		return 1
+	}
+	glossary.Load()
+
+	results, err := wf.Run(context.Background(), input, workflows.Executor(cliApp, ollamaClient), func(r workflow.Result) {
+		slog.Info("Finished workflow step", "step", r.Label())
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	fmt.Println(workflow.Output(input, results))
+	return 0
+}
+
+func readInput(path string) (string, error) {
+	if path == "-" {
+		data, err := io.ReadAll(os.Stdin)
+		return string(data), err
+	}
+	data, err := os.ReadFile(path)
+	return string(data), err
+}
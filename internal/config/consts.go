@@ -2,15 +2,44 @@ package config
 
 const (
 	ReplaceHighlightedText     = "ReplaceHighlightedText"
+	DeliveryModeKey            = "DeliveryMode"
+	DisabledModulesKey         = "DisabledModules"
+	DocFoldersKey              = "DocFolders"
+	DocTopKKey                 = "DocTopK"
+	AskDocumentsKey            = "AskDocuments"
+	EmbeddingModelKey          = "EmbeddingModel"
+	PostProcessRulesKey        = "PostProcessRules"
+	ResultWindowWidthKey       = "ResultWindowWidth"
+	ResultWindowHeightKey      = "ResultWindowHeight"
 	SpeakAIResponseKey         = "SpeakAIResponseKey"
-	ShowPopUpKey               = "ShowPopUpKey"
+	ReadAIResponseKey          = "ReadAIResponseKey"
+	SpeechEngineKey            = "SpeechEngine"
+	SpeechVoiceKey             = "SpeechVoice"
+	SpeechRateKey              = "SpeechRate"
+	SpeechLanguageKey          = "SpeechLanguage"
+	DictationKey               = "Dictation"
+	DictationApplyPromptKey    = "DictationApplyPrompt"
+	WhisperURLKey              = "WhisperURL"
+	WhisperBinaryKey           = "WhisperBinary"
+	WhisperModelKey            = "WhisperModel"
+	WhisperLanguageKey         = "WhisperLanguage"
 	ShowStartWindowKey         = "showStartWindow"
 	firstRunKey                = "firstRun"
 	CurrentPromptKey           = "lastPrompt"
+	CurrentTemplateKey         = "lastTemplate"
+	AppProfilesKey             = "appProfiles"
+	CurrentWorkflowKey         = "lastWorkflow"
+	WorkflowsKey               = "workflows"
+	PromptTemplatesKey         = "promptTemplates"
+	PromptVariablesKey         = "promptVariables"
 	CurrentChatModelKey        = "lastChatModel"
 	CurrentModelKey            = "lastModel"
+	NumCtxKey                  = "numCtx"
 	CurrentFromLangKey         = "fromLang"
 	CurrentToLangKey           = "toLang"
+	TranslationLanguagesKey    = "translationLanguages"
+	MyLanguageKey              = "myLanguage"
+	SecondLanguageKey          = "secondLanguage"
 	StopOllamaOnShutDownKey    = "stopOllamaOnShutDown"
 	UseRemoteOllamaKey         = "useRemoteOllama"
 	OllamaURLKey               = "OllamaURL"
@@ -29,6 +58,9 @@ const (
 	PantryKey    = "MealPantry"
 	VeggiesKey   = "MealVeggies"
 	ProteinKey   = "MealProtein"
+	DietKey      = "MealDiet"
+	HouseholdKey = "MealHousehold"
+	EatersKey    = "MealEaters"
 
 	LengthOfKeyBoardShortcuts = 3
 )
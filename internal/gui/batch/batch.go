@@ -0,0 +1,193 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	ollamaApi "github.com/ollama/ollama/api"
+
+	"github.com/bahelit/ctrl_plus_revise/internal/gui"
+	"github.com/bahelit/ctrl_plus_revise/internal/ollama"
+	"github.com/bahelit/ctrl_plus_revise/pkg/docsplit"
+)
+
+const translateAction = "Translate"
+
+// file is a file of the batch and how far along it is.
+type file struct {
+	path   string
+	status string
+}
+
+// BatchWindow translates or revises a set of files, the results are written
+// next to the originals.
+func BatchWindow(guiApp fyne.App, ollamaClient *ollamaApi.Client) {
+	slog.Debug("Showing batch window")
+	w := guiApp.NewWindow("Ctrl+Revise Batch Files")
+	w.Resize(fyne.NewSize(700, 480))
+
+	var (
+		files  []*file
+		cancel context.CancelFunc
+	)
+	list := widget.NewList(
+		func() int { return len(files) },
+		func() fyne.CanvasObject {
+			return container.NewBorder(nil, nil, nil, widget.NewLabel("status"), widget.NewLabel("file"))
+		},
+		func(id widget.ListItemID, o fyne.CanvasObject) {
+			row := o.(*fyne.Container)
+			row.Objects[0].(*widget.Label).SetText(files[id].path)
+			row.Objects[1].(*widget.Label).SetText(files[id].status)
+		})
+
+	addFiles := widget.NewButtonWithIcon("Add File", theme.ContentAddIcon(), func() {
+		open := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+			if err != nil || reader == nil {
+				return
+			}
+			_ = reader.Close()
+			files = append(files, &file{path: reader.URI().Path(), status: "Waiting"})
+			list.Refresh()
+		}, w)
+		open.SetFilter(storage.NewExtensionFileFilter([]string{".txt", ".md", ".po", ".json", ".srt"}))
+		open.Show()
+	})
+	clearFiles := widget.NewButtonWithIcon("Clear", theme.ContentClearIcon(), func() {
+		files = nil
+		list.Refresh()
+	})
+
+	var actions []string
+	actions = append(actions, translateAction)
+	for _, p := range ollama.RevisionPrompts() {
+		if p.Schema() != nil {
+			// data describes the file, it can't replace its parts
+			continue
+		}
+		actions = append(actions, p.String())
+	}
+	toLang := widget.NewSelect(gui.Languages(guiApp), nil)
+	toLang.SetSelectedIndex(0)
+	action := widget.NewSelect(actions, func(s string) {
+		if s == translateAction {
+			toLang.Enable()
+		} else {
+			toLang.Disable()
+		}
+	})
+	action.SetSelected(translateAction)
+
+	progress := widget.NewProgressBar()
+	var start, stop *widget.Button
+	start = widget.NewButtonWithIcon("Start", theme.MediaPlayIcon(), func() {
+		if len(files) == 0 {
+			return
+		}
+		ctx, cancelFunc := context.WithCancel(context.Background())
+		cancel = cancelFunc
+		start.Disable()
+		stop.Enable()
+		go func() {
+			runBatch(ctx, guiApp, ollamaClient, files, action.Selected, ollama.Language(toLang.Selected), progress, list)
+			cancelFunc()
+			start.Enable()
+			stop.Disable()
+		}()
+	})
+	start.Importance = widget.HighImportance
+	stop = widget.NewButtonWithIcon("Stop", theme.MediaStopIcon(), func() {
+		if cancel != nil {
+			cancel()
+		}
+	})
+	stop.Disable()
+
+	note := widget.NewLabel("Results are written next to each file, e.g. README.es.md. " +
+		"Starting again after a failure continues where the file stopped.")
+	note.Wrapping = fyne.TextWrapWord
+
+	top := container.NewVBox(
+		container.New(layout.NewFormLayout(),
+			widget.NewLabel("Action:"), action,
+			widget.NewLabel("Translate to:"), toLang,
+		),
+		container.NewHBox(addFiles, clearFiles),
+	)
+	bottom := container.NewVBox(progress, note, container.NewHBox(layout.NewSpacer(), stop, start))
+	w.SetContent(container.NewBorder(top, bottom, nil, nil, list))
+	w.Show()
+}
+
+func runBatch(ctx context.Context, guiApp fyne.App, ollamaClient *ollamaApi.Client, files []*file,
+	action string, toLang ollama.Language, progress *widget.ProgressBar, list *widget.List) {
+	transform, suffix := transformFor(guiApp, ollamaClient, action, toLang)
+	settings := action + " " + suffix + " " + ollama.GetActiveModel(guiApp).String()
+	for _, f := range files {
+		if ctx.Err() != nil {
+			return
+		}
+		job := docsplit.Job{
+			Path:      f.path,
+			Suffix:    suffix,
+			Settings:  settings,
+			Retries:   2,
+			Transform: transform,
+			Progress: func(done, total int) {
+				f.status = fmt.Sprintf("%d of %d", done, total)
+				if total > 0 {
+					progress.SetValue(float64(done) / float64(total))
+				}
+				list.Refresh()
+			},
+		}
+		err := job.Run(ctx)
+		if errors.Is(err, context.Canceled) {
+			f.status = "Stopped, start again to resume"
+		} else if err != nil {
+			slog.Error("Batch file failed", "file", f.path, "error", err)
+			f.status = "Failed, start again to resume"
+		} else {
+			f.status = "Done: " + filepath.Base(job.OutputPath())
+		}
+		list.Refresh()
+	}
+}
+
+// transformFor returns how each piece of a file is processed and the suffix
+// of the output files, the language of a translation or the name of the
+// prompt, e.g. README.correct-grammar.md.
+func transformFor(guiApp fyne.App, ollamaClient *ollamaApi.Client, action string, toLang ollama.Language) (docsplit.Transform, string) {
+	if action == translateAction {
+		suffix := strings.ToLower(string(toLang))
+		if tag, ok := ollama.LanguageTag(guiApp, toLang); ok {
+			suffix = tag.String()
+		}
+		return func(ctx context.Context, text, instructions string) (string, error) {
+			generated, err := ollama.AskAIToTranslateWithInstructions(ctx, guiApp, ollamaClient, text, "", toLang, instructions)
+			return generated.Response, err
+		}, suffix
+	}
+
+	prompt := ollama.CorrectGrammar
+	for _, p := range ollama.RevisionPrompts() {
+		if p.String() == action {
+			prompt = p
+		}
+	}
+	return func(ctx context.Context, text, instructions string) (string, error) {
+		generated, err := ollama.AskAIWithPromptMsgAndInstructions(ctx, guiApp, ollamaClient, prompt, text, instructions)
+		return generated.Response, err
+	}, strings.Join(strings.Fields(strings.ToLower(strings.ReplaceAll(prompt.String(), "'", ""))), "-")
+}
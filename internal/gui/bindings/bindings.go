@@ -28,13 +28,13 @@ func SetBindingVariables(guiApp fyne.App) error {
 		slog.Error("Failed to set SelectedModelBinding", "error", err)
 	}
 
-	from := guiApp.Preferences().StringWithFallback(config.CurrentFromLangKey, string(ollama.English))
+	from := guiApp.Preferences().StringWithFallback(config.CurrentFromLangKey, string(ollama.AutoDetect))
 	err = TranslationFromBinding.Set(from)
 	if err != nil {
 		slog.Error("Failed to set SelectedModelBinding", "error", err)
 	}
 
-	to := guiApp.Preferences().StringWithFallback(config.CurrentToLangKey, string(ollama.Spanish))
+	to := guiApp.Preferences().StringWithFallback(config.CurrentToLangKey, string(ollama.MyLanguage))
 	err = TranslationToBinding.Set(to)
 	if err != nil {
 		slog.Error("Failed to set SelectedModelBinding", "error", err)
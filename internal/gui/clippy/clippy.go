@@ -1,221 +1,325 @@
 package clippy
 
 import (
-	"crypto/sha256"
+	"fmt"
 	"log/slog"
+	"strings"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/layout"
 	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
+	ollamaApi "github.com/ollama/ollama/api"
 
+	"github.com/bahelit/ctrl_plus_revise/internal/config"
 	"github.com/bahelit/ctrl_plus_revise/internal/gui/loading"
-	"github.com/bahelit/ctrl_plus_revise/internal/gui/shortcuts"
+	"github.com/bahelit/ctrl_plus_revise/internal/gui/metrics"
 	"github.com/bahelit/ctrl_plus_revise/internal/ollama"
-	ollamaApi "github.com/ollama/ollama/api"
 )
 
-func QuestionPopUp(guiApp fyne.App, ollamaClient *ollamaApi.Client, question string, response *ollamaApi.GenerateResponse) {
-	w := guiApp.NewWindow("Ctrl+Revise")
-	w.Resize(fyne.NewSize(640, 500))
-	hello := widget.NewLabel("Glad to Help!")
-	hello.TextStyle = fyne.TextStyle{Bold: true}
-	hello.Alignment = fyne.TextAlignCenter
+const (
+	defaultWidth  float32 = 640
+	defaultHeight float32 = 500
+)
 
-	questionText := widget.NewLabel("Question:")
-	questionText.Alignment = fyne.TextAlignLeading
-	questionText.Wrapping = fyne.TextWrapWord
-	questionText.TextStyle = fyne.TextStyle{Bold: true}
-	questionText1 := widget.NewLabel(question)
-	questionText1.Alignment = fyne.TextAlignLeading
-	questionText1.Wrapping = fyne.TextWrapWord
+// Result is what is shown in the result window.
+type Result struct {
+	// Heading is shown above the input, e.g. "Question:" or "Highlighted Text:".
+	Heading string
+	Input   string
+	// Revises is set when the response is a revision of the input, the
+	// readability of the input is compared with the response's.
+	Revises bool
+	// Response is the first AI response, refining it adds versions to the history.
+	Response *ollamaApi.GenerateResponse
+	// Steps are the responses of the steps of a workflow, they replace
+	// Response in the history and the last one is shown.
+	Steps []Step
+	// FollowUps are the prompts offered to rework the response, the prompt
+	// library's follow-up prompts are used when empty.
+	FollowUps []ollama.PromptMsg
+	// OnCopy is called with the text copied to the clipboard.
+	OnCopy func(text string)
+}
 
-	generatedText := widget.NewLabel("AI Response:")
-	generatedText.Alignment = fyne.TextAlignLeading
-	generatedText.Wrapping = fyne.TextWrapWord
-	generatedText.TextStyle = fyne.TextStyle{Bold: true}
-
-	generatedText1 := widget.NewRichTextFromMarkdown(response.Response)
-	generatedText1.Wrapping = fyne.TextWrapWord
-
-	vbox := container.NewVScroll(generatedText1)
-
-	buttons := container.NewHBox(
-		widget.NewButton("Try Again", func() {
-			loadingScreen := loading.LoadingScreenWithMessageAddModel(guiApp, loading.ThinkingMsg,
-				"Trying Again...")
-			loadingScreen.Show()
-			reGenerated, err := ollama.AskAiWithPromptAndContext(guiApp, ollamaClient, response.Context, ollama.TryAgain)
-			if err != nil {
-				slog.Error("Failed to re-generate", "error", err)
-				return
-			}
-			shortcuts.LastClipboardContent = sha256.Sum256([]byte(response.Response))
-			w.Hide()
-			loadingScreen.Hide()
-			QuestionPopUp(guiApp, ollamaClient, question, &reGenerated)
-		}),
-		widget.NewButton("Make the text more Friendly", func() {
-			loadingScreen := loading.LoadingScreenWithMessageAddModel(guiApp, loading.ThinkingMsg,
-				"Making the text more Friendly...")
-			loadingScreen.Show()
-			reGenerated, err := ollama.AskAiWithPromptAndContext(guiApp, ollamaClient, response.Context, ollama.MakeItFriendlyRedo)
-			if err != nil {
-				slog.Error("Failed to re-generate", "error", err)
-				return
-			}
-			shortcuts.LastClipboardContent = sha256.Sum256([]byte(response.Response))
-			w.Hide()
-			loadingScreen.Hide()
-			QuestionPopUp(guiApp, ollamaClient, question, &reGenerated)
-		}),
-		widget.NewButton("Make the text more Professional", func() {
-			loadingScreen := loading.LoadingScreenWithMessageAddModel(guiApp, loading.ThinkingMsg,
-				"Making the text more Professional...")
-			loadingScreen.Show()
-			reGenerated, err := ollama.AskAiWithPromptAndContext(guiApp, ollamaClient, response.Context, ollama.MakeItProfessionalRedo)
-			if err != nil {
-				slog.Error("Failed to re-generate", "error", err)
-				return
-			}
-			shortcuts.LastClipboardContent = sha256.Sum256([]byte(response.Response))
-			w.Hide()
-			loadingScreen.Hide()
-			QuestionPopUp(guiApp, ollamaClient, question, &reGenerated)
-		}),
-		widget.NewButton("Make the text a Bulleted List", func() {
-			loadingScreen := loading.LoadingScreenWithMessageAddModel(guiApp, loading.ThinkingMsg,
-				"Making the text a Bulleted List...")
-			loadingScreen.Show()
-			reGenerated, err := ollama.AskAiWithPromptAndContext(guiApp, ollamaClient, response.Context, ollama.MakeItAListRedo)
-			if err != nil {
-				slog.Error("Failed to re-generate", "error", err)
-				return
-			}
-			shortcuts.LastClipboardContent = sha256.Sum256([]byte(response.Response))
-			w.Hide()
-			loadingScreen.Hide()
-			QuestionPopUp(guiApp, ollamaClient, question, &reGenerated)
-		}),
-		widget.NewButtonWithIcon("Copy generated text to Clipboard", theme.ContentCopyIcon(), func() {
-			w.Clipboard().SetContent(response.Response)
-			w.Close()
-		}),
-	)
-	buttons.Layout = layout.NewAdaptiveGridLayout(3)
+// Step is the response of one step of a workflow.
+type Step struct {
+	Label    string
+	Response *ollamaApi.GenerateResponse
+}
+
+// iteration is one version of the response.
+type iteration struct {
+	label    string
+	response *ollamaApi.GenerateResponse
+}
 
-	grid := container.New(layout.NewAdaptiveGridLayout(1), vbox)
+// resultView keeps every version of the response so the user can step back
+// and pick the one they like best.
+type resultView struct {
+	guiApp       fyne.App
+	ollamaClient *ollamaApi.Client
+	// content is refilled with the current version, it is the content of the
+	// result window or of the tab the result is shown in.
+	content   *fyne.Container
+	clipboard func() fyne.Clipboard
+	close     func()
+	result    Result
+	history   []iteration
+	current   int
+}
 
-	questionSection := container.NewVBox(hello, questionText, questionText1, generatedText)
-	w.SetContent(container.NewBorder(
-		questionSection,
-		buttons,
-		nil,
-		nil,
-		container.NewVScroll(grid),
-	))
+// ShowResult opens the result window, the window keeps the size the user
+// last left it at. Fyne doesn't expose the window position so only the size
+// is remembered.
+func ShowResult(guiApp fyne.App, ollamaClient *ollamaApi.Client, result Result) {
+	w := guiApp.NewWindow("Ctrl+Revise")
+	w.Resize(fyne.NewSize(
+		float32(guiApp.Preferences().FloatWithFallback(config.ResultWindowWidthKey, float64(defaultWidth))),
+		float32(guiApp.Preferences().FloatWithFallback(config.ResultWindowHeightKey, float64(defaultHeight)))))
+	w.SetCloseIntercept(func() {
+		rememberSize(guiApp, w)
+		w.Close()
+	})
+	view := newResultView(guiApp, ollamaClient, result)
+	view.clipboard = w.Clipboard
+	view.close = func() {
+		rememberSize(guiApp, w)
+		w.Close()
+	}
+	view.show()
+	w.SetContent(view.content)
 	w.Show()
 }
 
+// QuestionTab shows the answer to a question in a new tab next to the
+// question, copying the answer closes the tab.
 func QuestionTab(guiApp fyne.App, tabs *container.AppTabs, ollamaClient *ollamaApi.Client, question string, response *ollamaApi.GenerateResponse) {
-	w := guiApp.NewWindow("Ctrl+Revise")
-	w.Resize(fyne.NewSize(640, 500))
+	view := newResultView(guiApp, ollamaClient, Result{Input: question, Response: response})
+	tab := container.NewTabItemWithIcon(tabTitle(question), theme.QuestionIcon(), view.content)
+	view.clipboard = func() fyne.Clipboard {
+		return windowFor(guiApp, tabs).Clipboard()
+	}
+	view.close = func() {
+		tabs.Remove(tab)
+	}
+	view.show()
+	tabs.Append(tab)
+	tabs.Select(tab)
+}
+
+func newResultView(guiApp fyne.App, ollamaClient *ollamaApi.Client, result Result) *resultView {
+	if len(result.FollowUps) == 0 {
+		result.FollowUps = ollama.FollowUpPrompts()
+	}
+	if result.Heading == "" {
+		result.Heading = "Question:"
+	}
+	view := &resultView{
+		guiApp:       guiApp,
+		ollamaClient: ollamaClient,
+		content:      container.NewStack(),
+		result:       result,
+		history:      []iteration{{label: "Original", response: result.Response}},
+	}
+	if len(result.Steps) > 0 {
+		view.history = nil
+		for _, step := range result.Steps {
+			view.history = append(view.history, iteration{label: step.Label, response: step.Response})
+		}
+		view.current = len(view.history) - 1
+	}
+	return view
+}
+
+// tabTitle shortens the question to fit on a tab.
+func tabTitle(question string) string {
+	const maxLength = 24
+	title := []rune(strings.Join(strings.Fields(question), " "))
+	if len(title) > maxLength {
+		return string(title[:maxLength]) + "…"
+	}
+	return string(title)
+}
+
+// windowFor returns the window showing the object.
+func windowFor(guiApp fyne.App, o fyne.CanvasObject) fyne.Window {
+	windows := guiApp.Driver().AllWindows()
+	c := guiApp.Driver().CanvasForObject(o)
+	for _, w := range windows {
+		if w.Canvas() == c {
+			return w
+		}
+	}
+	return windows[0]
+}
+
+func rememberSize(guiApp fyne.App, w fyne.Window) {
+	size := w.Canvas().Size()
+	guiApp.Preferences().SetFloat(config.ResultWindowWidthKey, float64(size.Width))
+	guiApp.Preferences().SetFloat(config.ResultWindowHeightKey, float64(size.Height))
+}
+
+func (v *resultView) response() *ollamaApi.GenerateResponse {
+	return v.history[v.current].response
+}
+
+func (v *resultView) versionNames() []string {
+	var names []string
+	for i, it := range v.history {
+		names = append(names, fmt.Sprintf("%d. %s", i+1, it.label))
+	}
+	return names
+}
+
+// show fills the window with the current version, refining updates the same
+// window instead of opening a new one.
+func (v *resultView) show() {
 	hello := widget.NewLabel("Glad to Help!")
 	hello.TextStyle = fyne.TextStyle{Bold: true}
 	hello.Alignment = fyne.TextAlignCenter
 
-	questionText := widget.NewLabel("Question:")
-	questionText.Alignment = fyne.TextAlignLeading
-	questionText.Wrapping = fyne.TextWrapWord
-	questionText.TextStyle = fyne.TextStyle{Bold: true}
-	questionText1 := widget.NewLabel(question)
-	questionText1.Alignment = fyne.TextAlignLeading
-	questionText1.Wrapping = fyne.TextWrapWord
+	inputHeading := widget.NewLabel(v.result.Heading)
+	inputHeading.TextStyle = fyne.TextStyle{Bold: true}
+	inputText := widget.NewLabel(v.result.Input)
+	inputText.Wrapping = fyne.TextWrapWord
 
-	generatedText := widget.NewLabel("AI Response:")
-	generatedText.Alignment = fyne.TextAlignLeading
+	generatedHeading := widget.NewLabel("AI Response:")
+	generatedHeading.TextStyle = fyne.TextStyle{Bold: true}
+	generatedText := widget.NewRichTextFromMarkdown(v.response().Response)
 	generatedText.Wrapping = fyne.TextWrapWord
-	generatedText.TextStyle = fyne.TextStyle{Bold: true}
-
-	generatedText1 := widget.NewRichTextFromMarkdown(response.Response)
-	generatedText1.Wrapping = fyne.TextWrapWord
-
-	vbox := container.NewVScroll(generatedText1)
-
-	buttons := container.NewHBox(
-		widget.NewButton("Try Again", func() {
-			loadingScreen := loading.LoadingScreenWithMessageAddModel(guiApp, loading.ThinkingMsg,
-				"Trying Again...")
-			loadingScreen.Show()
-			reGenerated, err := ollama.AskAiWithPromptAndContext(guiApp, ollamaClient, response.Context, ollama.TryAgain)
-			if err != nil {
-				slog.Error("Failed to re-generate", "error", err)
-				return
-			}
-			shortcuts.LastClipboardContent = sha256.Sum256([]byte(response.Response))
-			w.Hide()
-			loadingScreen.Hide()
-			QuestionTab(guiApp, tabs, ollamaClient, question, &reGenerated)
-		}),
-		widget.NewButton("Make the text more Friendly", func() {
-			loadingScreen := loading.LoadingScreenWithMessageAddModel(guiApp, loading.ThinkingMsg,
-				"Making the text more Friendly...")
-			loadingScreen.Show()
-			reGenerated, err := ollama.AskAiWithPromptAndContext(guiApp, ollamaClient, response.Context, ollama.MakeItFriendlyRedo)
-			if err != nil {
-				slog.Error("Failed to re-generate", "error", err)
-				return
-			}
-			shortcuts.LastClipboardContent = sha256.Sum256([]byte(response.Response))
-			w.Hide()
-			loadingScreen.Hide()
-			QuestionTab(guiApp, tabs, ollamaClient, question, &reGenerated)
-		}),
-		widget.NewButton("Make the text more Professional", func() {
-			loadingScreen := loading.LoadingScreenWithMessageAddModel(guiApp, loading.ThinkingMsg,
-				"Making the text more Professional...")
-			loadingScreen.Show()
-			reGenerated, err := ollama.AskAiWithPromptAndContext(guiApp, ollamaClient, response.Context, ollama.MakeItProfessionalRedo)
-			if err != nil {
-				slog.Error("Failed to re-generate", "error", err)
-				return
-			}
-			shortcuts.LastClipboardContent = sha256.Sum256([]byte(response.Response))
-			w.Hide()
-			loadingScreen.Hide()
-			QuestionTab(guiApp, tabs, ollamaClient, question, &reGenerated)
-		}),
-		widget.NewButton("Make the text a Bulleted List", func() {
-			loadingScreen := loading.LoadingScreenWithMessageAddModel(guiApp, loading.ThinkingMsg,
-				"Making the text a Bulleted List...")
-			loadingScreen.Show()
-			reGenerated, err := ollama.AskAiWithPromptAndContext(guiApp, ollamaClient, response.Context, ollama.MakeItAListRedo)
-			if err != nil {
-				slog.Error("Failed to re-generate", "error", err)
-				return
-			}
-			shortcuts.LastClipboardContent = sha256.Sum256([]byte(response.Response))
-			w.Hide()
-			loadingScreen.Hide()
-			QuestionTab(guiApp, tabs, ollamaClient, question, &reGenerated)
-		}),
-		widget.NewButtonWithIcon("Copy generated text to Clipboard", theme.ContentCopyIcon(), func() {
-			w.Clipboard().SetContent(response.Response)
-			w.Close()
-		}),
-	)
-	buttons.Layout = layout.NewAdaptiveGridLayout(3)
 
-	grid := container.New(layout.NewAdaptiveGridLayout(1), vbox)
-
-	questionSection := container.NewVBox(hello, questionText, questionText1, generatedText)
-	w.SetContent(container.NewBorder(
+	questionSection := container.NewVBox(hello, inputHeading, inputText,
+		container.NewBorder(nil, nil, generatedHeading, nil, v.historyBar()))
+	bottom := container.NewVBox(v.refineBar(), v.buttons())
+	v.content.Objects = []fyne.CanvasObject{container.NewBorder(
 		questionSection,
-		buttons,
+		bottom,
 		nil,
 		nil,
-		container.NewVScroll(grid),
-	))
-	w.Show()
+		container.NewBorder(nil, v.readability(), nil, nil, container.NewVScroll(generatedText)),
+	)}
+	v.content.Refresh()
+}
+
+// readability compares the current version with the highlighted text it
+// revises, or with the first version of an answer once it was refined.
+func (v *resultView) readability() fyne.CanvasObject {
+	var table fyne.CanvasObject
+	switch {
+	case v.result.Revises:
+		table = metrics.Table(v.result.Input, v.response().Response)
+	case v.current > 0:
+		table = metrics.Table(v.history[0].response.Response, v.response().Response)
+	default:
+		table = metrics.Single(v.response().Response)
+	}
+	return widget.NewAccordion(widget.NewAccordionItem("Readability", table))
+}
+
+// historyBar steps back and forth through the versions of the response.
+func (v *resultView) historyBar() fyne.CanvasObject {
+	versions := widget.NewSelect(v.versionNames(), nil)
+	versions.SetSelectedIndex(v.current)
+	versions.OnChanged = func(string) {
+		if versions.SelectedIndex() != v.current {
+			v.current = versions.SelectedIndex()
+			v.show()
+		}
+	}
+	previous := widget.NewButtonWithIcon("", theme.NavigateBackIcon(), func() {
+		v.current--
+		v.show()
+	})
+	next := widget.NewButtonWithIcon("", theme.NavigateNextIcon(), func() {
+		v.current++
+		v.show()
+	})
+	if v.current == 0 {
+		previous.Disable()
+	}
+	if v.current == len(v.history)-1 {
+		next.Disable()
+	}
+	return container.NewBorder(nil, nil, previous, next, versions)
+}
+
+// refineBar transforms the current version with any prompt or with an
+// instruction typed by the user.
+func (v *resultView) refineBar() fyne.CanvasObject {
+	var promptNames []string
+	for _, p := range ollama.RevisionPrompts() {
+		promptNames = append(promptNames, p.String())
+	}
+	prompts := widget.NewSelect(promptNames, nil)
+	prompts.PlaceHolder = "Apply a prompt"
+	applyPrompt := widget.NewButton("Apply", func() {
+		if prompts.SelectedIndex() < 0 {
+			return
+		}
+		prompt := ollama.RevisionPrompts()[prompts.SelectedIndex()]
+		text := v.response().Response
+		v.refine(prompt.String(), func() (ollamaApi.GenerateResponse, error) {
+			return ollama.AskAIToRevise(v.guiApp, v.ollamaClient, prompt, text)
+		})
+	})
+
+	instruction := widget.NewEntry()
+	instruction.SetPlaceHolder("Or tell the AI what to change, e.g. shorter, in British English")
+	refineInstruction := func() {
+		text := strings.TrimSpace(instruction.Text)
+		if text == "" {
+			return
+		}
+		current := v.response().Response
+		v.refine(text, func() (ollamaApi.GenerateResponse, error) {
+			return ollama.AskAIToRefine(v.guiApp, v.ollamaClient, text, current)
+		})
+	}
+	instruction.OnSubmitted = func(string) { refineInstruction() }
+	refineButton := widget.NewButton("Refine", refineInstruction)
+
+	return container.NewVBox(
+		container.NewBorder(nil, nil, nil, applyPrompt, prompts),
+		container.NewBorder(nil, nil, nil, refineButton, instruction),
+	)
+}
+
+func (v *resultView) buttons() fyne.CanvasObject {
+	buttons := container.NewHBox()
+	for _, prompt := range v.result.FollowUps {
+		buttons.Add(widget.NewButton(prompt.FollowUpLabel(), func() {
+			msgContext := v.response().Context
+			v.refine(prompt.FollowUpLabel(), func() (ollamaApi.GenerateResponse, error) {
+				return ollama.AskAiWithPromptAndContext(v.guiApp, v.ollamaClient, msgContext, prompt)
+			})
+		}))
+	}
+	buttons.Add(widget.NewButtonWithIcon("Copy generated text to Clipboard", theme.ContentCopyIcon(), func() {
+		text := v.response().Response
+		v.clipboard().SetContent(text)
+		if v.result.OnCopy != nil {
+			v.result.OnCopy(text)
+		}
+		v.close()
+	}))
+	buttons.Layout = layout.NewAdaptiveGridLayout(3)
+	return buttons
+}
+
+// refine adds the generated response to the end of the history and shows it.
+func (v *resultView) refine(label string, generate func() (ollamaApi.GenerateResponse, error)) {
+	loadingScreen := loading.LoadingScreenWithMessageAddModel(v.guiApp, loading.ThinkingMsg,
+		label+"...")
+	loadingScreen.Show()
+	reGenerated, err := generate()
+	loadingScreen.Hide()
+	if err != nil {
+		slog.Error("Failed to re-generate", "error", err)
+		return
+	}
+	v.history = append(v.history, iteration{label: label, response: &reGenerated})
+	v.current = len(v.history) - 1
+	v.show()
 }
@@ -0,0 +1,84 @@
+package delivery
+
+import (
+	"log/slog"
+
+	"fyne.io/fyne/v2"
+
+	"github.com/bahelit/ctrl_plus_revise/internal/config"
+)
+
+// Mode is what happens with an AI response once it has been generated.
+//
+//go:generate stringer -linecomment -type=Mode
+type Mode int
+
+const (
+	Paste             Mode = iota // Paste over the highlighted text
+	ClipboardOnly                 // Copy to the clipboard only
+	PopUp                         // Show in a pop-up window
+	PopUpAndClipboard             // Show in a pop-up and copy to the clipboard
+	Review                        // Review the changes before pasting
+)
+
+// Action is a keyboard shortcut whose response can be delivered differently.
+type Action string
+
+const (
+	Ask       Action = "Ask"
+	Revise    Action = "Revise"
+	Translate Action = "Translate"
+)
+
+// Modes returns every mode in the order they are offered in the settings.
+func Modes() []Mode {
+	return []Mode{Paste, ClipboardOnly, PopUp, PopUpAndClipboard, Review}
+}
+
+// ModeNames returns the display names of Modes.
+func ModeNames() []string {
+	var names []string
+	for _, m := range Modes() {
+		names = append(names, m.String())
+	}
+	return names
+}
+
+// ModeFromString returns the mode with the display name s.
+func ModeFromString(s string) Mode {
+	for _, m := range Modes() {
+		if m.String() == s {
+			return m
+		}
+	}
+	slog.Error("Unknown delivery mode", "mode", s)
+	return Paste
+}
+
+// Get returns the delivery mode chosen for the action, users that haven't
+// picked one keep the behaviour of the older paste checkbox.
+func Get(guiApp fyne.App, action Action) Mode {
+	mode := guiApp.Preferences().IntWithFallback(config.DeliveryModeKey+string(action), -1)
+	if mode >= 0 && mode < len(Modes()) {
+		return Mode(mode)
+	}
+	if guiApp.Preferences().BoolWithFallback(config.ReplaceHighlightedText, true) {
+		return Paste
+	}
+	return ClipboardOnly
+}
+
+// Set saves the delivery mode for the action.
+func Set(guiApp fyne.App, action Action, mode Mode) {
+	guiApp.Preferences().SetInt(config.DeliveryModeKey+string(action), int(mode))
+}
+
+// CopiesToClipboard reports whether the response should be put in the clipboard.
+func (m Mode) CopiesToClipboard() bool {
+	return m == Paste || m == ClipboardOnly || m == PopUpAndClipboard
+}
+
+// ShowsPopUp reports whether the response should be shown in the result window.
+func (m Mode) ShowsPopUp() bool {
+	return m == PopUp || m == PopUpAndClipboard
+}
@@ -0,0 +1,31 @@
+package delivery
+
+import (
+	"fyne.io/fyne/v2"
+
+	"github.com/bahelit/ctrl_plus_revise/internal/config"
+	"github.com/bahelit/ctrl_plus_revise/pkg/postprocess"
+)
+
+// PostProcessRules returns the clean-up rules applied to responses.
+func PostProcessRules(guiApp fyne.App) []postprocess.Rule {
+	var defaults []string
+	for _, r := range postprocess.DefaultRules() {
+		defaults = append(defaults, string(r))
+	}
+	return postprocess.ParseRules(guiApp.Preferences().StringListWithFallback(config.PostProcessRulesKey, defaults))
+}
+
+// SetPostProcessRules saves the clean-up rules applied to responses.
+func SetPostProcessRules(guiApp fyne.App, rules []postprocess.Rule) {
+	names := []string{}
+	for _, r := range rules {
+		names = append(names, string(r))
+	}
+	guiApp.Preferences().SetStringList(config.PostProcessRulesKey, names)
+}
+
+// CleanUp applies the user's clean-up rules to a response to the input.
+func CleanUp(guiApp fyne.App, input, response string) string {
+	return postprocess.Apply(PostProcessRules(guiApp), input, response)
+}
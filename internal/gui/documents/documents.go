@@ -0,0 +1,179 @@
+// Package documents indexes folders of documents and answers questions from
+// them, citing the passages the answer is based on.
+package documents
+
+import (
+	"errors"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	ollamaApi "github.com/ollama/ollama/api"
+
+	"github.com/bahelit/ctrl_plus_revise/internal/config"
+	"github.com/bahelit/ctrl_plus_revise/internal/gui/clippy"
+	"github.com/bahelit/ctrl_plus_revise/internal/gui/loading"
+	"github.com/bahelit/ctrl_plus_revise/internal/ollama"
+	"github.com/bahelit/ctrl_plus_revise/internal/store/database"
+	"github.com/bahelit/ctrl_plus_revise/pkg/docindex"
+)
+
+var topKOptions = []string{"3", "5", "8", "12"}
+
+// DocumentsWindow manages the indexed folders and asks questions about them.
+func DocumentsWindow(guiApp fyne.App, ollamaClient *ollamaApi.Client) {
+	slog.Debug("Showing Documents")
+	w := guiApp.NewWindow("Ctrl+Revise Documents")
+
+	store, err := database.NewDocumentStore()
+	if err != nil {
+		slog.Error("Failed to open the document index", "error", err)
+	}
+
+	selected := -1
+	folderList := widget.NewList(
+		func() int { return len(folders(guiApp)) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, o fyne.CanvasObject) {
+			if list := folders(guiApp); id < len(list) {
+				o.(*widget.Label).SetText(list[id])
+			}
+		})
+	folderList.OnSelected = func(id widget.ListItemID) { selected = id }
+	folderList.OnUnselected = func(widget.ListItemID) { selected = -1 }
+
+	addFolder := widget.NewButtonWithIcon("Add Folder", theme.FolderNewIcon(), func() {
+		dialog.ShowFolderOpen(func(uri fyne.ListableURI, err error) {
+			if err != nil || uri == nil {
+				return
+			}
+			list := folders(guiApp)
+			for _, folder := range list {
+				if docindex.Overlaps(folder, uri.Path()) {
+					// a document can only belong to one indexed folder
+					dialog.ShowInformation("Folder already indexed",
+						uri.Path()+" overlaps with "+folder+", remove that folder first to index this one instead.", w)
+					return
+				}
+			}
+			guiApp.Preferences().SetStringList(config.DocFoldersKey, append(list, uri.Path()))
+			folderList.Refresh()
+		}, w)
+	})
+	removeFolder := widget.NewButtonWithIcon("Remove", theme.DeleteIcon(), func() {
+		list := folders(guiApp)
+		if selected < 0 || selected >= len(list) {
+			return
+		}
+		folder := list[selected]
+		if store != nil {
+			_ = store.DeleteFolder(folder)
+		}
+		guiApp.Preferences().SetStringList(config.DocFoldersKey, slices.Delete(list, selected, selected+1))
+		folderList.UnselectAll()
+		folderList.Refresh()
+	})
+
+	model := widget.NewEntry()
+	model.SetText(ollama.EmbeddingModel(guiApp))
+	model.OnChanged = func(s string) {
+		guiApp.Preferences().SetString(config.EmbeddingModelKey, strings.TrimSpace(s))
+	}
+
+	progress := widget.NewProgressBar()
+	status := widget.NewLabel("Index the folders before asking about them, only new and changed files are indexed again")
+	status.Wrapping = fyne.TextWrapWord
+	var indexButton *widget.Button
+	indexButton = widget.NewButtonWithIcon("Index Now", theme.ViewRefreshIcon(), func() {
+		if store == nil {
+			dialog.ShowError(errors.New("the document index couldn't be opened"), w)
+			return
+		}
+		indexButton.Disable()
+		go func() {
+			defer indexButton.Enable()
+			status.SetText("Getting the embedding model " + ollama.EmbeddingModel(guiApp) + "…")
+			err := ollama.PullEmbeddingModel(guiApp, ollamaClient, func(resp ollamaApi.ProgressResponse) error {
+				if resp.Total > 0 {
+					progress.SetValue(float64(resp.Completed) / float64(resp.Total))
+				}
+				return nil
+			})
+			if err != nil {
+				status.SetText("Failed to get the embedding model: " + err.Error())
+				return
+			}
+
+			var results []string
+			for _, folder := range folders(guiApp) {
+				result := indexFolder(guiApp, ollamaClient, store, folder, func(done, total int, path string) {
+					if total > 0 {
+						progress.SetValue(float64(done) / float64(total))
+					}
+					if path != "" {
+						status.SetText("Indexing " + path)
+					}
+				})
+				for _, err := range result.errs {
+					slog.Warn("Skipped while indexing", "folder", folder, "error", err)
+				}
+				results = append(results, folder+": "+result.String())
+			}
+			status.SetText(strings.Join(results, "\n"))
+		}()
+	})
+
+	topKSelect := widget.NewSelect(topKOptions, func(s string) {
+		k, err := strconv.Atoi(s)
+		if err == nil {
+			guiApp.Preferences().SetInt(config.DocTopKKey, k)
+		}
+	})
+	topKSelect.SetSelected(strconv.Itoa(topK(guiApp)))
+
+	question := widget.NewMultiLineEntry()
+	question.SetMinRowsVisible(3)
+	question.SetPlaceHolder("Ask a question about your documents")
+	askButton := widget.NewButtonWithIcon("Ask", theme.QuestionIcon(), func() {
+		text := strings.TrimSpace(question.Text)
+		if text == "" {
+			return
+		}
+		loadingScreen := loading.LoadingScreenWithMessageAddModel(guiApp, loading.ThinkingMsg, "Searching your documents...")
+		loadingScreen.Show()
+		response, err := Ask(guiApp, ollamaClient, text)
+		loadingScreen.Hide()
+		if err != nil {
+			slog.Error("Failed to answer from documents", "error", err)
+			dialog.ShowError(err, w)
+			return
+		}
+		clippy.ShowResult(guiApp, ollamaClient, clippy.Result{Input: text, Response: &response})
+	})
+	askButton.Importance = widget.HighImportance
+
+	indexCard := widget.NewCard("Folders", "Markdown, text, code and PDF files (PDF needs pdftotext)",
+		container.NewBorder(nil,
+			container.NewVBox(
+				container.NewHBox(addFolder, removeFolder, layout.NewSpacer(), indexButton),
+				widget.NewForm(widget.NewFormItem("Embedding model", model)),
+				progress,
+				status,
+			),
+			nil, nil, folderList))
+	askCard := widget.NewCard("Ask", "",
+		container.NewBorder(nil,
+			container.NewHBox(widget.NewLabel("Passages"), topKSelect, layout.NewSpacer(), askButton),
+			nil, nil, question))
+
+	w.SetContent(container.NewBorder(nil, askCard, nil, nil, indexCard))
+	w.Resize(fyne.NewSize(640, 620))
+	w.Show()
+}
@@ -0,0 +1,150 @@
+package documents
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"fyne.io/fyne/v2"
+	ollamaApi "github.com/ollama/ollama/api"
+
+	"github.com/bahelit/ctrl_plus_revise/internal/config"
+	"github.com/bahelit/ctrl_plus_revise/internal/ollama"
+	"github.com/bahelit/ctrl_plus_revise/internal/store/database"
+	"github.com/bahelit/ctrl_plus_revise/pkg/docindex"
+)
+
+// minScore leaves out passages that have little to do with the question.
+const minScore = 0.3
+
+var (
+	errNoIndex   = errors.New("no documents are indexed, add a folder and index it first")
+	errNoMatches = errors.New("none of the indexed documents is about the question")
+)
+
+// folders returns the folders the user indexes.
+func folders(guiApp fyne.App) []string {
+	return guiApp.Preferences().StringList(config.DocFoldersKey)
+}
+
+// topK returns how many passages are sent with a question.
+func topK(guiApp fyne.App) int {
+	return guiApp.Preferences().IntWithFallback(config.DocTopKKey, docindex.DefaultTopK)
+}
+
+// summary is the outcome of indexing a folder.
+type summary struct {
+	indexed, unchanged, removed int
+	errs                        []error
+}
+
+func (s summary) String() string {
+	text := fmt.Sprintf("%d files indexed, %d unchanged, %d removed", s.indexed, s.unchanged, s.removed)
+	if len(s.errs) > 0 {
+		text += fmt.Sprintf(", %d skipped", len(s.errs))
+	}
+	return text
+}
+
+// indexFolder embeds the documents of the folder that are new or changed
+// since they were indexed and removes the ones that were deleted. progress
+// is called before every file.
+func indexFolder(guiApp fyne.App, ollamaClient *ollamaApi.Client, store *database.DocumentStore, folder string,
+	progress func(done, total int, path string)) summary {
+	var result summary
+	files, errs := docindex.Scan(os.DirFS(folder))
+	result.errs = errs
+
+	indexed, err := store.GetFiles(folder)
+	if err != nil {
+		result.errs = append(result.errs, err)
+		return result
+	}
+	model := ollama.EmbeddingModel(guiApp)
+
+	for i, file := range files {
+		path := filepath.Join(folder, filepath.FromSlash(file.Path))
+		progress(i, len(files), path)
+		old, ok := indexed[path]
+		delete(indexed, path)
+		if ok && old.Size == file.Size && old.Modified.Equal(file.ModTime) && old.Model == model {
+			result.unchanged++
+			continue
+		}
+
+		err = indexFile(guiApp, ollamaClient, store, database.IndexedFile{
+			Folder:   folder,
+			Path:     path,
+			Size:     file.Size,
+			Modified: file.ModTime,
+			Model:    model,
+		})
+		if err != nil {
+			slog.Warn("Failed to index file", "path", path, "error", err)
+			result.errs = append(result.errs, err)
+			continue
+		}
+		result.indexed++
+	}
+	progress(len(files), len(files), "")
+
+	for path := range indexed {
+		if store.DeleteFile(path) == nil {
+			result.removed++
+		}
+	}
+	return result
+}
+
+// indexFile splits the file into passages and embeds them, the file name is
+// embedded with every passage so questions naming a document find it.
+func indexFile(guiApp fyne.App, ollamaClient *ollamaApi.Client, store *database.DocumentStore, file database.IndexedFile) error {
+	text, err := docindex.ReadText(file.Path)
+	if err != nil {
+		return err
+	}
+	chunks := docindex.Split(text, docindex.ChunkChars, docindex.OverlapChars)
+	vectors := make([]docindex.Vector, len(chunks))
+	for i, chunk := range chunks {
+		vectors[i], err = ollama.Embed(guiApp, ollamaClient, filepath.Base(file.Path)+"\n"+chunk.Text)
+		if err != nil {
+			return fmt.Errorf("%s: %w", file.Path, err)
+		}
+	}
+	return store.SaveFile(file, chunks, vectors)
+}
+
+// Ask answers the question from the indexed documents, the sources the
+// answer cites are listed under it.
+func Ask(guiApp fyne.App, ollamaClient *ollamaApi.Client, question string) (ollamaApi.GenerateResponse, error) {
+	store, err := database.NewDocumentStore()
+	if err != nil {
+		return ollamaApi.GenerateResponse{}, err
+	}
+	passages, err := store.GetPassages(folders(guiApp), ollama.EmbeddingModel(guiApp))
+	if err != nil {
+		return ollamaApi.GenerateResponse{}, err
+	}
+	if len(passages) == 0 {
+		return ollamaApi.GenerateResponse{}, errNoIndex
+	}
+
+	vector, err := ollama.Embed(guiApp, ollamaClient, question)
+	if err != nil {
+		return ollamaApi.GenerateResponse{}, err
+	}
+	matches := docindex.Search(vector, passages, topK(guiApp), minScore)
+	if len(matches) == 0 {
+		return ollamaApi.GenerateResponse{}, errNoMatches
+	}
+	slog.Debug("Answering from documents", "passages", len(passages), "matches", len(matches))
+
+	response, err := ollama.AskAIWithDocuments(guiApp, ollamaClient, question, matches)
+	if err != nil {
+		return response, err
+	}
+	response.Response += "\n\n" + docindex.Sources(matches)
+	return response, nil
+}
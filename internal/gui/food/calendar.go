@@ -0,0 +1,316 @@
+package food
+
+import (
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	ollamaApi "github.com/ollama/ollama/api"
+
+	"github.com/bahelit/ctrl_plus_revise/internal/gui/loading"
+	"github.com/bahelit/ctrl_plus_revise/internal/ollama"
+	"github.com/bahelit/ctrl_plus_revise/internal/store/database"
+	"github.com/bahelit/ctrl_plus_revise/pkg/diet"
+	"github.com/bahelit/ctrl_plus_revise/pkg/household"
+	"github.com/bahelit/ctrl_plus_revise/pkg/mealplan"
+	"github.com/bahelit/ctrl_plus_revise/pkg/nutrition"
+	"github.com/bahelit/ctrl_plus_revise/pkg/recipe"
+)
+
+// calendar is the weekly meal planner, every meal of the week is a recipe
+// from the recipe book.
+type calendar struct {
+	guiApp       fyne.App
+	ollamaClient *ollamaApi.Client
+	w            fyne.Window
+	mealInfo     MealInfo
+	plans        *database.MealPlanStore
+	recipes      *database.RecipeStore
+	week         mealplan.Week
+	book         map[int64]recipe.Recipe
+	title        *widget.Label
+	nutrition    *widget.Label
+	grid         *fyne.Container
+}
+
+// MealCalendar opens the weekly meal planner, recipes are generated with the
+// preferences picked in the meal planner.
+func MealCalendar(guiApp fyne.App, ollamaClient *ollamaApi.Client, mealInfo MealInfo) {
+	slog.Debug("Showing meal calendar")
+	w := guiApp.NewWindow("Ctrl+Revise Meal Calendar")
+	w.Resize(fyne.NewSize(900, 480))
+
+	plans, err := database.NewMealPlanStore()
+	if err != nil {
+		slog.Error("Can NOT open the meal plan", "err", err.Error())
+		dialog.ShowError(err, w)
+		w.Show()
+		return
+	}
+	recipes, err := database.NewRecipeStore()
+	if err != nil {
+		slog.Error("Can NOT open the recipe book", "err", err.Error())
+		dialog.ShowError(err, w)
+		w.Show()
+		return
+	}
+	c := &calendar{
+		guiApp:       guiApp,
+		ollamaClient: ollamaClient,
+		w:            w,
+		mealInfo:     mealInfo,
+		plans:        plans,
+		recipes:      recipes,
+		title:        widget.NewLabel(""),
+		nutrition:    widget.NewLabel(""),
+		grid:         container.NewGridWithColumns(len(mealplan.Meals()) + 1),
+	}
+	c.title.TextStyle = fyne.TextStyle{Bold: true}
+
+	previous := widget.NewButtonWithIcon("", theme.NavigateBackIcon(), func() {
+		c.load(c.week.Start.AddDate(0, 0, -mealplan.DaysPerWeek))
+	})
+	next := widget.NewButtonWithIcon("", theme.NavigateNextIcon(), func() {
+		c.load(c.week.Start.AddDate(0, 0, mealplan.DaysPerWeek))
+	})
+	today := widget.NewButton("This Week", func() {
+		c.load(mealplan.WeekStart(time.Now()))
+	})
+	shopping := widget.NewButtonWithIcon("Shopping List", theme.ListIcon(), c.showShoppingList)
+	shopping.Importance = widget.SuccessImportance
+
+	top := container.NewHBox(previous, c.title, next, layout.NewSpacer(), today)
+	w.SetContent(container.NewBorder(top, container.NewVBox(c.nutrition, container.NewPadded(shopping)), nil, nil, container.NewVScroll(c.grid)))
+	c.load(mealplan.WeekStart(time.Now()))
+	w.Show()
+}
+
+// load shows the week starting on start.
+func (c *calendar) load(start time.Time) {
+	week, err := c.plans.GetWeek(start)
+	if err != nil {
+		dialog.ShowError(err, c.w)
+	}
+	c.week = week
+	c.loadBook()
+	c.refresh()
+}
+
+func (c *calendar) loadBook() {
+	recipes, err := c.recipes.GetRecipes("")
+	if err != nil {
+		slog.Error("Can NOT read the recipe book", "err", err.Error())
+	}
+	c.book = map[int64]recipe.Recipe{}
+	for _, r := range recipes {
+		c.book[*r.ID] = r
+	}
+}
+
+// refresh fills the grid with a row per day and a column per meal.
+func (c *calendar) refresh() {
+	days := c.week.Days()
+	c.title.SetText(days[0].Format("Jan 2") + " - " + days[len(days)-1].Format("Jan 2, 2006"))
+
+	c.grid.RemoveAll()
+	c.grid.Add(widget.NewLabel(""))
+	for _, meal := range mealplan.Meals() {
+		heading := widget.NewLabel(meal)
+		heading.TextStyle = fyne.TextStyle{Bold: true}
+		heading.Alignment = fyne.TextAlignCenter
+		c.grid.Add(heading)
+	}
+	for _, day := range days {
+		label := widget.NewLabel(day.Format("Mon Jan 2"))
+		if sameDate(day, time.Now()) {
+			label.TextStyle = fyne.TextStyle{Bold: true}
+		}
+		c.grid.Add(label)
+		for _, meal := range mealplan.Meals() {
+			text := "+"
+			if entry, ok := c.week.Entry(day, meal); ok {
+				text = entryLabel(entry)
+			}
+			c.grid.Add(widget.NewButton(text, func() { c.editMeal(day, meal) }))
+		}
+	}
+	c.grid.Refresh()
+	c.nutrition.SetText(weekNutrition(c.week, c.book))
+}
+
+// weekNutrition sums the nutrition of a serving of every planned meal.
+func weekNutrition(week mealplan.Week, book map[int64]recipe.Recipe) string {
+	recipes := week.Recipes(book)
+	if len(recipes) == 0 {
+		return ""
+	}
+	total := nutrition.PerPerson(recipes)
+	return "Per person this week: " + total.String() + "\nDaily average: " + total.Scale(1.0/mealplan.DaysPerWeek).String()
+}
+
+func entryLabel(entry mealplan.Entry) string {
+	if entry.Servings > 0 {
+		return entry.Title + " (" + strconv.Itoa(entry.Servings) + ")"
+	}
+	return entry.Title
+}
+
+func sameDate(a, b time.Time) bool {
+	return a.Format(time.DateOnly) == b.Format(time.DateOnly)
+}
+
+// editMeal picks a recipe from the book for the meal or generates one.
+func (c *calendar) editMeal(day time.Time, meal string) {
+	var ids []int64
+	for id := range c.book {
+		ids = append(ids, id)
+	}
+	slices.SortFunc(ids, func(a, b int64) int { return strings.Compare(c.book[a].Title, c.book[b].Title) })
+	var titles []string
+	for _, id := range ids {
+		titles = append(titles, c.book[id].Title)
+	}
+
+	recipes := widget.NewSelect(titles, nil)
+	recipes.PlaceHolder = "Pick a recipe from the recipe book"
+	servings := widget.NewSelect(servingOptions(), nil)
+	servings.PlaceHolder = "As in the recipe"
+	members := loadHousehold(c.guiApp)
+	eaters := widget.NewCheckGroup(members.Names(), func(names []string) {
+		if len(names) > 0 {
+			servings.SetSelected(strconv.Itoa(min(members.Find(names).Servings(), maxServings)))
+		}
+	})
+	eaters.Horizontal = true
+
+	entry, planned := c.week.Entry(day, meal)
+	eaters.SetSelected(c.mealInfo.Eaters.Names())
+	if planned {
+		eaters.SetSelected(entry.Eaters)
+		for i, id := range ids {
+			if id == entry.RecipeID {
+				recipes.SetSelectedIndex(i)
+			}
+		}
+		if entry.Servings > 0 {
+			servings.SetSelected(strconv.Itoa(entry.Servings))
+		}
+	}
+
+	var d dialog.Dialog
+	generate := widget.NewButtonWithIcon("Generate a Recipe", theme.ComputerIcon(), func() {
+		d.Hide()
+		c.generate(day, meal, selectedServings(servings), members.Find(eaters.Selected))
+	})
+	remove := widget.NewButtonWithIcon("Clear", theme.DeleteIcon(), func() {
+		d.Hide()
+		if err := c.plans.DeleteEntry(day, meal); err != nil {
+			dialog.ShowError(err, c.w)
+			return
+		}
+		c.week.Remove(day, meal)
+		c.refresh()
+	})
+	if !planned {
+		remove.Disable()
+	}
+	form := widget.NewForm(widget.NewFormItem("Recipe", recipes))
+	if len(members) > 0 {
+		form.Append("Who's eating", eaters)
+	}
+	form.Append("Servings", servings)
+	content := container.NewVBox(form,
+		container.NewHBox(generate, layout.NewSpacer(), remove))
+	d = dialog.NewCustomConfirm(meal+" on "+day.Format("Monday, Jan 2"), "Plan", "Cancel", content, func(ok bool) {
+		if !ok || recipes.SelectedIndex() < 0 {
+			return
+		}
+		id := ids[recipes.SelectedIndex()]
+		c.plan(mealplan.Entry{Date: day, Meal: meal, RecipeID: id, Title: c.book[id].Title,
+			Servings: selectedServings(servings), Eaters: eaters.Selected})
+		info := c.mealInfo
+		info.Eaters = members.Find(eaters.Selected)
+		c.warn(c.book[id], info.restrictions())
+	}, c.w)
+	d.Resize(fyne.NewSize(520, 260))
+	d.Show()
+}
+
+// generate asks the AI for a recipe for the meal, it is saved to the recipe
+// book and planned.
+func (c *calendar) generate(day time.Time, meal string, servings int, eaters household.Members) {
+	info := c.mealInfo
+	info.Meal = meal
+	info.Eaters = eaters
+	if servings > 0 {
+		info.Consumers = strconv.Itoa(servings)
+	}
+	info.Inventory = loadInventory()
+	prompt := createMealPrompt(info)
+
+	loadingScreen := loading.LoadingScreenWithMessageAddModel(c.guiApp, loading.ThinkingMsg, "Preparing recipe")
+	loadingScreen.Show()
+	r, err := ollama.AskAIForData(c.guiApp, c.ollamaClient, ollama.MakeRecipe, prompt)
+	loadingScreen.Hide()
+	if err != nil {
+		slog.Error("Failed to generate a recipe", "error", err)
+		dialog.ShowError(err, c.w)
+		return
+	}
+	if err = c.recipes.SaveRecipe(&r); err != nil {
+		dialog.ShowError(err, c.w)
+		return
+	}
+	c.book[*r.ID] = r
+	c.plan(mealplan.Entry{Date: day, Meal: meal, RecipeID: *r.ID, Title: r.Title, Servings: servings, Eaters: eaters.Names()})
+	c.warn(r, info.restrictions())
+}
+
+// warn shows the ingredients of the planned recipe that break the restrictions.
+func (c *calendar) warn(r recipe.Recipe, restrictions diet.Restrictions) {
+	if flags := recipeFlags(r, restrictions); len(flags) > 0 {
+		dialog.ShowCustom(r.Title, "OK", flagsWarning(flags), c.w)
+	}
+}
+
+func (c *calendar) plan(entry mealplan.Entry) {
+	if err := c.plans.SaveEntry(entry); err != nil {
+		dialog.ShowError(err, c.w)
+		return
+	}
+	c.week.Set(entry)
+	c.refresh()
+}
+
+// showShoppingList adds up the ingredients of the week, minus what is in the
+// pantry.
+func (c *calendar) showShoppingList() {
+	c.loadBook()
+	items := mealplan.List(c.week.Recipes(c.book), loadInventory(), time.Now())
+	if len(items) == 0 {
+		dialog.ShowInformation("Shopping List", "Nothing to buy, plan some meals or check the pantry.", c.w)
+		return
+	}
+	shoppingListWindow(c.guiApp, items)
+}
+
+func servingOptions() []string {
+	var options []string
+	for i := 1; i <= maxServings; i++ {
+		options = append(options, strconv.Itoa(i))
+	}
+	return options
+}
+
+func selectedServings(s *widget.Select) int {
+	servings, _ := strconv.Atoi(s.Selected)
+	return servings
+}
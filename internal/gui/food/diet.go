@@ -0,0 +1,118 @@
+package food
+
+import (
+	"encoding/json"
+	"log/slog"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/bahelit/ctrl_plus_revise/internal/config"
+	"github.com/bahelit/ctrl_plus_revise/pkg/diet"
+	"github.com/bahelit/ctrl_plus_revise/pkg/recipe"
+)
+
+// loadRestrictions returns the household's allergies, diets and dislikes.
+func loadRestrictions(guiApp fyne.App) diet.Restrictions {
+	var restrictions diet.Restrictions
+	saved := guiApp.Preferences().String(config.DietKey)
+	if saved == "" {
+		return restrictions
+	}
+	if err := json.Unmarshal([]byte(saved), &restrictions); err != nil {
+		slog.Error("Failed to read dietary restrictions", "error", err)
+	}
+	return restrictions
+}
+
+func saveRestrictions(guiApp fyne.App, restrictions diet.Restrictions) {
+	data, err := json.Marshal(restrictions)
+	if err != nil {
+		slog.Error("Failed to save dietary restrictions", "error", err)
+		return
+	}
+	guiApp.Preferences().SetString(config.DietKey, string(data))
+}
+
+// restrictionsCards edits the household's allergies, diets and dislikes,
+// onChange is called with every change after it is saved.
+func restrictionsCards(guiApp fyne.App, onChange func(diet.Restrictions)) fyne.CanvasObject {
+	restrictions := loadRestrictions(guiApp)
+	changed := func() {
+		saveRestrictions(guiApp, restrictions)
+		onChange(restrictions)
+	}
+
+	allergyCheck := widget.NewCheckGroup(diet.Allergies(), nil)
+	allergyCheck.SetSelected(restrictions.Allergies)
+	allergyCheck.OnChanged = func(allergies []string) {
+		slog.Debug("Food allergies", "allergy", allergies)
+		restrictions.Allergies = allergies
+		changed()
+	}
+	dietCheck := widget.NewCheckGroup(diet.Diets(), nil)
+	dietCheck.SetSelected(restrictions.Diets)
+	dietCheck.OnChanged = func(diets []string) {
+		slog.Debug("Diets", "diet", diets)
+		restrictions.Diets = diets
+		changed()
+	}
+	dislikes := widget.NewEntry()
+	dislikes.SetPlaceHolder("olives, mushrooms, cilantro")
+	dislikes.SetText(strings.Join(restrictions.Dislikes, ", "))
+	dislikes.OnChanged = func(s string) {
+		restrictions.Dislikes = diet.ParseList(s)
+		changed()
+	}
+
+	return container.NewVBox(
+		widget.NewCard("Allergy", "Got any food allergies? Recipes using them are flagged", allergyCheck),
+		widget.NewCard("Diet", "Every dish follows these diets", dietCheck),
+		widget.NewCard("Dislikes", "Ingredients to leave out, separated by commas", dislikes),
+	)
+}
+
+// recipeFlags checks the ingredients of the recipe against the restrictions.
+func recipeFlags(r recipe.Recipe, restrictions diet.Restrictions) []diet.Flag {
+	var ingredients []string
+	for _, ingredient := range r.Ingredients {
+		ingredients = append(ingredients, ingredient.Name)
+	}
+	return restrictions.Check(ingredients)
+}
+
+// textFlags checks every line of a recipe written as text, it can't tell the
+// ingredients from the instructions so it errs on the side of flagging.
+func textFlags(text string, restrictions diet.Restrictions) []diet.Flag {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(strings.TrimLeft(line, "-*#0123456789. ")); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return restrictions.Check(lines)
+}
+
+// flagsWarning lists what breaks the restrictions above the recipe, it is
+// hidden when nothing does.
+func flagsWarning(flags []diet.Flag) fyne.CanvasObject {
+	if len(flags) == 0 {
+		return container.NewVBox()
+	}
+	title := "Check this recipe before cooking"
+	if diet.HasAllergen(flags) {
+		title = "Warning: this recipe may contain allergens"
+	}
+	heading := widget.NewLabelWithStyle(title, fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	var lines []string
+	for _, f := range flags {
+		lines = append(lines, "- "+f.Ingredient+": "+f.Reason)
+	}
+	details := widget.NewLabel(strings.Join(lines, "\n"))
+	details.Wrapping = fyne.TextWrapWord
+	return widget.NewCard("", "", container.NewBorder(nil, nil, widget.NewIcon(theme.WarningIcon()), nil,
+		container.NewVBox(heading, details)))
+}
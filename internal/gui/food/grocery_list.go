@@ -0,0 +1,117 @@
+package food
+
+import (
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	ollamaApi "github.com/ollama/ollama/api"
+
+	"github.com/bahelit/ctrl_plus_revise/internal/gui/loading"
+	"github.com/bahelit/ctrl_plus_revise/internal/ollama"
+	"github.com/bahelit/ctrl_plus_revise/pkg/diet"
+	"github.com/bahelit/ctrl_plus_revise/pkg/mealplan"
+)
+
+// askForGroceryList asks for the grocery list as data and shows it as a
+// checklist, models that can't produce valid JSON get the Markdown list.
+func askForGroceryList(guiApp fyne.App, w fyne.Window, tabs *container.AppTabs, ollamaClient *ollamaApi.Client,
+	restrictions diet.Restrictions, prompt, msg string) {
+	loadingScreen := loading.LoadingScreenWithMessageAddModel(guiApp, loading.ThinkingMsg, msg)
+	loadingScreen.Show()
+
+	list, err := ollama.AskAIForData(guiApp, ollamaClient, ollama.MakeGroceryList, prompt)
+	if err == nil && len(list.Items) > 0 {
+		loadingScreen.Hide()
+		groceryListPopUp(w, tabs, restrictions, list)
+		return
+	}
+	slog.Warn("Failed to get the grocery list as data, asking for Markdown", "error", err)
+
+	prompt = addMarkdownFormattingToRecipe(prompt)
+	generated, err := ollama.AskAI(guiApp, ollamaClient, prompt)
+	loadingScreen.Hide()
+	if err != nil {
+		slog.Error("Failed to ask AI", "error", err)
+		return
+	}
+	recipePopUp(guiApp, w, tabs, ollamaClient, restrictions, prompt, &generated)
+}
+
+// groceryListPopUp shows the grocery list grouped by aisle with a check box
+// for every item.
+func groceryListPopUp(w fyne.Window, tabs *container.AppTabs, restrictions diet.Restrictions, list ollama.GroceryList) {
+	var names []string
+	byCategory := map[string][]ollama.GroceryItem{}
+	for _, item := range list.Items {
+		names = append(names, item.Name)
+		category := groceryCategory(item.Category)
+		byCategory[category] = append(byCategory[category], item)
+	}
+
+	content := container.NewVBox()
+	for _, category := range mealplan.Sections() {
+		items := byCategory[category]
+		if len(items) == 0 {
+			continue
+		}
+		checks := container.NewVBox()
+		for _, item := range items {
+			checks.Add(widget.NewCheck(groceryItemText(item), nil))
+		}
+		content.Add(widget.NewCard(category, "", checks))
+	}
+	if len(list.Tips) > 0 {
+		tips := widget.NewLabel("- " + strings.Join(list.Tips, "\n- "))
+		tips.Wrapping = fyne.TextWrapWord
+		content.Add(widget.NewCard("Tips", "", tips))
+	}
+
+	tabCount := len(tabs.Items)
+	var tab *container.TabItem
+	buttons := container.NewPadded(
+		widget.NewButtonWithIcon("Close List", theme.ContentClearIcon(), func() {
+			tabs.Remove(tab)
+		}),
+		widget.NewButtonWithIcon("Copy the List to Clipboard", theme.ContentCopyIcon(), func() {
+			w.Clipboard().SetContent(groceryListText(list))
+		}),
+	)
+	buttons.Layout = layout.NewAdaptiveGridLayout(2)
+	tab = container.NewTabItem("Grocery List #"+strconv.Itoa(tabCount),
+		container.NewBorder(flagsWarning(restrictions.Check(names)), container.NewVBox(buttons, footer()), nil, nil, container.NewVScroll(content)))
+	tabs.Append(tab)
+	tabs.SelectIndex(tabCount)
+}
+
+func groceryItemText(item ollama.GroceryItem) string {
+	if item.Quantity == "" {
+		return item.Name
+	}
+	return item.Name + " (" + item.Quantity + ")"
+}
+
+// groceryListText is the list as plain text for pasting into a notes app.
+func groceryListText(list ollama.GroceryList) string {
+	var b strings.Builder
+	for _, item := range list.Items {
+		b.WriteString("- " + groceryItemText(item) + "\n")
+	}
+	return b.String()
+}
+
+// groceryCategory returns the aisle of the list matching the category the
+// model picked, the schema allows any case.
+func groceryCategory(category string) string {
+	for _, c := range mealplan.Sections() {
+		if strings.EqualFold(c, strings.TrimSpace(category)) {
+			return c
+		}
+	}
+	return "Other"
+}
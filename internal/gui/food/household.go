@@ -0,0 +1,233 @@
+package food
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/bahelit/ctrl_plus_revise/internal/config"
+	"github.com/bahelit/ctrl_plus_revise/pkg/diet"
+	"github.com/bahelit/ctrl_plus_revise/pkg/household"
+)
+
+// loadHousehold returns the members of the household.
+func loadHousehold(guiApp fyne.App) household.Members {
+	var members household.Members
+	saved := guiApp.Preferences().String(config.HouseholdKey)
+	if saved == "" {
+		return members
+	}
+	if err := json.Unmarshal([]byte(saved), &members); err != nil {
+		slog.Error("Failed to read household", "error", err)
+	}
+	return members
+}
+
+func saveHousehold(guiApp fyne.App, members household.Members) error {
+	data, err := json.Marshal(members)
+	if err != nil {
+		return err
+	}
+	guiApp.Preferences().SetString(config.HouseholdKey, string(data))
+	return nil
+}
+
+// eatersPicker picks the members of the household eating the meal, onChange
+// is called with them, or nothing when nobody is picked.
+func eatersPicker(guiApp fyne.App, onChange func(household.Members)) fyne.CanvasObject {
+	members := loadHousehold(guiApp)
+	eaters := widget.NewCheckGroup(members.Names(), nil)
+	eaters.Horizontal = true
+	eaters.SetSelected(guiApp.Preferences().StringList(config.EatersKey))
+	eaters.OnChanged = func(names []string) {
+		guiApp.Preferences().SetStringList(config.EatersKey, names)
+		onChange(members.Find(names))
+	}
+
+	edit := widget.NewButtonWithIcon("Household", theme.AccountIcon(), func() {
+		HouseholdWindow(guiApp, func() {
+			members = loadHousehold(guiApp)
+			eaters.Options = members.Names()
+			eaters.SetSelected(members.Find(eaters.Selected).Names())
+			eaters.Refresh()
+			onChange(members.Find(eaters.Selected))
+		})
+	})
+	onChange(members.Find(eaters.Selected))
+	return container.NewBorder(nil, nil, widget.NewLabel("Who's eating?"), edit, eaters)
+}
+
+// HouseholdWindow edits the members of the household, onChange is called
+// after they are saved.
+func HouseholdWindow(guiApp fyne.App, onChange func()) {
+	slog.Debug("Showing household")
+	w := guiApp.NewWindow("Ctrl+Revise Household")
+	w.Resize(fyne.NewSize(760, 560))
+
+	members := loadHousehold(guiApp)
+	editor := newMemberEditor()
+	selected := -1
+	list := widget.NewList(
+		func() int { return len(members) },
+		func() fyne.CanvasObject { return widget.NewLabel("Template Member Name (adult)") },
+		func(id widget.ListItemID, o fyne.CanvasObject) {
+			o.(*widget.Label).SetText(members[id].Describe())
+		})
+	list.OnSelected = func(id widget.ListItemID) {
+		selected = id
+		editor.edit(members[id])
+	}
+
+	save := func() bool {
+		if err := saveHousehold(guiApp, members); err != nil {
+			dialog.ShowError(err, w)
+			return false
+		}
+		list.Refresh()
+		if onChange != nil {
+			onChange()
+		}
+		return true
+	}
+	saveButton := widget.NewButtonWithIcon("Save", theme.DocumentSaveIcon(), func() {
+		member, err := editor.member()
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		for i, m := range members {
+			if i != selected && strings.EqualFold(m.Name, member.Name) {
+				dialog.ShowError(errors.New(member.Name+" is already in the household"), w)
+				return
+			}
+		}
+		if selected < 0 {
+			members = append(members, member)
+			selected = len(members) - 1
+		} else {
+			members[selected] = member
+		}
+		if save() {
+			list.Select(selected)
+		}
+	})
+	newButton := widget.NewButtonWithIcon("New", theme.ContentAddIcon(), func() {
+		list.UnselectAll()
+		selected = -1
+		editor.edit(household.Member{AgeGroup: household.Adult, Appetite: household.Normal})
+	})
+	deleteButton := widget.NewButtonWithIcon("Delete", theme.DeleteIcon(), func() {
+		if selected < 0 {
+			return
+		}
+		members = slices.Delete(members, selected, selected+1)
+		list.UnselectAll()
+		selected = -1
+		editor.edit(household.Member{AgeGroup: household.Adult, Appetite: household.Normal})
+		save()
+	})
+
+	buttons := container.NewHBox(newButton, deleteButton, layout.NewSpacer(), saveButton)
+	split := container.NewHSplit(list, container.NewBorder(nil, buttons, nil, nil, container.NewVScroll(editor.content)))
+	split.Offset = 0.3
+	w.SetContent(split)
+	editor.edit(household.Member{AgeGroup: household.Adult, Appetite: household.Normal})
+	w.Show()
+}
+
+// memberEditor is the form for a member of the household.
+type memberEditor struct {
+	name      *widget.Entry
+	ageGroup  *widget.Select
+	appetite  *widget.Select
+	allergies *widget.CheckGroup
+	diets     *widget.CheckGroup
+	dislikes  *widget.Entry
+	likes     *widget.Entry
+	portion   *widget.Label
+	content   fyne.CanvasObject
+}
+
+func newMemberEditor() *memberEditor {
+	e := &memberEditor{
+		name:      widget.NewEntry(),
+		allergies: widget.NewCheckGroup(diet.Allergies(), nil),
+		diets:     widget.NewCheckGroup(diet.Diets(), nil),
+		dislikes:  widget.NewEntry(),
+		likes:     widget.NewEntry(),
+		portion:   widget.NewLabel(""),
+	}
+	var ages, appetites []string
+	for _, a := range household.AgeGroups() {
+		ages = append(ages, string(a))
+	}
+	for _, a := range household.Appetites() {
+		appetites = append(appetites, string(a))
+	}
+	e.ageGroup = widget.NewSelect(ages, func(string) { e.showPortion() })
+	e.appetite = widget.NewSelect(appetites, func(string) { e.showPortion() })
+	e.allergies.Horizontal = true
+	e.diets.Horizontal = true
+	e.name.SetPlaceHolder("Sam")
+	e.dislikes.SetPlaceHolder("olives, mushrooms")
+	e.likes.SetPlaceHolder("pasta, spicy food")
+
+	e.content = container.NewVBox(
+		widget.NewForm(
+			widget.NewFormItem("Name", e.name),
+			widget.NewFormItem("Age", e.ageGroup),
+			widget.NewFormItem("Appetite", e.appetite),
+			widget.NewFormItem("Portion", e.portion),
+			widget.NewFormItem("Likes", e.likes),
+			widget.NewFormItem("Dislikes", e.dislikes),
+		),
+		widget.NewCard("", "Allergies", e.allergies),
+		widget.NewCard("", "Diets", e.diets),
+	)
+	return e
+}
+
+func (e *memberEditor) edit(m household.Member) {
+	e.name.SetText(m.Name)
+	e.ageGroup.SetSelected(string(m.AgeGroup))
+	e.appetite.SetSelected(string(m.Appetite))
+	e.allergies.SetSelected(m.Restrictions.Allergies)
+	e.diets.SetSelected(m.Restrictions.Diets)
+	e.dislikes.SetText(strings.Join(m.Restrictions.Dislikes, ", "))
+	e.likes.SetText(strings.Join(m.Likes, ", "))
+	e.showPortion()
+}
+
+func (e *memberEditor) showPortion() {
+	m := household.Member{AgeGroup: household.AgeGroup(e.ageGroup.Selected), Appetite: household.Appetite(e.appetite.Selected)}
+	e.portion.SetText(strconv.FormatFloat(math.Round(m.Portion()*100)/100, 'f', -1, 64) + " of an adult serving")
+}
+
+func (e *memberEditor) member() (household.Member, error) {
+	m := household.Member{
+		Name:     strings.TrimSpace(e.name.Text),
+		AgeGroup: household.AgeGroup(e.ageGroup.Selected),
+		Appetite: household.Appetite(e.appetite.Selected),
+		Restrictions: diet.Restrictions{
+			Allergies: e.allergies.Selected,
+			Diets:     e.diets.Selected,
+			Dislikes:  diet.ParseList(e.dislikes.Text),
+		},
+		Likes: diet.ParseList(e.likes.Text),
+	}
+	if m.Name == "" {
+		return m, errors.New("every member needs a name")
+	}
+	return m, nil
+}
@@ -21,6 +21,9 @@ import (
 	"github.com/bahelit/ctrl_plus_revise/internal/gui/loading"
 	"github.com/bahelit/ctrl_plus_revise/internal/gui/shortcuts"
 	"github.com/bahelit/ctrl_plus_revise/internal/ollama"
+	"github.com/bahelit/ctrl_plus_revise/pkg/diet"
+	"github.com/bahelit/ctrl_plus_revise/pkg/household"
+	"github.com/bahelit/ctrl_plus_revise/pkg/pantry"
 	ollamaApi "github.com/ollama/ollama/api"
 )
 
@@ -35,7 +38,17 @@ type MealInfo struct {
 	Pantry    []string
 	Veggies   []string
 	Protein   []string
-	Allergies []string
+	Inventory pantry.Inventory
+	// Restrictions are the allergies, diets and dislikes of the household.
+	Restrictions diet.Restrictions
+	// Eaters are the members of the household eating the meal.
+	Eaters household.Members
+}
+
+// restrictions returns the restrictions of the household and of everybody
+// eating the meal.
+func (m MealInfo) restrictions() diet.Restrictions {
+	return m.Restrictions.Merge(m.Eaters.Restrictions())
 }
 
 func MealPlanner(guiApp fyne.App, ollamaClient *ollamaApi.Client) {
@@ -66,13 +79,27 @@ func MealPlanner(guiApp fyne.App, ollamaClient *ollamaApi.Client) {
 	consumersValidated.SetPlaceHolder("How many people are eating?")
 	consumersValidated.OnSubmitted = func(s string) {
 		guiApp.Preferences().SetString(config.ConsumersKey, s)
+		mealInfo.Consumers = s
 	}
 	consumers := guiApp.Preferences().String(config.ConsumersKey)
 	if consumers != "" {
 		consumersValidated.SetText(consumers)
 	}
+	mealInfo.Consumers = consumersValidated.Text
+	eaters := eatersPicker(guiApp, func(members household.Members) {
+		mealInfo.Eaters = members
+		if len(members) == 0 {
+			consumersValidated.Enable()
+			mealInfo.Consumers = guiApp.Preferences().String(config.ConsumersKey)
+			consumersValidated.SetText(mealInfo.Consumers)
+			return
+		}
+		mealInfo.Consumers = strconv.Itoa(members.Servings())
+		consumersValidated.SetText(mealInfo.Consumers)
+		consumersValidated.Disable()
+	})
 
-	mealAndCount := container.NewVBox(meal, consumersValidated)
+	mealAndCount := container.NewVBox(meal, eaters, consumersValidated)
 	mealCard := widget.NewCard("Meal", "Breakfast or dinner, or how about breakfast for dinner?", mealAndCount)
 
 	flavor := widget.NewRadioGroup(getThemes(), func(s string) {
@@ -135,10 +162,9 @@ func MealPlanner(guiApp fyne.App, ollamaClient *ollamaApi.Client) {
 	proteinCheck.SetSelected(guiApp.Preferences().StringList(config.ProteinKey))
 	proteinCard := widget.NewCard("Protein", "Meat or meat substitutes", proteinCheck)
 
-	allergyCheck := widget.NewCheckGroup(getAllergies(), func(strings []string) {
-		slog.Debug("Food allergies", "allergy", strings)
+	dietCards := restrictionsCards(guiApp, func(restrictions diet.Restrictions) {
+		mealInfo.Restrictions = restrictions
 	})
-	allergyCard := widget.NewCard("Allergy", "Got any food allergies?", allergyCheck)
 
 	mealTab := container.NewTabItem("Meal", container.NewVScroll(mealCard))
 	themeTab := container.NewTabItem("Theme", container.NewVScroll(flavorCard))
@@ -149,11 +175,10 @@ func MealPlanner(guiApp fyne.App, ollamaClient *ollamaApi.Client) {
 	pantryTab := container.NewTabItem("Pantry", container.NewVScroll(pantryCard))
 	veggiesTab := container.NewTabItem("Vegetables", container.NewVScroll(veggieCard))
 	proteinTab := container.NewTabItem("Protein", container.NewVScroll(proteinCard))
-	allergyTab := container.NewTabItem("Allergy", container.NewVScroll(allergyCard))
-	verticalTabs := container.NewAppTabs(mealTab, themeTab, cookwareTab, herbsTab, veggiesTab, dairyTab, pantryTab, proteinTab, freezerTab, allergyTab)
+	dietTab := container.NewTabItem("Diet & Allergy", container.NewVScroll(dietCards))
+	verticalTabs := container.NewAppTabs(mealTab, themeTab, cookwareTab, herbsTab, veggiesTab, dairyTab, pantryTab, proteinTab, freezerTab, dietTab)
 	verticalTabs.SetTabLocation(container.TabLocationLeading)
 
-	mealInfo.Consumers = consumersValidated.Text
 	mealInfo.Cookware = shuffleStringArray(cookWare.Selected)
 	mealInfo.Dairy = shuffleStringArray(dairyCheck.Selected)
 	mealInfo.Freezer = shuffleStringArray(freezerCheck.Selected)
@@ -161,28 +186,17 @@ func MealPlanner(guiApp fyne.App, ollamaClient *ollamaApi.Client) {
 	mealInfo.Pantry = shuffleStringArray(pantryCheck.Selected)
 	mealInfo.Veggies = shuffleStringArray(veggieCheck.Selected)
 	mealInfo.Protein = shuffleStringArray(proteinCheck.Selected)
-	mealInfo.Allergies = shuffleStringArray(allergyCheck.Selected)
+	mealInfo.Restrictions = loadRestrictions(guiApp)
 
 	suggest := widget.NewButton("Suggest a Single Meal", func() {
+		mealInfo.Inventory = loadInventory()
 		recipe := createMealPrompt(mealInfo)
 		slog.Info("Recipe for single meal", "PROMPT", recipe)
-		loadingScreen := loading.LoadingScreenWithMessageAddModel(guiApp, loading.ThinkingMsg,
-			"Preparing recipe")
-		loadingScreen.Show()
-
-		recipe = addMarkdownFormattingToRecipe(recipe)
-
-		generated, err := ollama.AskAI(guiApp, ollamaClient, recipe)
-		if err != nil {
-			slog.Error("Failed to ask AI", "error", err)
-			loadingScreen.Hide()
-			return
-		}
-		loadingScreen.Hide()
-		recipePopUp(guiApp, &tabs, ollamaClient, recipe, &generated)
+		askForRecipe(guiApp, mealPlanner, &tabs, ollamaClient, mealInfo.restrictions(), recipe, "Preparing recipe")
 	})
 	suggest.Importance = widget.HighImportance
 	suggestPrep := widget.NewButton("Prep Multiple Meals", func() {
+		mealInfo.Inventory = loadInventory()
 		recipe := createMealPrepPrompt(mealInfo)
 		slog.Info("Meal prep plan", "PROMPT", recipe)
 		loadingScreen := loading.LoadingScreenWithMessageAddModel(guiApp, loading.ThinkingMsg,
@@ -198,45 +212,21 @@ func MealPlanner(guiApp fyne.App, ollamaClient *ollamaApi.Client) {
 			return
 		}
 		loadingScreen.Hide()
-		recipePopUp(guiApp, &tabs, ollamaClient, recipe, &generated)
+		recipePopUp(guiApp, mealPlanner, &tabs, ollamaClient, mealInfo.restrictions(), recipe, &generated)
 	})
 	suggestPrep.Importance = widget.HighImportance
 	groceryList := widget.NewButton("Create a Grocery List", func() {
+		mealInfo.Inventory = loadInventory()
 		recipe := createGroceryListPrompt(mealInfo)
 		slog.Info("Grocery List", "PROMPT", recipe)
-		loadingScreen := loading.LoadingScreenWithMessageAddModel(guiApp, loading.ThinkingMsg,
-			"Creating grocery list")
-		loadingScreen.Show()
-
-		recipe = addMarkdownFormattingToRecipe(recipe)
-
-		generated, err := ollama.AskAI(guiApp, ollamaClient, recipe)
-		if err != nil {
-			slog.Error("Failed to ask AI", "error", err)
-			loadingScreen.Hide()
-			return
-		}
-		loadingScreen.Hide()
-		recipePopUp(guiApp, &tabs, ollamaClient, recipe, &generated)
+		askForGroceryList(guiApp, mealPlanner, &tabs, ollamaClient, mealInfo.restrictions(), recipe, "Creating grocery list")
 	})
 	groceryList.Importance = widget.SuccessImportance
 	budgetFriendlyGroceryList := widget.NewButton("Create a Budget Friendly Grocery List", func() {
+		mealInfo.Inventory = loadInventory()
 		recipe := createBudgetFriendlyGroceryListPrompt(mealInfo)
 		slog.Info("Budget Friendly Shopping plan", "PROMPT", recipe)
-		loadingScreen := loading.LoadingScreenWithMessageAddModel(guiApp, loading.ThinkingMsg,
-			"Creating budget friendly grocery list")
-		loadingScreen.Show()
-
-		recipe = addMarkdownFormattingToRecipe(recipe)
-
-		generated, err := ollama.AskAI(guiApp, ollamaClient, recipe)
-		if err != nil {
-			slog.Error("Failed to ask AI", "error", err)
-			loadingScreen.Hide()
-			return
-		}
-		loadingScreen.Hide()
-		recipePopUp(guiApp, &tabs, ollamaClient, recipe, &generated)
+		askForGroceryList(guiApp, mealPlanner, &tabs, ollamaClient, mealInfo.restrictions(), recipe, "Creating budget friendly grocery list")
 	})
 	budgetFriendlyGroceryList.Importance = widget.SuccessImportance
 
@@ -246,8 +236,18 @@ func MealPlanner(guiApp fyne.App, ollamaClient *ollamaApi.Client) {
 		container.NewPadded(suggestPrep),
 		container.NewPadded(budgetFriendlyGroceryList))
 	action.Layout = layout.NewAdaptiveGridLayout(2)
+	inventory := widget.NewButtonWithIcon("Pantry Inventory", theme.StorageIcon(), func() {
+		PantryWindow(guiApp)
+	})
+	recipeBook := widget.NewButtonWithIcon("Recipe Book", theme.FolderOpenIcon(), func() {
+		RecipeBook(guiApp)
+	})
+	calendar := widget.NewButtonWithIcon("Meal Calendar", theme.GridIcon(), func() {
+		MealCalendar(guiApp, ollamaClient, mealInfo)
+	})
+	library := container.NewGridWithColumns(3, container.NewPadded(calendar), container.NewPadded(inventory), container.NewPadded(recipeBook))
 
-	boarderLayout := container.NewBorder(nil, action, nil, nil, verticalTabs)
+	boarderLayout := container.NewBorder(nil, container.NewVBox(action, library), nil, nil, verticalTabs)
 	mealPlannerTab := container.NewTabItem("Meal Planner", boarderLayout)
 	tabs.Append(mealPlannerTab)
 	tabContainer := container.NewBorder(topText, nil, nil, nil, &tabs)
@@ -285,85 +285,43 @@ func addMarkdownFormattingToRecipe(recipe string) string {
 	return recipe + ". " + "format: markdown"
 }
 
-func recipePopUp(guiApp fyne.App, tabs *container.AppTabs, ollamaClient *ollamaApi.Client, recipe string, response *ollamaApi.GenerateResponse) {
+func recipePopUp(guiApp fyne.App, w fyne.Window, tabs *container.AppTabs, ollamaClient *ollamaApi.Client,
+	restrictions diet.Restrictions, recipe string, response *ollamaApi.GenerateResponse) {
 	generatedText1 := widget.NewRichTextFromMarkdown(response.Response)
 	generatedText1.Wrapping = fyne.TextWrapWord
 
 	vbox := container.NewVScroll(generatedText1)
 	tabCount := len(tabs.Items)
 
-	buttons := container.NewPadded(
-		widget.NewButton("Something Different", func() {
-			loadingScreen := loading.LoadingScreenWithMessageAddModel(guiApp, loading.ThinkingMsg,
-				"Something Different...")
-			loadingScreen.Show()
-			reGenerated, err := ollama.AskAiWithStringAndContext(guiApp, ollamaClient, response.Context,
-				"That doesn't sound good, how about something else please.")
-			if err != nil {
-				slog.Error("Failed to re-generate", "error", err)
-				return
-			}
-			shortcuts.LastClipboardContent = sha256.Sum256([]byte(response.Response))
-			loadingScreen.Hide()
-			recipePopUp(guiApp, tabs, ollamaClient, recipe, &reGenerated)
-		}),
-		widget.NewButton("Something Simple and Quick", func() {
-			loadingScreen := loading.LoadingScreenWithMessageAddModel(guiApp, loading.ThinkingMsg,
-				"Something Simple and Quick...")
-			loadingScreen.Show()
-			reGenerated, err := ollama.AskAiWithStringAndContext(guiApp, ollamaClient, response.Context,
-				"That doesn't sound good, how about something that's simple and quick to put together please.")
-			if err != nil {
-				slog.Error("Failed to re-generate", "error", err)
-				return
-			}
-			shortcuts.LastClipboardContent = sha256.Sum256([]byte(response.Response))
-			loadingScreen.Hide()
-			recipePopUp(guiApp, tabs, ollamaClient, recipe, &reGenerated)
-		}),
-		widget.NewButton("Something Healthy", func() {
-			loadingScreen := loading.LoadingScreenWithMessageAddModel(guiApp, loading.ThinkingMsg,
-				"Something Healthy...")
-			loadingScreen.Show()
-			reGenerated, err := ollama.AskAiWithStringAndContext(guiApp, ollamaClient, response.Context,
-				"That doesn't sound good, how about something really healthy but still tasty please.")
-			if err != nil {
-				slog.Error("Failed to re-generate", "error", err)
-				return
-			}
-			shortcuts.LastClipboardContent = sha256.Sum256([]byte(response.Response))
-			loadingScreen.Hide()
-			recipePopUp(guiApp, tabs, ollamaClient, recipe, &reGenerated)
-		}),
-		widget.NewButton("Let's Not Cook", func() {
+	buttons := container.NewPadded()
+	for _, v := range recipeVariations {
+		buttons.Add(widget.NewButton(v.label, func() {
 			loadingScreen := loading.LoadingScreenWithMessageAddModel(guiApp, loading.ThinkingMsg,
-				"Let's Not Cook")
+				v.label+"...")
 			loadingScreen.Show()
-			reGenerated, err := ollama.AskAiWithStringAndContext(guiApp, ollamaClient, response.Context,
-				"I don't feel like cooking or heating anything up, how about something cold I could put together please.")
+			reGenerated, err := ollama.AskAiWithStringAndContext(guiApp, ollamaClient, response.Context, v.request)
 			if err != nil {
 				slog.Error("Failed to re-generate", "error", err)
+				loadingScreen.Hide()
 				return
 			}
 			shortcuts.LastClipboardContent = sha256.Sum256([]byte(response.Response))
 			loadingScreen.Hide()
-			recipePopUp(guiApp, tabs, ollamaClient, recipe, &reGenerated)
-		}),
-		widget.NewButtonWithIcon("Close Recipe", theme.ContentClearIcon(), func() {
-			tabs.Remove(tabs.Selected())
-		}),
-		widget.NewButtonWithIcon("Copy the Recipe to Clipboard", theme.ContentCopyIcon(), func() {
-			// TODO: Pass in window?
-			//w.Clipboard().SetContent(response.Response)
-			//w.Close()
-		}),
-	)
+			recipePopUp(guiApp, w, tabs, ollamaClient, restrictions, recipe, &reGenerated)
+		}))
+	}
+	buttons.Add(widget.NewButtonWithIcon("Close Recipe", theme.ContentClearIcon(), func() {
+		tabs.Remove(tabs.Selected())
+	}))
+	buttons.Add(widget.NewButtonWithIcon("Copy the Recipe to Clipboard", theme.ContentCopyIcon(), func() {
+		w.Clipboard().SetContent(response.Response)
+	}))
 
 	buttons.Layout = layout.NewAdaptiveGridLayout(3)
 	center := container.NewVBox(buttons, layout.NewSpacer(), footer())
 	grid := container.New(layout.NewAdaptiveGridLayout(1), vbox)
 	tabSpace := container.NewBorder(
-		nil,
+		flagsWarning(textFlags(response.Response, restrictions)),
 		center,
 		nil,
 		nil,
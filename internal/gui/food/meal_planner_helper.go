@@ -419,24 +419,3 @@ func getPantryStuffs() []string {
 	}
 	return pantryStuffs
 }
-
-func getAllergies() []string {
-	foodAllergies := []string{
-		"Peanut",
-		"Tree Nut",
-		"Milk",
-		"Egg",
-		"Wheat",
-		"Soy",
-		"Fish",
-		"Shellfish",
-		"Sesame",
-		"Mustard",
-		"Corn",
-		"Gluten",
-		"Sulfite",
-		"Celery",
-		"Lupin",
-	}
-	return foodAllergies
-}
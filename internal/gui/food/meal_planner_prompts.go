@@ -2,6 +2,10 @@ package food
 
 import (
 	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bahelit/ctrl_plus_revise/pkg/pantry"
 )
 
 func createMealPrompt(mealInfo MealInfo) string {
@@ -92,17 +96,9 @@ func createMealPrompt(mealInfo MealInfo) string {
 		}
 		recipe = fmt.Sprintf("%s. In the Pantry we have we have %s", recipe, pantry)
 	}
-	if len(mealInfo.Allergies) != 0 {
-		var allergy string
-		for i, a := range mealInfo.Allergies {
-			if i == 0 {
-				allergy = allergy + a
-			} else {
-				allergy = allergy + ", " + a
-			}
-		}
-		recipe = fmt.Sprintf("%s. Please be aware of our food allergy to %s", recipe, allergy)
-	}
+	recipe += inventoryPrompt(mealInfo.Inventory, time.Now(), true)
+	recipe += mealInfo.Eaters.Prompt()
+	recipe += mealInfo.restrictions().Prompt()
 	return recipe
 }
 
@@ -194,17 +190,9 @@ func createMealPrepPrompt(mealInfo MealInfo) string {
 		}
 		recipe = fmt.Sprintf("%s. In the Pantry we have we have %s", recipe, pantry)
 	}
-	if len(mealInfo.Allergies) != 0 {
-		var allergy string
-		for i, a := range mealInfo.Allergies {
-			if i == 0 {
-				allergy = allergy + a
-			} else {
-				allergy = allergy + ", " + a
-			}
-		}
-		recipe = fmt.Sprintf("%s. Please be aware of our food allergy to %s", recipe, allergy)
-	}
+	recipe += inventoryPrompt(mealInfo.Inventory, time.Now(), true)
+	recipe += mealInfo.Eaters.Prompt()
+	recipe += mealInfo.restrictions().Prompt()
 	return recipe
 }
 
@@ -296,17 +284,9 @@ func createGroceryListPrompt(mealInfo MealInfo) string {
 		}
 		recipe = fmt.Sprintf("%s. In the Pantry we have we have %s", recipe, pantry)
 	}
-	if len(mealInfo.Allergies) != 0 {
-		var allergy string
-		for i, a := range mealInfo.Allergies {
-			if i == 0 {
-				allergy = allergy + a
-			} else {
-				allergy = allergy + ", " + a
-			}
-		}
-		recipe = fmt.Sprintf("%s. Please be aware of our food allergy to %s", recipe, allergy)
-	}
+	recipe += inventoryPrompt(mealInfo.Inventory, time.Now(), false)
+	recipe += mealInfo.Eaters.Prompt()
+	recipe += mealInfo.restrictions().Prompt()
 	return recipe
 }
 
@@ -401,16 +381,32 @@ func createBudgetFriendlyGroceryListPrompt(mealInfo MealInfo) string {
 		}
 		recipe = fmt.Sprintf("%s. In the Pantry we have we have %s", recipe, pantry)
 	}
-	if len(mealInfo.Allergies) != 0 {
-		var allergy string
-		for i, a := range mealInfo.Allergies {
-			if i == 0 {
-				allergy = allergy + a
-			} else {
-				allergy = allergy + ", " + a
-			}
+	recipe += inventoryPrompt(mealInfo.Inventory, time.Now(), false)
+	recipe += mealInfo.Eaters.Prompt()
+	recipe += mealInfo.restrictions().Prompt()
+	return recipe
+}
+
+// inventoryPrompt lists the pantry inventory with quantities, when prioritise
+// is set the items about to expire are asked to be used first.
+func inventoryPrompt(inventory pantry.Inventory, now time.Time, prioritise bool) string {
+	var fresh pantry.Inventory
+	for _, item := range inventory {
+		if days, ok := item.DaysLeft(now); !ok || days >= 0 {
+			fresh = append(fresh, item)
 		}
-		recipe = fmt.Sprintf("%s. Please be aware of our food allergy to %s", recipe, allergy)
 	}
-	return recipe
+	if len(fresh) == 0 {
+		return ""
+	}
+	prompt := fmt.Sprintf(". Our pantry inventory is %s", fresh.Describe())
+	soon := fresh.ExpiringSoon(now, expiringSoonDays)
+	if !prioritise || len(soon) == 0 {
+		return prompt
+	}
+	var items []string
+	for _, item := range soon {
+		items = append(items, fmt.Sprintf("%s (%s)", item.Describe(), item.Expiry(now)))
+	}
+	return fmt.Sprintf("%s. Please prioritise using these items that expire soon: %s", prompt, strings.Join(items, ", "))
 }
@@ -0,0 +1,273 @@
+package food
+
+import (
+	"errors"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/bahelit/ctrl_plus_revise/internal/config"
+	"github.com/bahelit/ctrl_plus_revise/internal/store/database"
+	"github.com/bahelit/ctrl_plus_revise/pkg/pantry"
+)
+
+// expiringSoonDays is how far ahead the meal planner looks for food to use up.
+const expiringSoonDays = 4
+
+var units = []string{"", "g", "kg", "oz", "lb", "ml", "l", "cup", "tbsp", "tsp", "can", "jar", "bag", "box", "bunch", "dozen"}
+
+// categoryItems are the meal planner's check boxes of each category, they are
+// offered as names and imported into the inventory.
+var categoryItems = map[pantry.Category]struct {
+	names func() []string
+	key   string
+}{
+	pantry.Dairy:       {getDairy, config.DairyKey},
+	pantry.Freezer:     {getFreezerStuffs, config.FreezerKey},
+	pantry.HerbsSpices: {getHerbsSpices, config.HerbsKey},
+	pantry.Pantry:      {getPantryStuffs, config.PantryKey},
+	pantry.Vegetables:  {getVegetables, config.VeggiesKey},
+	pantry.Protein:     {getProteins, config.ProteinKey},
+}
+
+// loadInventory returns the pantry inventory, or nothing when it can't be read.
+func loadInventory() pantry.Inventory {
+	store, err := database.NewPantryStore()
+	if err != nil {
+		slog.Error("Can NOT open the pantry", "err", err.Error())
+		return nil
+	}
+	items, err := store.GetItems()
+	if err != nil {
+		slog.Error("Can NOT read the pantry", "err", err.Error())
+	}
+	return items
+}
+
+// PantryWindow opens the pantry inventory editor.
+func PantryWindow(guiApp fyne.App) {
+	slog.Debug("Showing pantry")
+	w := guiApp.NewWindow("Ctrl+Revise Pantry")
+	w.Resize(fyne.NewSize(760, 480))
+
+	store, err := database.NewPantryStore()
+	if err != nil {
+		slog.Error("Can NOT open the pantry", "err", err.Error())
+		dialog.ShowError(err, w)
+		w.Show()
+		return
+	}
+	items, err := store.GetItems()
+	if err != nil {
+		slog.Error("Can NOT read the pantry", "err", err.Error())
+	}
+
+	editor := newItemEditor()
+	var selected = -1
+	list := widget.NewList(
+		func() int { return len(items) },
+		func() fyne.CanvasObject { return widget.NewLabel("Template Pantry Item Name") },
+		func(id widget.ListItemID, o fyne.CanvasObject) {
+			o.(*widget.Label).SetText(itemLabel(items[id], time.Now()))
+		})
+	list.OnSelected = func(id widget.ListItemID) {
+		selected = id
+		editor.edit(items[id])
+	}
+
+	reload := func() {
+		items, err = store.GetItems()
+		if err != nil {
+			slog.Error("Can NOT read the pantry", "err", err.Error())
+			return
+		}
+		list.UnselectAll()
+		list.Refresh()
+		selected = -1
+		editor.edit(pantry.Item{Category: editor.category(), Purchased: time.Now()})
+	}
+
+	saveButton := widget.NewButtonWithIcon("Save", theme.DocumentSaveIcon(), func() {
+		item, err := editor.item()
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		if err = store.SaveItem(&item); err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		reload()
+	})
+	newButton := widget.NewButtonWithIcon("New", theme.ContentAddIcon(), func() {
+		list.UnselectAll()
+		selected = -1
+		editor.edit(pantry.Item{Category: editor.category(), Purchased: time.Now()})
+	})
+	deleteButton := widget.NewButtonWithIcon("Used Up", theme.DeleteIcon(), func() {
+		if selected < 0 || items[selected].ID == nil {
+			return
+		}
+		if err := store.DeleteItem(*items[selected].ID); err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		reload()
+	})
+	importButton := widget.NewButtonWithIcon("Add Checked Items", theme.DownloadIcon(), func() {
+		added := importCheckedItems(guiApp, store, items)
+		dialog.ShowInformation("Pantry", strconv.Itoa(added)+" items from the meal planner were added", w)
+		reload()
+	})
+
+	buttons := container.NewHBox(newButton, deleteButton, importButton, layout.NewSpacer(), saveButton)
+	split := container.NewHSplit(list, container.NewBorder(nil, buttons, nil, nil, editor.content))
+	split.Offset = 0.45
+	w.SetContent(split)
+	editor.edit(pantry.Item{Category: pantry.Pantry, Purchased: time.Now()})
+	w.Show()
+}
+
+// importCheckedItems adds the items checked in the meal planner that aren't
+// in the inventory yet.
+func importCheckedItems(guiApp fyne.App, store *database.PantryStore, items pantry.Inventory) int {
+	added := 0
+	for _, category := range pantry.Categories() {
+		for _, name := range guiApp.Preferences().StringList(categoryItems[category].key) {
+			if slices.ContainsFunc(items, func(i pantry.Item) bool { return strings.EqualFold(i.Name, name) }) {
+				continue
+			}
+			item := pantry.Item{Name: name, Category: category, Purchased: time.Now()}
+			if err := store.SaveItem(&item); err != nil {
+				slog.Error("Failed to add pantry item", "error", err, "item", name)
+				continue
+			}
+			added++
+		}
+	}
+	return added
+}
+
+func itemLabel(item pantry.Item, now time.Time) string {
+	label := item.Name
+	if amount := item.Amount(); amount != "" {
+		label += " - " + amount
+	}
+	if expiry := item.Expiry(now); expiry != "" {
+		label += " (" + expiry + ")"
+	}
+	return label
+}
+
+// itemEditor is the form for a single pantry item.
+type itemEditor struct {
+	id        *int64
+	name      *widget.SelectEntry
+	group     *widget.Select
+	quantity  *widget.Entry
+	unit      *widget.SelectEntry
+	purchased *widget.Entry
+	expires   *widget.Entry
+	content   fyne.CanvasObject
+}
+
+func newItemEditor() *itemEditor {
+	e := &itemEditor{
+		name:      widget.NewSelectEntry(nil),
+		quantity:  widget.NewEntry(),
+		unit:      widget.NewSelectEntry(units),
+		purchased: widget.NewEntry(),
+		expires:   widget.NewEntry(),
+	}
+	var categories []string
+	for _, c := range pantry.Categories() {
+		categories = append(categories, string(c))
+	}
+	e.group = widget.NewSelect(categories, func(s string) {
+		e.name.SetOptions(categoryItems[pantry.Category(s)].names())
+	})
+	e.name.SetPlaceHolder("Chicken breast")
+	e.quantity.SetPlaceHolder("1.5")
+	e.purchased.SetPlaceHolder(pantry.DateLayout)
+	e.expires.SetPlaceHolder(pantry.DateLayout + ", empty if it keeps")
+
+	expiresIn := func(days int) func() {
+		return func() {
+			purchased, err := pantry.ParseDate(e.purchased.Text)
+			if err != nil || purchased.IsZero() {
+				purchased = time.Now()
+			}
+			e.expires.SetText(pantry.FormatDate(purchased.AddDate(0, 0, days)))
+		}
+	}
+	quickExpiry := container.NewHBox(
+		widget.NewButton("+3 days", expiresIn(3)),
+		widget.NewButton("+1 week", expiresIn(7)),
+		widget.NewButton("+1 month", expiresIn(30)),
+	)
+	e.content = container.New(layout.NewFormLayout(),
+		widget.NewLabel("Category:"), e.group,
+		widget.NewLabel("Name:"), e.name,
+		widget.NewLabel("Quantity:"), container.NewGridWithColumns(2, e.quantity, e.unit),
+		widget.NewLabel("Purchased:"), e.purchased,
+		widget.NewLabel("Expires:"), container.NewVBox(e.expires, quickExpiry),
+	)
+	return e
+}
+
+func (e *itemEditor) edit(item pantry.Item) {
+	e.id = item.ID
+	e.group.SetSelected(string(item.Category))
+	e.name.SetText(item.Name)
+	e.quantity.SetText("")
+	if item.Quantity != 0 {
+		e.quantity.SetText(strconv.FormatFloat(item.Quantity, 'f', -1, 64))
+	}
+	e.unit.SetText(item.Unit)
+	e.purchased.SetText(pantry.FormatDate(item.Purchased))
+	e.expires.SetText(pantry.FormatDate(item.Expires))
+}
+
+func (e *itemEditor) category() pantry.Category {
+	return pantry.Category(e.group.Selected)
+}
+
+func (e *itemEditor) item() (pantry.Item, error) {
+	item := pantry.Item{
+		ID:       e.id,
+		Name:     strings.TrimSpace(e.name.Text),
+		Category: e.category(),
+		Unit:     strings.TrimSpace(e.unit.Text),
+	}
+	if item.Name == "" {
+		return item, errEmptyName
+	}
+	var err error
+	if q := strings.TrimSpace(e.quantity.Text); q != "" {
+		if item.Quantity, err = strconv.ParseFloat(q, 64); err != nil {
+			return item, errQuantity
+		}
+	}
+	if item.Purchased, err = pantry.ParseDate(e.purchased.Text); err != nil {
+		return item, errDate
+	}
+	if item.Expires, err = pantry.ParseDate(e.expires.Text); err != nil {
+		return item, errDate
+	}
+	return item, nil
+}
+
+var (
+	errEmptyName = errors.New("the item needs a name")
+	errQuantity  = errors.New("the quantity must be a number such as 1.5")
+	errDate      = errors.New("dates are written as " + pantry.DateLayout)
+)
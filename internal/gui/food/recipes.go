@@ -0,0 +1,268 @@
+package food
+
+import (
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	ollamaApi "github.com/ollama/ollama/api"
+
+	"github.com/bahelit/ctrl_plus_revise/internal/gui/loading"
+	"github.com/bahelit/ctrl_plus_revise/internal/ollama"
+	"github.com/bahelit/ctrl_plus_revise/internal/store/database"
+	"github.com/bahelit/ctrl_plus_revise/pkg/diet"
+	"github.com/bahelit/ctrl_plus_revise/pkg/nutrition"
+	"github.com/bahelit/ctrl_plus_revise/pkg/recipe"
+)
+
+// maxServings is the most servings a recipe can be scaled to.
+const maxServings = 12
+
+// recipeVariations are the buttons asking for a different recipe.
+var recipeVariations = []struct {
+	label   string
+	request string
+}{
+	{"Something Different", "That doesn't sound good, how about something else please."},
+	{"Something Simple and Quick", "That doesn't sound good, how about something that's simple and quick to put together please."},
+	{"Something Healthy", "That doesn't sound good, how about something really healthy but still tasty please."},
+	{"Let's Not Cook", "I don't feel like cooking or heating anything up, how about something cold I could put together please."},
+}
+
+// askForRecipe asks for the recipe as data and shows it in a tab, models that
+// can't produce valid JSON get the Markdown recipe.
+func askForRecipe(guiApp fyne.App, w fyne.Window, tabs *container.AppTabs, ollamaClient *ollamaApi.Client,
+	restrictions diet.Restrictions, prompt, msg string) {
+	loadingScreen := loading.LoadingScreenWithMessageAddModel(guiApp, loading.ThinkingMsg, msg)
+	loadingScreen.Show()
+
+	r, err := ollama.AskAIForData(guiApp, ollamaClient, ollama.MakeRecipe, prompt)
+	if err == nil && len(r.Ingredients) > 0 {
+		loadingScreen.Hide()
+		recipeTab(guiApp, w, tabs, ollamaClient, restrictions, prompt, r)
+		return
+	}
+	slog.Warn("Failed to get the recipe as data, asking for Markdown", "error", err)
+
+	prompt = addMarkdownFormattingToRecipe(prompt)
+	generated, err := ollama.AskAI(guiApp, ollamaClient, prompt)
+	loadingScreen.Hide()
+	if err != nil {
+		slog.Error("Failed to ask AI", "error", err)
+		return
+	}
+	recipePopUp(guiApp, w, tabs, ollamaClient, restrictions, prompt, &generated)
+}
+
+// recipeTab shows a recipe that can be scaled, saved to the recipe book and
+// copied to the clipboard.
+func recipeTab(guiApp fyne.App, w fyne.Window, tabs *container.AppTabs, ollamaClient *ollamaApi.Client,
+	restrictions diet.Restrictions, prompt string, r recipe.Recipe) {
+	view := newRecipeView(r, restrictions)
+	var tab *container.TabItem
+
+	variations := container.NewPadded()
+	for _, v := range recipeVariations {
+		variations.Add(widget.NewButton(v.label, func() {
+			askForRecipe(guiApp, w, tabs, ollamaClient, restrictions, prompt+". "+v.request, v.label+"...")
+		}))
+	}
+	variations.Layout = layout.NewAdaptiveGridLayout(2)
+
+	save := widget.NewButtonWithIcon("Save to Recipe Book", theme.DocumentSaveIcon(), nil)
+	save.OnTapped = func() {
+		store, err := database.NewRecipeStore()
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		saved := view.recipe
+		if err = store.SaveRecipe(&saved); err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		save.SetText("Saved")
+		save.Disable()
+	}
+	buttons := container.NewPadded(
+		save,
+		widget.NewButtonWithIcon("Copy the Recipe to Clipboard", theme.ContentCopyIcon(), func() {
+			w.Clipboard().SetContent(view.scaled().Text())
+		}),
+		widget.NewButtonWithIcon("Close Recipe", theme.ContentClearIcon(), func() {
+			tabs.Remove(tab)
+		}),
+	)
+	buttons.Layout = layout.NewAdaptiveGridLayout(3)
+
+	tabCount := len(tabs.Items)
+	tab = container.NewTabItem("Recipe #"+strconv.Itoa(tabCount), container.NewBorder(
+		view.servingsBar(), container.NewVBox(variations, buttons, footer()), nil, nil, view.content))
+	tabs.Append(tab)
+	tabs.SelectIndex(tabCount)
+}
+
+// recipeView shows a recipe at the servings picked by the user, ingredients
+// breaking the restrictions are flagged above it.
+type recipeView struct {
+	recipe       recipe.Recipe
+	restrictions diet.Restrictions
+	servings     *widget.Select
+	text         *widget.RichText
+	warning      *fyne.Container
+	content      fyne.CanvasObject
+}
+
+func newRecipeView(r recipe.Recipe, restrictions diet.Restrictions) *recipeView {
+	v := &recipeView{restrictions: restrictions, text: widget.NewRichText(), warning: container.NewVBox()}
+	v.text.Wrapping = fyne.TextWrapWord
+	v.content = container.NewBorder(v.warning, nil, nil, nil, container.NewVScroll(v.text))
+	v.servings = widget.NewSelect(servingOptions(), func(string) { v.refresh() })
+	v.show(r)
+	return v
+}
+
+// show replaces the recipe, the servings are reset to the recipe's.
+func (v *recipeView) show(r recipe.Recipe) {
+	v.recipe = r
+	if r.Servings > 0 && r.Servings <= maxServings {
+		v.servings.SetSelected(strconv.Itoa(r.Servings))
+	} else {
+		v.servings.ClearSelected()
+	}
+	v.warning.Objects = []fyne.CanvasObject{flagsWarning(recipeFlags(r, v.restrictions))}
+	v.warning.Refresh()
+	v.refresh()
+}
+
+func (v *recipeView) scaled() recipe.Recipe {
+	servings, _ := strconv.Atoi(v.servings.Selected)
+	return v.recipe.Scale(servings)
+}
+
+func (v *recipeView) refresh() {
+	text := v.scaled().Markdown()
+	if len(v.recipe.Ingredients) > 0 {
+		text += "\n" + nutrition.Recipe(v.recipe).Markdown()
+	}
+	v.text.ParseMarkdown(text)
+}
+
+func (v *recipeView) servingsBar() fyne.CanvasObject {
+	return container.NewHBox(widget.NewLabel("Servings:"), v.servings)
+}
+
+// RecipeBook opens the saved recipes, they can be searched, rated, noted,
+// scaled and copied.
+func RecipeBook(guiApp fyne.App) {
+	slog.Debug("Showing recipe book")
+	w := guiApp.NewWindow("Ctrl+Revise Recipe Book")
+	w.Resize(fyne.NewSize(820, 560))
+
+	store, err := database.NewRecipeStore()
+	if err != nil {
+		slog.Error("Can NOT open the recipe book", "err", err.Error())
+		dialog.ShowError(err, w)
+		w.Show()
+		return
+	}
+	recipes, err := store.GetRecipes("")
+	if err != nil {
+		slog.Error("Can NOT read the recipe book", "err", err.Error())
+	}
+
+	view := newRecipeView(recipe.Recipe{}, loadRestrictions(guiApp))
+	notes := widget.NewMultiLineEntry()
+	notes.SetPlaceHolder("Notes, e.g. use less salt next time")
+	notes.Wrapping = fyne.TextWrapWord
+	notes.SetMinRowsVisible(3)
+	var ratings []string
+	for i := 0; i <= recipe.MaxRating; i++ {
+		ratings = append(ratings, recipe.Recipe{Rating: i}.Stars())
+	}
+	rating := widget.NewSelect(ratings, nil)
+
+	selected := -1
+	list := widget.NewList(
+		func() int { return len(recipes) },
+		func() fyne.CanvasObject { return widget.NewLabel("★★★★★ Template Recipe Title") },
+		func(id widget.ListItemID, o fyne.CanvasObject) {
+			o.(*widget.Label).SetText(recipes[id].Stars() + " " + recipes[id].Title)
+		})
+	list.OnSelected = func(id widget.ListItemID) {
+		selected = id
+		view.show(recipes[id])
+		rating.SetSelectedIndex(recipes[id].Rating)
+		notes.SetText(recipes[id].Notes)
+	}
+
+	search := widget.NewEntry()
+	search.SetPlaceHolder("Search by title, ingredient or notes")
+	reload := func() {
+		recipes, err = store.GetRecipes(strings.TrimSpace(search.Text))
+		if err != nil {
+			slog.Error("Can NOT read the recipe book", "err", err.Error())
+			return
+		}
+		list.UnselectAll()
+		list.Refresh()
+		selected = -1
+		view.show(recipe.Recipe{})
+		rating.ClearSelected()
+		notes.SetText("")
+	}
+	search.OnChanged = func(string) { reload() }
+
+	saveButton := widget.NewButtonWithIcon("Save", theme.DocumentSaveIcon(), func() {
+		if selected < 0 {
+			return
+		}
+		r := recipes[selected]
+		r.Rating = max(rating.SelectedIndex(), 0)
+		r.Notes = strings.TrimSpace(notes.Text)
+		if err := store.SaveRecipe(&r); err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		recipes[selected] = r
+		list.RefreshItem(selected)
+		view.show(r)
+	})
+	deleteButton := widget.NewButtonWithIcon("Delete", theme.DeleteIcon(), func() {
+		if selected < 0 {
+			return
+		}
+		r := recipes[selected]
+		dialog.ShowConfirm("Delete Recipe", "Delete "+r.Title+" from the recipe book?", func(ok bool) {
+			if !ok {
+				return
+			}
+			if err := store.DeleteRecipe(*r.ID); err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			reload()
+		}, w)
+	})
+	copyButton := widget.NewButtonWithIcon("Copy", theme.ContentCopyIcon(), func() {
+		if selected < 0 {
+			return
+		}
+		w.Clipboard().SetContent(view.scaled().Text())
+	})
+
+	details := container.NewBorder(
+		container.NewHBox(view.servingsBar(), layout.NewSpacer(), widget.NewLabel("Rating:"), rating),
+		container.NewVBox(notes, container.NewHBox(deleteButton, copyButton, layout.NewSpacer(), saveButton)),
+		nil, nil, view.content)
+	split := container.NewHSplit(container.NewBorder(search, nil, nil, nil, list), details)
+	split.Offset = 0.35
+	w.SetContent(split)
+	w.Show()
+}
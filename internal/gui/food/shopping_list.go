@@ -0,0 +1,82 @@
+package food
+
+import (
+	"log/slog"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/bahelit/ctrl_plus_revise/pkg/mealplan"
+)
+
+// shoppingFormats are the formats the shopping list is exported in, with the
+// file extension.
+var shoppingFormats = []struct {
+	name      string
+	extension string
+	export    func([]mealplan.Item) (string, error)
+}{
+	{"Markdown", ".md", func(items []mealplan.Item) (string, error) { return mealplan.Markdown(items), nil }},
+	{"CSV", ".csv", mealplan.CSV},
+	{"Plain Text", ".txt", func(items []mealplan.Item) (string, error) { return mealplan.Text(items), nil }},
+}
+
+// shoppingListWindow shows the shopping list of the week to copy or save.
+func shoppingListWindow(guiApp fyne.App, items []mealplan.Item) {
+	w := guiApp.NewWindow("Ctrl+Revise Shopping List")
+	w.Resize(fyne.NewSize(520, 560))
+
+	text := widget.NewMultiLineEntry()
+	text.Wrapping = fyne.TextWrapWord
+	var names []string
+	for _, f := range shoppingFormats {
+		names = append(names, f.name)
+	}
+	format := widget.NewRadioGroup(names, nil)
+	format.Horizontal = true
+	format.Required = true
+	format.OnChanged = func(string) {
+		exported, err := shoppingFormats[formatIndex(format.Selected)].export(items)
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		text.SetText(exported)
+	}
+	format.SetSelected(names[0])
+
+	copyButton := widget.NewButtonWithIcon("Copy to Clipboard", theme.ContentCopyIcon(), func() {
+		w.Clipboard().SetContent(text.Text)
+	})
+	saveButton := widget.NewButtonWithIcon("Save As...", theme.DocumentSaveIcon(), func() {
+		save := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+			if err != nil || writer == nil {
+				return
+			}
+			defer writer.Close()
+			if _, err = writer.Write([]byte(text.Text)); err != nil {
+				slog.Error("Failed to save the shopping list", "error", err)
+				dialog.ShowError(err, w)
+			}
+		}, w)
+		save.SetFileName("shopping-list" + shoppingFormats[formatIndex(format.Selected)].extension)
+		save.Show()
+	})
+	buttons := container.NewHBox(copyButton, layout.NewSpacer(), saveButton)
+	w.SetContent(container.NewBorder(format, buttons, nil, nil, text))
+	w.Show()
+}
+
+func formatIndex(name string) int {
+	for i, f := range shoppingFormats {
+		if strings.EqualFold(f.name, name) {
+			return i
+		}
+	}
+	return 0
+}
@@ -0,0 +1,200 @@
+package glossary
+
+import (
+	"log/slog"
+	"sort"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/bahelit/ctrl_plus_revise/internal/gui"
+	"github.com/bahelit/ctrl_plus_revise/internal/ollama"
+	"github.com/bahelit/ctrl_plus_revise/internal/store/database"
+	"github.com/bahelit/ctrl_plus_revise/pkg/glossary"
+)
+
+// Load reads the glossary from the database and hands it to the prompts.
+func Load() {
+	store, err := database.NewGlossaryStore()
+	if err != nil {
+		slog.Error("Can NOT open the glossary", "err", err.Error())
+		return
+	}
+	terms, err := store.GetTerms()
+	if err != nil {
+		slog.Error("Can NOT read the glossary", "err", err.Error())
+		return
+	}
+	ollama.SetGlossary(terms)
+}
+
+// ShowGlossary opens the glossary editor.
+func ShowGlossary(guiApp fyne.App) {
+	slog.Debug("Showing glossary")
+	w := guiApp.NewWindow("Ctrl+Revise Glossary")
+	w.Resize(fyne.NewSize(720, 420))
+
+	store, err := database.NewGlossaryStore()
+	if err != nil {
+		slog.Error("Can NOT open the glossary", "err", err.Error())
+		dialog.ShowError(err, w)
+		w.Show()
+		return
+	}
+	terms, err := store.GetTerms()
+	if err != nil {
+		slog.Error("Can NOT read the glossary", "err", err.Error())
+	}
+
+	editor := newTermEditor(guiApp)
+	var selected = -1
+	list := widget.NewList(
+		func() int { return len(terms) },
+		func() fyne.CanvasObject { return widget.NewLabel("Template Term") },
+		func(id widget.ListItemID, o fyne.CanvasObject) {
+			o.(*widget.Label).SetText(terms[id].Source)
+		})
+	list.OnSelected = func(id widget.ListItemID) {
+		selected = id
+		editor.edit(terms[id])
+	}
+
+	reload := func() {
+		terms, err = store.GetTerms()
+		if err != nil {
+			slog.Error("Can NOT read the glossary", "err", err.Error())
+			return
+		}
+		ollama.SetGlossary(terms)
+		list.UnselectAll()
+		list.Refresh()
+		selected = -1
+		editor.edit(glossary.Term{})
+	}
+
+	saveButton := widget.NewButtonWithIcon("Save", theme.DocumentSaveIcon(), func() {
+		term := editor.term()
+		if strings.TrimSpace(term.Source) == "" {
+			dialog.ShowInformation("Glossary", "The term can't be empty", w)
+			return
+		}
+		if err := store.SaveTerm(&term); err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		reload()
+	})
+	newButton := widget.NewButtonWithIcon("New", theme.ContentAddIcon(), func() {
+		list.UnselectAll()
+		selected = -1
+		editor.edit(glossary.Term{})
+	})
+	deleteButton := widget.NewButtonWithIcon("Delete", theme.DeleteIcon(), func() {
+		if selected < 0 || terms[selected].ID == nil {
+			return
+		}
+		if err := store.DeleteTerm(*terms[selected].ID); err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		reload()
+	})
+
+	buttons := container.NewHBox(newButton, deleteButton, layout.NewSpacer(), saveButton)
+	split := container.NewHSplit(list, container.NewBorder(nil, buttons, nil, nil, editor.content))
+	split.Offset = 0.3
+	w.SetContent(split)
+	w.Show()
+}
+
+// termEditor is the form for a single term.
+type termEditor struct {
+	id             *int64
+	source         *widget.Entry
+	doNotTranslate *widget.Check
+	caseSensitive  *widget.Check
+	translations   map[string]string
+	translated     *widget.Label
+	content        fyne.CanvasObject
+}
+
+func newTermEditor(guiApp fyne.App) *termEditor {
+	e := &termEditor{
+		source:         widget.NewEntry(),
+		doNotTranslate: widget.NewCheck("Do not translate", nil),
+		caseSensitive:  widget.NewCheck("Case sensitive", nil),
+		translations:   map[string]string{},
+		translated:     widget.NewLabel(""),
+	}
+	e.source.SetPlaceHolder("Product name or technical term")
+	e.translated.Wrapping = fyne.TextWrapWord
+
+	languageSelect := widget.NewSelect(gui.Languages(guiApp), nil)
+	translationEntry := widget.NewEntry()
+	translationEntry.SetPlaceHolder("The term in the selected language")
+	languageSelect.OnChanged = func(lang string) {
+		translationEntry.SetText(e.translations[lang])
+	}
+	setTranslation := widget.NewButton("Set", func() {
+		if languageSelect.Selected == "" {
+			return
+		}
+		text := strings.TrimSpace(translationEntry.Text)
+		if text == "" {
+			delete(e.translations, languageSelect.Selected)
+		} else {
+			e.translations[languageSelect.Selected] = text
+		}
+		e.showTranslations()
+	})
+
+	e.content = container.NewVBox(
+		container.New(layout.NewFormLayout(),
+			widget.NewLabel("Term:"), e.source,
+			widget.NewLabel(""), container.NewHBox(e.doNotTranslate, e.caseSensitive),
+			languageSelect, container.NewBorder(nil, nil, nil, setTranslation, translationEntry),
+		),
+		widget.NewLabelWithStyle("Translations:", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		e.translated,
+	)
+	return e
+}
+
+func (e *termEditor) edit(term glossary.Term) {
+	e.id = term.ID
+	e.source.SetText(term.Source)
+	e.doNotTranslate.SetChecked(term.DoNotTranslate)
+	e.caseSensitive.SetChecked(term.CaseSensitive)
+	e.translations = map[string]string{}
+	for lang, text := range term.Translations {
+		e.translations[lang] = text
+	}
+	e.showTranslations()
+}
+
+func (e *termEditor) showTranslations() {
+	var lines []string
+	for lang, text := range e.translations {
+		lines = append(lines, lang+": "+text)
+	}
+	sort.Strings(lines)
+	if len(lines) == 0 {
+		lines = append(lines, "None, the AI picks a translation")
+	}
+	e.translated.SetText(strings.Join(lines, "\n"))
+}
+
+func (e *termEditor) term() glossary.Term {
+	return glossary.Term{
+		ID:             e.id,
+		Source:         strings.TrimSpace(e.source.Text),
+		Translations:   e.translations,
+		DoNotTranslate: e.doNotTranslate.Checked,
+		CaseSensitive:  e.caseSensitive.Checked,
+	}
+}
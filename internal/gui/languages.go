@@ -1,17 +1,33 @@
 package gui
 
-import "github.com/bahelit/ctrl_plus_revise/internal/ollama"
+import (
+	"fyne.io/fyne/v2"
 
-var (
-	Languages = []string{string(ollama.English),
-		string(ollama.Arabic),
-		string(ollama.Chinese),
-		string(ollama.French),
-		string(ollama.German),
-		string(ollama.Italian),
-		string(ollama.Japanese),
-		string(ollama.Portuguese),
-		string(ollama.Russian),
-		string(ollama.Spanish),
-		string(ollama.Turkish)}
+	"github.com/bahelit/ctrl_plus_revise/internal/ollama"
 )
+
+// FromLanguages returns the choices for the language to translate from.
+func FromLanguages(guiApp fyne.App) []string {
+	return languageNames(ollama.AutoDetect, ollama.TranslationLanguages(guiApp))
+}
+
+// ToLanguages returns the choices for the language to translate to.
+func ToLanguages(guiApp fyne.App) []string {
+	return languageNames(ollama.MyLanguage, ollama.TranslationLanguages(guiApp))
+}
+
+// Languages returns the names of every language that can be translated.
+func Languages(guiApp fyne.App) []string {
+	return languageNames("", ollama.TranslationLanguages(guiApp))
+}
+
+func languageNames(first ollama.Language, languages []ollama.Language) []string {
+	var names []string
+	if first != "" {
+		names = append(names, string(first))
+	}
+	for _, l := range languages {
+		names = append(names, string(l))
+	}
+	return names
+}
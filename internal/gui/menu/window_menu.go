@@ -8,6 +8,7 @@ import (
 	"fyne.io/fyne/v2/driver/desktop"
 	ollamaApi "github.com/ollama/ollama/api"
 
+	"github.com/bahelit/ctrl_plus_revise/internal/gui/modules"
 	"github.com/bahelit/ctrl_plus_revise/internal/gui/settings"
 )
 
@@ -48,6 +49,16 @@ func MakeMenu(guiApp fyne.App, ollamaClient *ollamaApi.Client, w fyne.Window) *f
 			_ = guiApp.OpenURL(u)
 		}))
 
+	tools := fyne.NewMenu("Tools")
+	for _, m := range modules.Enabled(guiApp) {
+		item := fyne.NewMenuItem(m.Name, func() { m.Open(guiApp, ollamaClient) })
+		item.Icon = m.Icon
+		tools.Items = append(tools.Items, item)
+	}
+	tools.Items = append(tools.Items, fyne.NewMenuItemSeparator(), fyne.NewMenuItem("Modules", func() {
+		settings.ShowModuleSettings(guiApp, ollamaClient)
+	}))
+
 	// a quit item will be appended to our first (File) menu
 	file := fyne.NewMenu("File")
 	device := fyne.CurrentDevice()
@@ -57,6 +68,7 @@ func MakeMenu(guiApp fyne.App, ollamaClient *ollamaApi.Client, w fyne.Window) *f
 	file.Items = append(file.Items, aboutItem)
 	main := fyne.NewMainMenu(
 		file,
+		tools,
 		helpMenu,
 	)
 	return main
@@ -0,0 +1,49 @@
+// Package metrics shows the readability of a text next to a revision of it.
+package metrics
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/bahelit/ctrl_plus_revise/pkg/readability"
+)
+
+// Table compares the readability of the text before and after a revision,
+// the changes that make it easier to read are green and harder red.
+func Table(before, after string) fyne.CanvasObject {
+	rows := readability.Compare(readability.Analyze(before), readability.Analyze(after))
+	grid := container.NewGridWithColumns(3, heading(""), heading("Before"), heading("After"))
+	for _, row := range rows {
+		changed := widget.NewLabel(row.After)
+		switch row.Trend {
+		case readability.Easier:
+			changed.Importance = widget.SuccessImportance
+		case readability.Harder:
+			changed.Importance = widget.DangerImportance
+		}
+		grid.Add(widget.NewLabel(row.Name))
+		grid.Add(widget.NewLabel(row.Before))
+		grid.Add(changed)
+	}
+	return grid
+}
+
+// Single shows the readability of one text.
+func Single(text string) fyne.CanvasObject {
+	grid := container.NewGridWithColumns(2)
+	for _, row := range readability.Describe(readability.Analyze(text)) {
+		grid.Add(widget.NewLabel(row.Name))
+		grid.Add(widget.NewLabel(row.After))
+	}
+	return grid
+}
+
+// Summary is the readability of the text in one line.
+func Summary(text string) string {
+	return readability.Analyze(text).Summary()
+}
+
+func heading(text string) *widget.Label {
+	return widget.NewLabelWithStyle(text, fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+}
@@ -0,0 +1,105 @@
+// Package modules is the registry of the feature windows, such as the meal
+// planner or the translator. The system tray, the home screen and the
+// hotkeys are built from the modules the user has enabled.
+package modules
+
+import (
+	"slices"
+	"sync"
+
+	"fyne.io/fyne/v2"
+	ollamaApi "github.com/ollama/ollama/api"
+
+	"github.com/bahelit/ctrl_plus_revise/internal/config"
+)
+
+// Action opens a module's window.
+type Action func(guiApp fyne.App, ollamaClient *ollamaApi.Client)
+
+// Hotkey is a global keyboard shortcut of a module, Keys are in the order
+// gohook expects, the key first and the modifiers after it.
+type Hotkey struct {
+	Name   string
+	Keys   []string
+	Action Action
+}
+
+// Module is a feature with a window of its own.
+type Module struct {
+	// ID is saved in the preferences, it must not change.
+	ID          string
+	Name        string
+	Description string
+	Icon        fyne.Resource
+	Open        Action
+	// Settings opens the module's settings, nil when it has none.
+	Settings Action
+	Hotkeys  []Hotkey
+}
+
+var (
+	lock      sync.Mutex
+	registry  []Module
+	listeners []func()
+)
+
+// Register adds modules to the registry, in the order they are shown.
+func Register(modules ...Module) {
+	lock.Lock()
+	defer lock.Unlock()
+	for _, m := range modules {
+		if i := slices.IndexFunc(registry, func(r Module) bool { return r.ID == m.ID }); i >= 0 {
+			registry[i] = m
+			continue
+		}
+		registry = append(registry, m)
+	}
+}
+
+// All returns every registered module.
+func All() []Module {
+	lock.Lock()
+	defer lock.Unlock()
+	return slices.Clone(registry)
+}
+
+// Enabled returns the modules the user hasn't disabled.
+func Enabled(guiApp fyne.App) []Module {
+	var enabled []Module
+	for _, m := range All() {
+		if IsEnabled(guiApp, m.ID) {
+			enabled = append(enabled, m)
+		}
+	}
+	return enabled
+}
+
+// IsEnabled reports whether the module is enabled, modules are enabled
+// until the user disables them.
+func IsEnabled(guiApp fyne.App, id string) bool {
+	return !slices.Contains(guiApp.Preferences().StringList(config.DisabledModulesKey), id)
+}
+
+// SetEnabled enables or disables the module and tells the listeners.
+func SetEnabled(guiApp fyne.App, id string, enabled bool) {
+	disabled := guiApp.Preferences().StringList(config.DisabledModulesKey)
+	disabled = slices.DeleteFunc(disabled, func(d string) bool { return d == id })
+	if !enabled {
+		disabled = append(disabled, id)
+	}
+	guiApp.Preferences().SetStringList(config.DisabledModulesKey, disabled)
+
+	lock.Lock()
+	notify := slices.Clone(listeners)
+	lock.Unlock()
+	for _, listener := range notify {
+		listener()
+	}
+}
+
+// OnChange calls the listener whenever a module is enabled or disabled.
+func OnChange(listener func()) {
+	lock.Lock()
+	defer lock.Unlock()
+	listeners = append(listeners, listener)
+}
@@ -0,0 +1,136 @@
+package profiles
+
+import (
+	"encoding/json"
+	"log/slog"
+	"strings"
+
+	"fyne.io/fyne/v2"
+
+	"github.com/bahelit/ctrl_plus_revise/internal/config"
+	"github.com/bahelit/ctrl_plus_revise/internal/gui/delivery"
+	"github.com/bahelit/ctrl_plus_revise/internal/ollama"
+	"github.com/bahelit/ctrl_plus_revise/pkg/appprofile"
+)
+
+const (
+	// TemplatePrefix marks the user's prompt templates among the prompts.
+	TemplatePrefix = "Template: "
+	// WorkflowPrefix marks the user's workflows among the prompts.
+	WorkflowPrefix = "Workflow: "
+)
+
+// Get returns the saved profiles.
+func Get(guiApp fyne.App) appprofile.Profiles {
+	profiles := appprofile.Profiles{Fallback: appprofile.Profile{Name: "Fallback"}}
+	saved := guiApp.Preferences().String(config.AppProfilesKey)
+	if saved == "" {
+		return profiles
+	}
+	if err := json.Unmarshal([]byte(saved), &profiles); err != nil {
+		slog.Error("Failed to read application profiles", "error", err)
+	}
+	return profiles
+}
+
+// Save replaces the saved profiles.
+func Save(guiApp fyne.App, profiles appprofile.Profiles) error {
+	for _, p := range profiles.Rules {
+		if err := p.Valid(); err != nil {
+			return err
+		}
+	}
+	data, err := json.Marshal(profiles)
+	if err != nil {
+		return err
+	}
+	guiApp.Preferences().SetString(config.AppProfilesKey, string(data))
+	return nil
+}
+
+// Apply returns guiApp with the settings of the profile in place of the user's
+// settings, code reading its preferences uses the profile without knowing
+// about it. Changes to the preferences are saved as usual.
+func Apply(guiApp fyne.App, p appprofile.Profile) fyne.App {
+	overrides := map[string]any{}
+	if p.Model != "" {
+		if model, ok := ollama.ModelFromName(p.Model); ok {
+			overrides[config.CurrentModelKey] = int(model)
+		} else {
+			slog.Warn("Ignoring unknown model of profile", "profile", p.Name, "model", p.Model)
+		}
+	}
+	if p.Delivery != "" {
+		mode := int(delivery.ModeFromString(p.Delivery))
+		for _, action := range []delivery.Action{delivery.Ask, delivery.Revise, delivery.Translate} {
+			overrides[config.DeliveryModeKey+string(action)] = mode
+		}
+	}
+	if p.Language != "" {
+		overrides[config.CurrentToLangKey] = p.Language
+	}
+	if p.Prompt != "" {
+		// The prompt of the profile replaces the template or workflow picked
+		// in the settings
+		template, _ := strings.CutPrefix(p.Prompt, TemplatePrefix)
+		if template == p.Prompt {
+			template = ""
+		}
+		workflow, _ := strings.CutPrefix(p.Prompt, WorkflowPrefix)
+		if workflow == p.Prompt {
+			workflow = ""
+		}
+		overrides[config.CurrentTemplateKey] = template
+		overrides[config.CurrentWorkflowKey] = workflow
+	}
+	if len(overrides) == 0 {
+		return guiApp
+	}
+	return &profileApp{App: guiApp, prefs: &profilePreferences{Preferences: guiApp.Preferences(), overrides: overrides}}
+}
+
+// Prompt returns the built-in prompt of the profile, templates and workflows
+// are applied through the preferences.
+func Prompt(p appprofile.Profile) (ollama.PromptMsg, bool) {
+	if p.Prompt == "" {
+		return 0, false
+	}
+	return ollama.PromptFromName(p.Prompt)
+}
+
+type profileApp struct {
+	fyne.App
+	prefs *profilePreferences
+}
+
+func (a *profileApp) Preferences() fyne.Preferences {
+	return a.prefs
+}
+
+// profilePreferences reads the settings of a profile before the user's.
+type profilePreferences struct {
+	fyne.Preferences
+	overrides map[string]any
+}
+
+func (p *profilePreferences) Int(key string) int {
+	return p.IntWithFallback(key, 0)
+}
+
+func (p *profilePreferences) IntWithFallback(key string, fallback int) int {
+	if v, ok := p.overrides[key].(int); ok {
+		return v
+	}
+	return p.Preferences.IntWithFallback(key, fallback)
+}
+
+func (p *profilePreferences) String(key string) string {
+	return p.StringWithFallback(key, "")
+}
+
+func (p *profilePreferences) StringWithFallback(key, fallback string) string {
+	if v, ok := p.overrides[key].(string); ok {
+		return v
+	}
+	return p.Preferences.StringWithFallback(key, fallback)
+}
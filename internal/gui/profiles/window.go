@@ -0,0 +1,17 @@
+package profiles
+
+import (
+	"github.com/go-vgo/robotgo"
+
+	"github.com/bahelit/ctrl_plus_revise/pkg/appprofile"
+)
+
+// ActiveWindow returns the process name and title of the focused window.
+func ActiveWindow() appprofile.Window {
+	w := appprofile.Window{Title: robotgo.GetTitle()}
+	name, err := robotgo.FindName(robotgo.GetPid())
+	if err == nil {
+		w.Process = name
+	}
+	return w
+}
@@ -6,11 +6,14 @@ import (
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
 	"fyne.io/fyne/v2/widget"
 	"fyne.io/x/fyne/layout"
 	ollamaApi "github.com/ollama/ollama/api"
 
+	"github.com/bahelit/ctrl_plus_revise/internal/config"
 	"github.com/bahelit/ctrl_plus_revise/internal/gui/clippy"
+	"github.com/bahelit/ctrl_plus_revise/internal/gui/documents"
 	"github.com/bahelit/ctrl_plus_revise/internal/gui/loading"
 	"github.com/bahelit/ctrl_plus_revise/internal/ollama"
 )
@@ -29,6 +32,11 @@ func AskQuestionWindow(guiApp fyne.App, ollamaClient *ollamaApi.Client) {
 	label2 := widget.NewLabel("Press Shift + Enter to submit your question.")
 	label2.TextStyle = fyne.TextStyle{Italic: true}
 
+	useDocuments := widget.NewCheck("Answer from my documents", func(b bool) {
+		guiApp.Preferences().SetBool(config.AskDocumentsKey, b)
+	})
+	useDocuments.SetChecked(guiApp.Preferences().BoolWithFallback(config.AskDocumentsKey, false))
+
 	text := widget.NewMultiLineEntry()
 	text.SetMinRowsVisible(4)
 	text.PlaceHolder = "Ask your question here, remember this is an AI and important\n" +
@@ -43,14 +51,15 @@ func AskQuestionWindow(guiApp fyne.App, ollamaClient *ollamaApi.Client) {
 		loadingScreen := loading.LoadingScreenWithMessageAddModel(guiApp, loading.ThinkingMsg,
 			"Asking question...")
 		loadingScreen.Show()
-		response, err := ollama.AskAI(guiApp, ollamaClient, s)
+		response, err := askAI(guiApp, ollamaClient, s, useDocuments.Checked)
 		if err != nil {
 			slog.Error("Failed to ask AI", "error", err)
 			loadingScreen.Hide()
+			dialog.ShowError(err, question)
 			return
 		}
 		loadingScreen.Hide()
-		clippy.QuestionPopUp(guiApp, ollamaClient, s, &response)
+		clippy.ShowResult(guiApp, ollamaClient, clippy.Result{Input: s, Response: &response})
 		question.Close()
 	}
 	text.Validator = func(s string) error {
@@ -73,21 +82,22 @@ func AskQuestionWindow(guiApp fyne.App, ollamaClient *ollamaApi.Client) {
 		loadingScreen := loading.LoadingScreenWithMessageAddModel(guiApp, loading.ThinkingMsg,
 			"Asking question...")
 		loadingScreen.Show()
-		response, err := ollama.AskAI(guiApp, ollamaClient, text.Text)
+		response, err := askAI(guiApp, ollamaClient, text.Text, useDocuments.Checked)
 		if err != nil {
 			slog.Error("Failed to ask AI", "error", err)
 			loadingScreen.Hide()
+			dialog.ShowError(err, question)
 			return
 		}
 		loadingScreen.Hide()
-		clippy.QuestionPopUp(guiApp, ollamaClient, text.Text, &response)
+		clippy.ShowResult(guiApp, ollamaClient, clippy.Result{Input: text.Text, Response: &response})
 		question.Close()
 	})
 
 	topText := container.NewHBox(label1, label2)
 	questionLayout := layout.NewResponsiveLayout(topText)
 	buttonLayout := layout.NewResponsiveLayout(layout.Responsive(submitQuestionsButton))
-	questionWindow := container.NewBorder(questionLayout, container.NewPadded(buttonLayout), nil, nil, text)
+	questionWindow := container.NewBorder(questionLayout, container.NewPadded(container.NewVBox(useDocuments, buttonLayout)), nil, nil, text)
 	question.SetContent(container.NewVScroll(questionWindow))
 	question.Canvas().Focus(text)
 	question.Show()
@@ -123,7 +133,7 @@ func AskQuestionContainer(guiApp fyne.App, tabs *container.AppTabs, ollamaClient
 		}
 		loadingScreen.Hide()
 		// TODO: Add tab, add tab close/save buttons, copy button should be with text response
-		clippy.QuestionPopUp(guiApp, ollamaClient, s, &response)
+		clippy.ShowResult(guiApp, ollamaClient, clippy.Result{Input: s, Response: &response})
 	}
 	text.Validator = func(s string) error {
 		if len(s) < 10 {
@@ -161,3 +171,11 @@ func AskQuestionContainer(guiApp fyne.App, tabs *container.AppTabs, ollamaClient
 	questionWindow := container.NewBorder(questionLayout, buttonLayout, nil, nil, text)
 	return questionWindow
 }
+
+// askAI answers from the indexed documents when the user asked for it.
+func askAI(guiApp fyne.App, ollamaClient *ollamaApi.Client, question string, fromDocuments bool) (ollamaApi.GenerateResponse, error) {
+	if fromDocuments {
+		return documents.Ask(guiApp, ollamaClient, question)
+	}
+	return ollama.AskAI(guiApp, ollamaClient, question)
+}
@@ -0,0 +1,192 @@
+package review
+
+import (
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/bahelit/ctrl_plus_revise/internal/gui/metrics"
+	"github.com/bahelit/ctrl_plus_revise/pkg/worddiff"
+)
+
+// Actions are called when the user picks what to do with the revision,
+// the window is already closed when they run.
+type Actions struct {
+	// Accept pastes the text over the selection.
+	Accept func(text string)
+	// Copy puts the text in the clipboard without pasting.
+	Copy func(text string)
+	// Retry asks the AI for another revision.
+	Retry func()
+	// Discard leaves the selection as it was.
+	Discard func()
+}
+
+// ShowReview opens a compact window with the word level changes between the
+// selected text and the AI revision, every button has a keyboard shortcut so
+// the review doesn't need the mouse.
+func ShowReview(guiApp fyne.App, original, revised string, actions Actions) {
+	slog.Debug("Reviewing changes")
+	w := guiApp.NewWindow("Ctrl+Revise Review Changes")
+	w.Resize(fyne.NewSize(640, 420))
+
+	diff := worddiff.Words(original, revised)
+	accepted := make([]bool, diff.Hunks)
+	for i := range accepted {
+		accepted[i] = true
+	}
+
+	preview := widget.NewRichText()
+	preview.Wrapping = fyne.TextWrapWord
+	renderPreview := func() {
+		preview.Segments = diffSegments(diff, accepted)
+		preview.Refresh()
+	}
+	renderPreview()
+
+	hunkChecks := container.NewVBox()
+	checks := make([]*widget.Check, diff.Hunks)
+	for i := 0; i < diff.Hunks; i++ {
+		hunk := i
+		removed, added := diff.Change(hunk)
+		checks[hunk] = widget.NewCheck(hunkLabel(hunk, removed, added), func(b bool) {
+			accepted[hunk] = b
+			renderPreview()
+		})
+		checks[hunk].Checked = true
+		hunkChecks.Add(checks[hunk])
+	}
+
+	selectedText := func() string {
+		return diff.Apply(func(hunk int) bool { return accepted[hunk] })
+	}
+	closeAnd := func(action func()) func() {
+		return func() {
+			w.Close()
+			if action != nil {
+				action()
+			}
+		}
+	}
+	accept := closeAnd(func() {
+		if actions.Accept != nil {
+			actions.Accept(diff.Revised())
+		}
+	})
+	acceptSelected := closeAnd(func() {
+		if actions.Accept != nil {
+			actions.Accept(selectedText())
+		}
+	})
+	copyText := closeAnd(func() {
+		if actions.Copy != nil {
+			actions.Copy(selectedText())
+		}
+	})
+	retry := closeAnd(actions.Retry)
+	discard := closeAnd(actions.Discard)
+
+	acceptButton := widget.NewButtonWithIcon("Accept (Enter)", theme.ConfirmIcon(), accept)
+	acceptButton.Importance = widget.HighImportance
+	buttons := container.NewGridWithColumns(5,
+		acceptButton,
+		widget.NewButtonWithIcon("Accept Selected (Ctrl+Enter)", theme.ContentPasteIcon(), acceptSelected),
+		widget.NewButtonWithIcon("Copy (Ctrl+C)", theme.ContentCopyIcon(), copyText),
+		widget.NewButtonWithIcon("Retry (Ctrl+R)", theme.ViewRefreshIcon(), retry),
+		widget.NewButtonWithIcon("Discard (Esc)", theme.CancelIcon(), discard),
+	)
+
+	w.Canvas().SetOnTypedKey(func(e *fyne.KeyEvent) {
+		switch e.Name {
+		case fyne.KeyReturn, fyne.KeyEnter:
+			accept()
+		case fyne.KeyEscape:
+			discard()
+		}
+	})
+	w.Canvas().SetOnTypedRune(func(r rune) {
+		// 1-9 toggle the matching change
+		hunk := int(r - '1')
+		if hunk >= 0 && hunk < len(checks) && hunk < 9 {
+			checks[hunk].SetChecked(!checks[hunk].Checked)
+		}
+	})
+	addShortcut(w, fyne.KeyReturn, acceptSelected)
+	addShortcut(w, fyne.KeyEnter, acceptSelected)
+	// the driver turns Ctrl+C into the copy shortcut before custom shortcuts are checked
+	w.Canvas().AddShortcut(&fyne.ShortcutCopy{}, func(fyne.Shortcut) { copyText() })
+	addShortcut(w, fyne.KeyR, retry)
+	w.SetCloseIntercept(discard)
+
+	summary := widget.NewLabel(strconv.Itoa(diff.Hunks) + " changes, press 1-9 to toggle a change")
+	summary.TextStyle = fyne.TextStyle{Italic: true}
+	if diff.Hunks == 0 {
+		summary.SetText("The AI didn't change anything")
+	}
+
+	readability := widget.NewAccordion(widget.NewAccordionItem("Readability", metrics.Table(original, revised)))
+	content := container.NewVSplit(container.NewVScroll(preview),
+		container.NewVScroll(container.NewVBox(hunkChecks, readability)))
+	content.SetOffset(0.65)
+	w.SetContent(container.NewBorder(summary, buttons, nil, nil, content))
+	w.Show()
+	w.RequestFocus()
+}
+
+func addShortcut(w fyne.Window, key fyne.KeyName, action func()) {
+	w.Canvas().AddShortcut(&desktop.CustomShortcut{KeyName: key, Modifier: fyne.KeyModifierShortcutDefault},
+		func(fyne.Shortcut) { action() })
+}
+
+// diffSegments colours removed text red and added text green, changes that
+// are not accepted show the original text only.
+func diffSegments(diff worddiff.Diff, accepted []bool) []widget.RichTextSegment {
+	var segments []widget.RichTextSegment
+	for _, seg := range diff.Segments {
+		style := widget.RichTextStyleInline
+		switch seg.Kind {
+		case worddiff.Delete:
+			if accepted[seg.Hunk] {
+				style.ColorName = theme.ColorNameError
+				style.TextStyle = fyne.TextStyle{Italic: true}
+			}
+		case worddiff.Insert:
+			if !accepted[seg.Hunk] {
+				continue
+			}
+			style.ColorName = theme.ColorNameSuccess
+			style.TextStyle = fyne.TextStyle{Bold: true}
+		}
+		// RichText doesn't wrap text segments on new lines, split them out
+		for i, line := range strings.Split(seg.Text, "\n") {
+			if i > 0 {
+				segments = append(segments, &widget.TextSegment{Style: widget.RichTextStyleParagraph})
+			}
+			if line != "" {
+				segments = append(segments, &widget.TextSegment{Style: style, Text: line})
+			}
+		}
+	}
+	return segments
+}
+
+func hunkLabel(hunk int, removed, added string) string {
+	const maxLen = 40
+	shorten := func(s string) string {
+		s = strings.Join(strings.Fields(s), " ")
+		if len([]rune(s)) > maxLen {
+			return string([]rune(s)[:maxLen]) + "…"
+		}
+		if s == "" {
+			return "∅"
+		}
+		return s
+	}
+	return strconv.Itoa(hunk+1) + ": " + shorten(removed) + " → " + shorten(added)
+}
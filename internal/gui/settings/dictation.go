@@ -0,0 +1,62 @@
+package settings
+
+import (
+	"log/slog"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/bahelit/ctrl_plus_revise/internal/config"
+	"github.com/bahelit/ctrl_plus_revise/pkg/transcribe"
+)
+
+// ShowDictationSettings opens the speech-to-text settings window.
+func ShowDictationSettings(guiApp fyne.App) {
+	slog.Debug("Showing dictation settings")
+	dictationWindow := guiApp.NewWindow("Ctrl+Revise Dictation Settings")
+	dictationWindow.Resize(fyne.NewSize(520, 320))
+
+	enableCheckbox := widget.NewCheck("Hold Alt + D to dictate into the focused application", func(b bool) {
+		guiApp.Preferences().SetBool(config.DictationKey, b)
+	})
+	enableCheckbox.Checked = guiApp.Preferences().BoolWithFallback(config.DictationKey, false)
+
+	applyPromptCheckbox := widget.NewCheck("Run the transcript through the selected AI action before pasting", func(b bool) {
+		guiApp.Preferences().SetBool(config.DictationApplyPromptKey, b)
+	})
+	applyPromptCheckbox.Checked = guiApp.Preferences().BoolWithFallback(config.DictationApplyPromptKey, false)
+
+	serverEntry := preferenceEntry(guiApp, config.WhisperURLKey, "", "http://127.0.0.1:8080")
+	binaryEntry := preferenceEntry(guiApp, config.WhisperBinaryKey, "whisper-cli", "Path to whisper-cli")
+	modelEntry := preferenceEntry(guiApp, config.WhisperModelKey, "", "Path to a ggml model, e.g. ggml-base.en.bin")
+	languageEntry := preferenceEntry(guiApp, config.WhisperLanguageKey, "auto", "auto or a language code such as en")
+
+	note := widget.NewLabel("Leave the server empty to run the whisper.cpp binary on each recording.")
+	note.Wrapping = fyne.TextWrapWord
+	if !(&transcribe.Recorder{}).Available() {
+		note.SetText(transcribe.ErrNoRecorder.Error())
+		note.Importance = widget.DangerImportance
+	}
+
+	form := container.New(layout.NewFormLayout(),
+		widget.NewLabel("Whisper server:"), serverEntry,
+		widget.NewLabel("Whisper binary:"), binaryEntry,
+		widget.NewLabel("Whisper model:"), modelEntry,
+		widget.NewLabel("Language:"), languageEntry,
+	)
+	dictationWindow.SetContent(container.NewVBox(enableCheckbox, applyPromptCheckbox, form, note))
+	dictationWindow.Show()
+}
+
+// preferenceEntry creates an entry that saves its text to the preference key as it is typed.
+func preferenceEntry(guiApp fyne.App, key, fallback, placeHolder string) *widget.Entry {
+	entry := widget.NewEntry()
+	entry.SetPlaceHolder(placeHolder)
+	entry.SetText(guiApp.Preferences().StringWithFallback(key, fallback))
+	entry.OnChanged = func(s string) {
+		guiApp.Preferences().SetString(key, s)
+	}
+	return entry
+}
@@ -0,0 +1,63 @@
+package settings
+
+import (
+	"log/slog"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	ollamaApi "github.com/ollama/ollama/api"
+
+	"github.com/bahelit/ctrl_plus_revise/internal/gui/modules"
+)
+
+// ShowModuleSettings enables and disables the feature modules and opens
+// their settings.
+func ShowModuleSettings(guiApp fyne.App, ollamaClient *ollamaApi.Client) {
+	slog.Debug("Showing module settings")
+	w := guiApp.NewWindow("Ctrl+Revise Modules")
+
+	rows := container.NewVBox()
+	for _, m := range modules.All() {
+		enabled := widget.NewCheck(m.Name, func(on bool) {
+			modules.SetEnabled(guiApp, m.ID, on)
+		})
+		enabled.SetChecked(modules.IsEnabled(guiApp, m.ID))
+		description := widget.NewLabel(m.Description)
+		description.Wrapping = fyne.TextWrapWord
+		for _, hotkey := range m.Hotkeys {
+			description.SetText(description.Text + "\n" + hotkey.Name + ": " + keysText(hotkey.Keys))
+		}
+
+		row := container.NewHBox(widget.NewIcon(m.Icon), enabled, layout.NewSpacer())
+		if m.Settings != nil {
+			row.Add(widget.NewButtonWithIcon("Settings", theme.SettingsIcon(), func() {
+				m.Settings(guiApp, ollamaClient)
+			}))
+		}
+		rows.Add(widget.NewCard("", "", container.NewVBox(row, description)))
+	}
+
+	info := widget.NewLabel("Disabled modules are hidden from the system tray and the home screen and their hotkeys do nothing.")
+	info.Wrapping = fyne.TextWrapWord
+	w.SetContent(container.NewBorder(info, nil, nil, nil, container.NewVScroll(rows)))
+	w.Resize(fyne.NewSize(520, 520))
+	w.Show()
+}
+
+// keysText shows the keys of a hotkey the way users press them, the modifiers
+// first, e.g. "ALT + SHIFT + Q".
+func keysText(keys []string) string {
+	if len(keys) == 0 {
+		return ""
+	}
+	ordered := make([]string, 0, len(keys))
+	for _, k := range keys[1:] {
+		ordered = append(ordered, strings.ToUpper(k))
+	}
+	ordered = append(ordered, strings.ToUpper(keys[0]))
+	return strings.Join(ordered, " + ")
+}
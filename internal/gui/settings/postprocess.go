@@ -0,0 +1,50 @@
+package settings
+
+import (
+	"log/slog"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/bahelit/ctrl_plus_revise/internal/gui/delivery"
+	"github.com/bahelit/ctrl_plus_revise/pkg/postprocess"
+)
+
+// ShowPostProcessSettings opens the window for choosing how responses are
+// cleaned up before they are pasted, copied or shown.
+func ShowPostProcessSettings(guiApp fyne.App) {
+	slog.Debug("Showing response clean-up settings")
+	w := guiApp.NewWindow("Ctrl+Revise Response Clean-up")
+	w.Resize(fyne.NewSize(560, 320))
+
+	enabled := map[postprocess.Rule]bool{}
+	for _, r := range delivery.PostProcessRules(guiApp) {
+		enabled[r] = true
+	}
+	save := func() {
+		var rules []postprocess.Rule
+		for _, r := range postprocess.Rules() {
+			if enabled[r] {
+				rules = append(rules, r)
+			}
+		}
+		delivery.SetPostProcessRules(guiApp, rules)
+	}
+
+	checks := container.NewVBox()
+	for _, r := range postprocess.Rules() {
+		check := widget.NewCheck(r.Description(), func(on bool) {
+			enabled[r] = on
+			save()
+		})
+		check.SetChecked(enabled[r])
+		checks.Add(check)
+	}
+
+	note := widget.NewLabel("Small models often ignore the request to answer with the text only, " +
+		"these rules clean up the response before it replaces the highlighted text.")
+	note.Wrapping = fyne.TextWrapWord
+	w.SetContent(container.NewVBox(note, checks))
+	w.Show()
+}
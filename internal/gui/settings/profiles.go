@@ -0,0 +1,255 @@
+package settings
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/bahelit/ctrl_plus_revise/internal/gui"
+	"github.com/bahelit/ctrl_plus_revise/internal/gui/delivery"
+	"github.com/bahelit/ctrl_plus_revise/internal/gui/profiles"
+	"github.com/bahelit/ctrl_plus_revise/internal/ollama"
+	"github.com/bahelit/ctrl_plus_revise/pkg/appprofile"
+)
+
+// useMySetting is offered in every drop-down of a profile to keep the setting
+// of the fallback profile or the settings window.
+const useMySetting = "Use my setting"
+
+// ShowProfileSettings opens the editor for the application profiles, the
+// first profile matching the focused window is used by the keyboard shortcuts.
+func ShowProfileSettings(guiApp fyne.App) {
+	slog.Debug("Showing application profiles")
+	w := guiApp.NewWindow("Ctrl+Revise Application Profiles")
+	w.Resize(fyne.NewSize(860, 520))
+
+	saved := profiles.Get(guiApp)
+	editor := newProfileEditor(guiApp)
+	// selected is the index in saved.Rules, -1 is the fallback profile
+	var selected = -1
+	list := widget.NewList(
+		func() int { return len(saved.Rules) + 1 },
+		func() fyne.CanvasObject { return widget.NewLabel("Profile Name") },
+		func(id widget.ListItemID, o fyne.CanvasObject) {
+			if id == 0 {
+				o.(*widget.Label).SetText(saved.Fallback.Name + " (no match)")
+				return
+			}
+			o.(*widget.Label).SetText(saved.Rules[id-1].Name)
+		})
+	list.OnSelected = func(id widget.ListItemID) {
+		selected = id - 1
+		if selected < 0 {
+			editor.edit(saved.Fallback, true)
+			return
+		}
+		editor.edit(saved.Rules[selected], false)
+	}
+
+	save := func() bool {
+		if err := profiles.Save(guiApp, saved); err != nil {
+			dialog.ShowError(err, w)
+			return false
+		}
+		list.Refresh()
+		return true
+	}
+
+	saveButton := widget.NewButtonWithIcon("Save", theme.DocumentSaveIcon(), func() {
+		p := editor.profile()
+		if selected < 0 {
+			p.Pattern = ""
+			saved.Fallback = p
+			save()
+			return
+		}
+		if strings.TrimSpace(p.Name) == "" || strings.TrimSpace(p.Pattern) == "" {
+			dialog.ShowInformation("Application Profiles", "The profile needs a name and an application", w)
+			return
+		}
+		if err := p.Valid(); err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		saved.Rules[selected] = p
+		save()
+	})
+	newButton := widget.NewButtonWithIcon("New", theme.ContentAddIcon(), func() {
+		saved.Rules = append(saved.Rules, appprofile.Profile{Name: fmt.Sprintf("Profile %d", len(saved.Rules)+1)})
+		list.Refresh()
+		list.Select(len(saved.Rules))
+	})
+	deleteButton := widget.NewButtonWithIcon("Delete", theme.DeleteIcon(), func() {
+		if selected < 0 {
+			return
+		}
+		saved.Rules = append(saved.Rules[:selected], saved.Rules[selected+1:]...)
+		if save() {
+			list.UnselectAll()
+			selected = -1
+			editor.edit(appprofile.Profile{}, false)
+		}
+	})
+	move := func(by int) {
+		to := selected + by
+		if selected < 0 || to < 0 || to >= len(saved.Rules) {
+			return
+		}
+		saved.Rules[selected], saved.Rules[to] = saved.Rules[to], saved.Rules[selected]
+		if save() {
+			list.Select(to + 1)
+		}
+	}
+	upButton := widget.NewButtonWithIcon("", theme.MoveUpIcon(), func() { move(-1) })
+	downButton := widget.NewButtonWithIcon("", theme.MoveDownIcon(), func() { move(1) })
+
+	buttons := container.NewHBox(newButton, deleteButton, upButton, downButton, layout.NewSpacer(), saveButton)
+	note := widget.NewLabel("Profiles are checked from the top, the first one matching the application is used.")
+	note.Wrapping = fyne.TextWrapWord
+	right := container.NewBorder(nil, buttons, nil, nil,
+		container.NewVScroll(container.NewVBox(editor.content, widget.NewSeparator(), profileTester(guiApp))))
+	split := container.NewHSplit(container.NewBorder(nil, note, nil, nil, list), right)
+	split.Offset = 0.3
+	w.SetContent(split)
+	list.Select(0)
+	w.Show()
+}
+
+// profileTester shows which profile a window would use.
+func profileTester(guiApp fyne.App) fyne.CanvasObject {
+	process := widget.NewEntry()
+	process.SetPlaceHolder("slack")
+	title := widget.NewEntry()
+	title.SetPlaceHolder("general - Slack")
+	result := widget.NewLabel("")
+	result.Wrapping = fyne.TextWrapWord
+
+	test := func() {
+		window := appprofile.Window{Process: process.Text, Title: title.Text}
+		p, matched := profiles.Get(guiApp).Resolve(window)
+		if !matched {
+			result.SetText("No profile matches, the fallback profile is used.\n" + describeProfile(p))
+			return
+		}
+		result.SetText("Uses the profile \"" + p.Name + "\".\n" + describeProfile(p))
+	}
+	process.OnChanged = func(string) { test() }
+	title.OnChanged = func(string) { test() }
+
+	detect := widget.NewButton("Detect in 3 seconds", nil)
+	detect.OnTapped = func() {
+		detect.Disable()
+		go func() {
+			// Leave time to focus the window to test
+			time.Sleep(3 * time.Second)
+			window := profiles.ActiveWindow()
+			process.SetText(window.Process)
+			title.SetText(window.Title)
+			detect.Enable()
+		}()
+	}
+
+	form := container.New(layout.NewFormLayout(),
+		widget.NewLabel("Application:"), process,
+		widget.NewLabel("Window title:"), title,
+	)
+	heading := widget.NewLabelWithStyle("Test the profiles", fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	return container.NewVBox(heading, form, detect, result)
+}
+
+func describeProfile(p appprofile.Profile) string {
+	setting := func(v string) string {
+		if v == "" {
+			return useMySetting
+		}
+		return v
+	}
+	return "AI Action: " + setting(p.Prompt) +
+		"\nModel: " + setting(p.Model) +
+		"\nResponse: " + setting(p.Delivery) +
+		"\nTranslate to: " + setting(p.Language)
+}
+
+// profileEditor is the form for a single profile.
+type profileEditor struct {
+	name     *widget.Entry
+	pattern  *widget.Entry
+	prompt   *widget.Select
+	model    *widget.Select
+	delivery *widget.Select
+	language *widget.Select
+	content  fyne.CanvasObject
+}
+
+func newProfileEditor(guiApp fyne.App) *profileEditor {
+	e := &profileEditor{
+		name:     widget.NewEntry(),
+		pattern:  widget.NewEntry(),
+		prompt:   widget.NewSelect(append([]string{useMySetting}, copyActionOptions(guiApp)...), nil),
+		model:    widget.NewSelect(append([]string{useMySetting}, ollama.ModelNames()...), nil),
+		delivery: widget.NewSelect(append([]string{useMySetting}, delivery.ModeNames()...), nil),
+		language: widget.NewSelect(append([]string{useMySetting}, gui.ToLanguages(guiApp)...), nil),
+	}
+	e.name.SetPlaceHolder("Chat")
+	e.pattern.SetPlaceHolder("slack|discord|*teams*")
+	e.content = container.New(layout.NewFormLayout(),
+		widget.NewLabel("Name:"), e.name,
+		widget.NewLabel("Application or title:"), e.pattern,
+		widget.NewLabel("AI Action:"), e.prompt,
+		widget.NewLabel("Model:"), e.model,
+		widget.NewLabel("Response:"), e.delivery,
+		widget.NewLabel("Translate to:"), e.language,
+	)
+	return e
+}
+
+func (e *profileEditor) edit(p appprofile.Profile, fallback bool) {
+	e.name.SetText(p.Name)
+	e.pattern.SetText(p.Pattern)
+	if fallback {
+		e.name.Disable()
+		e.pattern.Disable()
+		e.pattern.SetPlaceHolder("Applications without a profile")
+	} else {
+		e.name.Enable()
+		e.pattern.Enable()
+		e.pattern.SetPlaceHolder("slack|discord|*teams*")
+	}
+	selectSetting(e.prompt, p.Prompt)
+	selectSetting(e.model, p.Model)
+	selectSetting(e.delivery, p.Delivery)
+	selectSetting(e.language, p.Language)
+}
+
+func (e *profileEditor) profile() appprofile.Profile {
+	return appprofile.Profile{
+		Name:     strings.TrimSpace(e.name.Text),
+		Pattern:  strings.TrimSpace(e.pattern.Text),
+		Prompt:   selectedSetting(e.prompt),
+		Model:    selectedSetting(e.model),
+		Delivery: selectedSetting(e.delivery),
+		Language: selectedSetting(e.language),
+	}
+}
+
+func selectSetting(s *widget.Select, value string) {
+	if value == "" {
+		value = useMySetting
+	}
+	s.SetSelected(value)
+}
+
+func selectedSetting(s *widget.Select) string {
+	if s.Selected == useMySetting {
+		return ""
+	}
+	return s.Selected
+}
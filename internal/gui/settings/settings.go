@@ -5,6 +5,8 @@ import (
 	"log/slog"
 	"os"
 	"os/exec"
+	"strconv"
+	"strings"
 	"time"
 
 	"fyne.io/fyne/v2"
@@ -18,7 +20,12 @@ import (
 	"github.com/bahelit/ctrl_plus_revise/internal/docker"
 	"github.com/bahelit/ctrl_plus_revise/internal/gui"
 	"github.com/bahelit/ctrl_plus_revise/internal/gui/bindings"
+	"github.com/bahelit/ctrl_plus_revise/internal/gui/delivery"
+	"github.com/bahelit/ctrl_plus_revise/internal/gui/glossary"
+	"github.com/bahelit/ctrl_plus_revise/internal/gui/profiles"
 	"github.com/bahelit/ctrl_plus_revise/internal/gui/shortcuts"
+	"github.com/bahelit/ctrl_plus_revise/internal/gui/templates"
+	"github.com/bahelit/ctrl_plus_revise/internal/gui/workflows"
 	"github.com/bahelit/ctrl_plus_revise/internal/ollama"
 )
 
@@ -32,41 +39,12 @@ func ShowSettings(guiApp fyne.App, ollamaClient *ollamaApi.Client) {
 
 	startUpCheckBox := showOnStartUpCheckBox(guiApp)
 	stopOllamaOnShutdownCheckbox := stopOllamaOnShutdownCheckBox(guiApp)
-	replaceHighlightedTextCheckBox := replaceHighlightedCheckbox(guiApp)
 	speakAIResponseTextCheckBox := speakAIResponseCheckbox(guiApp)
 	useDockerTextCheckBox := useDockerCheckBox(guiApp, ollamaClient)
-	showPopUpCheckBox := showPopUpCheckbox(guiApp)
-
-	replaceHighlightedTextCheckBox.OnChanged = func(b bool) {
-		if b {
-			guiApp.Preferences().SetBool(config.ReplaceHighlightedText, true)
-			//guiApp.Preferences().SetBool(config.ShowPopUpKey, false)
-			showPopUpCheckBox.Checked = false
-			showPopUpCheckBox.Refresh()
-			slog.Debug("Replace highlighted text is on")
-		} else {
-			slog.Debug("Replace highlighted text is off")
-			guiApp.Preferences().SetBool(config.ReplaceHighlightedText, false)
-		}
-	}
-	showPopUpCheckBox.OnChanged = func(b bool) {
-		if b {
-			//guiApp.Preferences().SetBool(config.ShowPopUpKey, true)
-			guiApp.Preferences().SetBool(config.ReplaceHighlightedText, false)
-			replaceHighlightedTextCheckBox.Checked = false
-			replaceHighlightedTextCheckBox.Refresh()
-			slog.Debug("Show Pop-Up is on")
-		} else {
-			slog.Debug("Show Pop-Up is off")
-			guiApp.Preferences().SetBool(config.ShowPopUpKey, false)
-		}
-	}
 
 	checkboxLayout := container.NewAdaptiveGrid(2,
 		layout.Responsive(speakAIResponseTextCheckBox),
-		layout.Responsive(replaceHighlightedTextCheckBox),
 		layout.Responsive(useDockerTextCheckBox),
-		layout.Responsive(showPopUpCheckBox),
 		layout.Responsive(startUpCheckBox),
 		layout.Responsive(stopOllamaOnShutdownCheckbox),
 	)
@@ -80,11 +58,51 @@ func ShowSettings(guiApp fyne.App, ollamaClient *ollamaApi.Client) {
 	downloadModel := widget.NewButton("Download/Update Model", func() {
 		_ = PullModelWrapper(guiApp, ollamaClient, true)
 	})
+	configureSpeech := widget.NewButton("Configure Speech", func() {
+		ShowSpeechSettings(guiApp)
+	})
+	configureDictation := widget.NewButton("Configure Dictation", func() {
+		ShowDictationSettings(guiApp)
+	})
+	configureTranslation := widget.NewButton("Configure Translation", func() {
+		ShowTranslationSettings(guiApp)
+	})
+	editGlossary := widget.NewButton("Edit Glossary", func() {
+		glossary.ShowGlossary(guiApp)
+	})
+	configurePostProcess := widget.NewButton("Response Clean-up", func() {
+		ShowPostProcessSettings(guiApp)
+	})
+	editWorkflows := widget.NewButton("Workflows", func() {
+		workflows.ShowWorkflows(guiApp, func() {
+			bindings.AiActionDropdown.SetOptions(copyActionOptions(guiApp))
+		})
+	})
+	configureProfiles := widget.NewButton("Application Profiles", func() {
+		ShowProfileSettings(guiApp)
+	})
+	configureModules := widget.NewButton("Modules", func() {
+		ShowModuleSettings(guiApp, ollamaClient)
+	})
+	editTemplates := widget.NewButton("Prompt Templates", func() {
+		templates.ShowTemplates(guiApp, func() {
+			bindings.AiActionDropdown.SetOptions(copyActionOptions(guiApp))
+		})
+	})
 
 	buttons := container.NewVBox(
 		keyboardShortcutsButton,
 		configureOllama,
 		downloadModel,
+		configureSpeech,
+		configureDictation,
+		configureTranslation,
+		editGlossary,
+		editTemplates,
+		editWorkflows,
+		configureProfiles,
+		configurePostProcess,
+		configureModules,
 	)
 
 	chooseActionLabel := widget.NewLabel("Choose what the AI should do to the highlighted text:")
@@ -107,6 +125,19 @@ func ShowSettings(guiApp fyne.App, ollamaClient *ollamaApi.Client) {
 		widget.NewLabel("To: "),
 		toLangDropdown,
 	)
+	contextLabel := widget.NewLabel("Choose how much text the AI reads at once (tokens)")
+	contextLabel.Alignment = fyne.TextAlignTrailing
+	contextDropdown := contextWindowDropDown(guiApp)
+	deliveryLabel := widget.NewLabel("Choose what happens with the AI response")
+	deliveryLabel.Alignment = fyne.TextAlignTrailing
+	deliveryDivider := container.NewHBox(
+		widget.NewLabel("Ask: "),
+		deliveryModeDropDown(guiApp, delivery.Ask),
+		widget.NewLabel("Revise: "),
+		deliveryModeDropDown(guiApp, delivery.Revise),
+		widget.NewLabel("Translate: "),
+		deliveryModeDropDown(guiApp, delivery.Translate),
+	)
 	dropDownMenu := container.NewAdaptiveGrid(2,
 		chooseActionLabel,
 		bindings.AiActionDropdown,
@@ -114,6 +145,10 @@ func ShowSettings(guiApp fyne.App, ollamaClient *ollamaApi.Client) {
 		bindings.AiModelDropdown,
 		chooseLanguageLabel,
 		langDivider,
+		contextLabel,
+		contextDropdown,
+		deliveryLabel,
+		deliveryDivider,
 	)
 
 	settingsWindow.SetContent(container.NewBorder(buttons, dropDownMenu, nil, nil, checkboxLayout))
@@ -169,7 +204,7 @@ func PullModelWrapper(guiApp fyne.App, ollamaClient *ollamaApi.Client, update bo
 
 func SelectTranslationFromDropDown(guiApp fyne.App) *widget.Select {
 	combo := widget.NewSelect(
-		gui.Languages,
+		gui.FromLanguages(guiApp),
 		func(value string) {
 			guiApp.Preferences().SetString(config.CurrentFromLangKey, value)
 			err := bindings.TranslationFromBinding.Set(value)
@@ -177,14 +212,14 @@ func SelectTranslationFromDropDown(guiApp fyne.App) *widget.Select {
 				slog.Error("Failed to set TranslationFromBinding", "error", err)
 			}
 		})
-	language := guiApp.Preferences().StringWithFallback(config.CurrentFromLangKey, string(ollama.English))
+	language := guiApp.Preferences().StringWithFallback(config.CurrentFromLangKey, string(ollama.AutoDetect))
 	combo.SetSelected(language)
 
 	return combo
 }
 
 func SelectTranslationToDropDown(guiApp fyne.App) *widget.Select {
-	combo := widget.NewSelect(gui.Languages,
+	combo := widget.NewSelect(gui.ToLanguages(guiApp),
 		func(value string) {
 			guiApp.Preferences().SetString(config.CurrentToLangKey, value)
 			err := bindings.TranslationToBinding.Set(value)
@@ -192,7 +227,7 @@ func SelectTranslationToDropDown(guiApp fyne.App) *widget.Select {
 				slog.Error("Failed to set TranslationToBinding", "error", err)
 			}
 		})
-	language := guiApp.Preferences().StringWithFallback(config.CurrentToLangKey, string(ollama.Spanish))
+	language := guiApp.Preferences().StringWithFallback(config.CurrentToLangKey, string(ollama.MyLanguage))
 	combo.SetSelected(language)
 
 	return combo
@@ -228,35 +263,28 @@ func stopOllamaOnShutdownCheckBox(guiApp fyne.App) *widget.Check {
 	return stopOllamaCheckbox
 }
 
-func replaceHighlightedCheckbox(guiApp fyne.App) *widget.Check {
-	replaceText := guiApp.Preferences().BoolWithFallback(config.ReplaceHighlightedText, true)
-	runOnCopy := widget.NewCheck("Paste AI Response", func(b bool) {
-		if !b {
-			slog.Debug("Replace highlighted checkbox is off")
-			guiApp.Preferences().SetBool(config.ReplaceHighlightedText, false)
-		} else if b {
-			slog.Debug("Replace highlighted checkbox is on")
-			guiApp.Preferences().SetBool(config.ReplaceHighlightedText, true)
+// contextWindowDropDown picks num_ctx, longer text is split into chunks that fit.
+func contextWindowDropDown(guiApp fyne.App) *widget.Select {
+	sizes := []string{"2048", "4096", "8192", "16384", "32768", "131072"}
+	combo := widget.NewSelect(sizes, func(value string) {
+		size, err := strconv.Atoi(value)
+		if err != nil {
+			slog.Error("Invalid context window", "value", value)
+			return
 		}
+		guiApp.Preferences().SetInt(config.NumCtxKey, size)
 	})
-	runOnCopy.Checked = replaceText
-	return runOnCopy
+	combo.SetSelected(strconv.Itoa(ollama.NumCtx(guiApp)))
+	return combo
 }
 
-func showPopUpCheckbox(guiApp fyne.App) *widget.Check {
-	//_ = guiApp.Preferences().BoolWithFallback(config.ShowPopUpKey, false)
-	popup := widget.NewCheck("Show Revise Window", func(b bool) {
-		if !b {
-			slog.Debug("Turning off PopUp mode")
-			//guiApp.Preferences().SetBool(config.ShowPopUpKey, false)
-		} else if b {
-			slog.Debug("Turning on PopUp mode")
-			//guiApp.Preferences().SetBool(config.ShowPopUpKey, true)
-		}
+func deliveryModeDropDown(guiApp fyne.App, action delivery.Action) *widget.Select {
+	dropdown := widget.NewSelect(delivery.ModeNames(), func(s string) {
+		slog.Debug("Delivery mode changed", "action", action, "mode", s)
+		delivery.Set(guiApp, action, delivery.ModeFromString(s))
 	})
-	popup.Disabled()
-	popup.Checked = false
-	return popup
+	dropdown.SetSelected(delivery.Get(guiApp, action).String())
+	return dropdown
 }
 
 func speakAIResponseCheckbox(guiApp fyne.App) *widget.Check {
@@ -400,8 +428,9 @@ func StopOllama(p *int) {
 	}
 }
 
-func selectCopyActionDropDown(guiApp fyne.App) *widget.Select {
-	combo := widget.NewSelect([]string{
+// copyActionOptions returns the built-in prompts followed by the user's templates.
+func copyActionOptions(guiApp fyne.App) []string {
+	options := []string{
 		ollama.CorrectGrammar.String(),
 		ollama.MakeItProfessional.String(),
 		ollama.MakeItFriendly.String(),
@@ -409,8 +438,32 @@ func selectCopyActionDropDown(guiApp fyne.App) *widget.Select {
 		ollama.MakeASummary.String(),
 		ollama.MakeExpanded.String(),
 		ollama.MakeExplanation.String(),
-		ollama.MakeItAList.String()},
+		ollama.MakeItAList.String(),
+		ollama.ExtractTags.String()}
+	for _, t := range ollama.PromptTemplates(guiApp) {
+		options = append(options, profiles.TemplatePrefix+t.Name)
+	}
+	for _, w := range workflows.Get(guiApp) {
+		options = append(options, profiles.WorkflowPrefix+w.Name)
+	}
+	return options
+}
+
+func selectCopyActionDropDown(guiApp fyne.App) *widget.Select {
+	combo := widget.NewSelect(copyActionOptions(guiApp),
 		func(value string) {
+			var template, workflow string
+			if name, ok := strings.CutPrefix(value, profiles.TemplatePrefix); ok {
+				template = name
+			}
+			if name, ok := strings.CutPrefix(value, profiles.WorkflowPrefix); ok {
+				workflow = name
+			}
+			guiApp.Preferences().SetString(config.CurrentTemplateKey, template)
+			guiApp.Preferences().SetString(config.CurrentWorkflowKey, workflow)
+			if template != "" || workflow != "" {
+				return
+			}
 			var selectedPrompt ollama.PromptMsg
 			switch value {
 			case ollama.CorrectGrammar.String():
@@ -429,6 +482,8 @@ func selectCopyActionDropDown(guiApp fyne.App) *widget.Select {
 				selectedPrompt = ollama.MakeExplanation
 			case ollama.MakeItAList.String():
 				selectedPrompt = ollama.MakeItAList
+			case ollama.ExtractTags.String():
+				selectedPrompt = ollama.ExtractTags
 			default:
 				slog.Error("Invalid selection", "value", value)
 				selectedPrompt = ollama.CorrectGrammar
@@ -440,6 +495,12 @@ func selectCopyActionDropDown(guiApp fyne.App) *widget.Select {
 			}
 		})
 	prompt := guiApp.Preferences().StringWithFallback(config.CurrentPromptKey, ollama.CorrectGrammar.String())
+	if tmpl := guiApp.Preferences().String(config.CurrentTemplateKey); tmpl != "" {
+		prompt = profiles.TemplatePrefix + tmpl
+	}
+	if workflow := guiApp.Preferences().String(config.CurrentWorkflowKey); workflow != "" {
+		prompt = profiles.WorkflowPrefix + workflow
+	}
 	combo.SetSelected(prompt)
 
 	return combo
@@ -0,0 +1,103 @@
+package settings
+
+import (
+	"log/slog"
+	"strconv"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/data/binding"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/bahelit/ctrl_plus_revise/internal/config"
+	"github.com/bahelit/ctrl_plus_revise/internal/gui/shortcuts"
+	"github.com/bahelit/ctrl_plus_revise/pkg/speech"
+)
+
+const (
+	defaultSpeechRate = 175
+	noSpeechEngine    = "No engine found, install espeak-ng or piper"
+)
+
+// ShowSpeechSettings opens the text-to-speech settings window.
+func ShowSpeechSettings(guiApp fyne.App) {
+	slog.Debug("Showing speech settings")
+	speechWindow := guiApp.NewWindow("Ctrl+Revise Speech Settings")
+	speechWindow.Resize(fyne.NewSize(480, 300))
+
+	var engineNames []string
+	for _, e := range speech.Detect() {
+		engineNames = append(engineNames, e.Name())
+	}
+	if len(engineNames) == 0 {
+		engineNames = append(engineNames, noSpeechEngine)
+	}
+	engineDropdown := widget.NewSelect(engineNames, func(value string) {
+		if value == noSpeechEngine {
+			return
+		}
+		guiApp.Preferences().SetString(config.SpeechEngineKey, value)
+		shortcuts.SetupSpeech(guiApp)
+	})
+	engineDropdown.SetSelected(guiApp.Preferences().StringWithFallback(config.SpeechEngineKey, engineNames[0]))
+
+	voiceEntry := widget.NewEntry()
+	voiceEntry.SetPlaceHolder("Engine default, for piper the path to a .onnx voice")
+	voiceEntry.SetText(guiApp.Preferences().String(config.SpeechVoiceKey))
+	voiceEntry.OnChanged = func(s string) {
+		guiApp.Preferences().SetString(config.SpeechVoiceKey, s)
+		shortcuts.SetupSpeech(guiApp)
+	}
+
+	languageEntry := widget.NewEntry()
+	languageEntry.SetPlaceHolder("Language tag, e.g. en-US")
+	languageEntry.SetText(guiApp.Preferences().StringWithFallback(config.SpeechLanguageKey, "en"))
+	languageEntry.OnChanged = func(s string) {
+		guiApp.Preferences().SetString(config.SpeechLanguageKey, s)
+		shortcuts.SetupSpeech(guiApp)
+	}
+
+	rate := binding.NewFloat()
+	_ = rate.Set(float64(guiApp.Preferences().IntWithFallback(config.SpeechRateKey, defaultSpeechRate)))
+	rateSlider := widget.NewSliderWithData(80, 400, rate)
+	rateSlider.Step = 5
+	rateLabel := widget.NewLabel("")
+	rateSlider.OnChanged = func(f float64) {
+		_ = rate.Set(f)
+		rateLabel.SetText(strconv.Itoa(int(f)) + " words per minute")
+	}
+	rateSlider.OnChangeEnded = func(f float64) {
+		guiApp.Preferences().SetInt(config.SpeechRateKey, int(f))
+		shortcuts.SetupSpeech(guiApp)
+	}
+	rateSlider.OnChanged(rateSlider.Value)
+
+	readResponseCheckbox := widget.NewCheck("Read AI responses aloud automatically", func(b bool) {
+		guiApp.Preferences().SetBool(config.ReadAIResponseKey, b)
+	})
+	readResponseCheckbox.Checked = guiApp.Preferences().BoolWithFallback(config.ReadAIResponseKey, false)
+
+	testButton := widget.NewButton("Test Voice", func() {
+		if shortcuts.Speech == nil {
+			return
+		}
+		shortcuts.Speech.Interrupt()
+		shortcuts.Speech.Say("Control Plus Revise is ready to help you!")
+	})
+	stopButton := widget.NewButton("Stop (Alt + S)", func() {
+		if shortcuts.Speech != nil {
+			shortcuts.Speech.Interrupt()
+		}
+	})
+
+	form := container.New(layout.NewFormLayout(),
+		widget.NewLabel("Engine:"), engineDropdown,
+		widget.NewLabel("Voice:"), voiceEntry,
+		widget.NewLabel("Language:"), languageEntry,
+		widget.NewLabel("Speed:"), container.NewBorder(nil, nil, nil, rateLabel, rateSlider),
+	)
+	buttons := container.NewGridWithColumns(2, testButton, stopButton)
+	speechWindow.SetContent(container.NewVBox(form, readResponseCheckbox, buttons))
+	speechWindow.Show()
+}
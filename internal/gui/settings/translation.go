@@ -0,0 +1,67 @@
+package settings
+
+import (
+	"log/slog"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/bahelit/ctrl_plus_revise/internal/config"
+	"github.com/bahelit/ctrl_plus_revise/internal/gui"
+	"github.com/bahelit/ctrl_plus_revise/internal/ollama"
+)
+
+// ShowTranslationSettings opens the window for adding languages and choosing
+// the user's own languages.
+func ShowTranslationSettings(guiApp fyne.App) {
+	slog.Debug("Showing translation settings")
+	translationWindow := guiApp.NewWindow("Ctrl+Revise Translation Settings")
+	translationWindow.Resize(fyne.NewSize(520, 260))
+
+	myLanguage := languagePreferenceDropDown(guiApp, config.MyLanguageKey, ollama.English)
+	secondLanguage := languagePreferenceDropDown(guiApp, config.SecondLanguageKey, ollama.Spanish)
+
+	status := widget.NewLabel("")
+	status.Wrapping = fyne.TextWrapWord
+	tagEntry := widget.NewEntry()
+	tagEntry.SetPlaceHolder("A BCP-47 language code such as sw, uk or pt-BR")
+	addLanguage := func() {
+		added, err := ollama.AddTranslationLanguage(guiApp, tagEntry.Text)
+		if err != nil {
+			status.SetText("Unknown language code: " + tagEntry.Text)
+			status.Importance = widget.DangerImportance
+			status.Refresh()
+			return
+		}
+		status.SetText(string(added) + " can now be picked for translation")
+		status.Importance = widget.SuccessImportance
+		status.Refresh()
+		tagEntry.SetText("")
+		myLanguage.SetOptions(gui.Languages(guiApp))
+		secondLanguage.SetOptions(gui.Languages(guiApp))
+	}
+	tagEntry.OnSubmitted = func(string) { addLanguage() }
+	addButton := widget.NewButton("Add", addLanguage)
+
+	note := widget.NewLabel("When translating to \"" + string(ollama.MyLanguage) +
+		"\", text in your language is translated to your second language instead.")
+	note.Wrapping = fyne.TextWrapWord
+
+	form := container.New(layout.NewFormLayout(),
+		widget.NewLabel("My language:"), myLanguage,
+		widget.NewLabel("Second language:"), secondLanguage,
+		widget.NewLabel("Add a language:"), container.NewBorder(nil, nil, nil, addButton, tagEntry),
+	)
+	translationWindow.SetContent(container.NewVBox(form, status, note))
+	translationWindow.Show()
+}
+
+func languagePreferenceDropDown(guiApp fyne.App, key string, fallback ollama.Language) *widget.Select {
+	combo := widget.NewSelect(gui.Languages(guiApp), func(value string) {
+		guiApp.Preferences().SetString(key, value)
+	})
+	combo.SetSelected(guiApp.Preferences().StringWithFallback(key, string(fallback)))
+	return combo
+}
@@ -0,0 +1,130 @@
+package shortcuts
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"log/slog"
+	"os"
+	"time"
+
+	"fyne.io/fyne/v2"
+	ollamaApi "github.com/ollama/ollama/api"
+	hook "github.com/robotn/gohook"
+
+	"github.com/bahelit/ctrl_plus_revise/internal/config"
+	"github.com/bahelit/ctrl_plus_revise/internal/gui/loading"
+	"github.com/bahelit/ctrl_plus_revise/internal/ollama"
+	"github.com/bahelit/ctrl_plus_revise/pkg/clipboard"
+	"github.com/bahelit/ctrl_plus_revise/pkg/transcribe"
+)
+
+var (
+	recorder             = &transcribe.Recorder{}
+	dictationKey         = "d"
+	transcriptionTimeout = 2 * time.Minute
+)
+
+// registerDictationHotkeys sets up push-to-talk, holding Alt + D records and
+// releasing either key pastes the transcript.
+func registerDictationHotkeys(guiApp fyne.App, ollamaClient *ollamaApi.Client) {
+	hook.Register(hook.KeyDown, []string{dictationKey, "alt"}, func(e hook.Event) {
+		if !guiApp.Preferences().BoolWithFallback(config.DictationKey, false) || recorder.Recording() {
+			return
+		}
+		slog.Debug("dictationKey has been pressed", "event", e)
+		handleDictationPressed(guiApp)
+	})
+	// gohook only fires registered key up events while every key is still held,
+	// so listen to all releases and pick out the push-to-talk keys.
+	hook.Register(hook.KeyUp, []string{}, func(e hook.Event) {
+		if !recorder.Recording() {
+			return
+		}
+		if e.Keycode != hook.Keycode[dictationKey] && e.Keycode != hook.Keycode["alt"] {
+			return
+		}
+		slog.Debug("dictationKey has been released", "event", e)
+		go handleDictationReleased(guiApp, ollamaClient)
+	})
+}
+
+func handleDictationPressed(guiApp fyne.App) {
+	err := recorder.Start()
+	if err != nil {
+		slog.Error("Failed to start recording", "error", err)
+		loading.ShowNotification(guiApp, "Dictation Error", "Failed to start recording\n"+err.Error())
+	}
+}
+
+func handleDictationReleased(guiApp fyne.App, ollamaClient *ollamaApi.Client) {
+	wavPath, err := recorder.Stop()
+	if err != nil {
+		slog.Error("Failed to stop recording", "error", err)
+		return
+	}
+	defer os.Remove(wavPath)
+
+	err = Throttle.Do()
+	if err != nil {
+		slog.Error("Failed to create throttle", "error", err)
+	}
+	defer Throttle.Done(err)
+
+	loadingScreen := loading.LoadingScreenWithMessage(guiApp, loading.ThinkingMsg, "Transcribing dictation...")
+	loadingScreen.Show()
+	ctx, cancel := context.WithTimeout(context.Background(), transcriptionTimeout)
+	defer cancel()
+	text, err := Transcriber(guiApp).Transcribe(ctx, wavPath)
+	loadingScreen.Hide()
+	if errors.Is(err, transcribe.ErrTooShort) {
+		slog.Info("Ignoring short recording")
+		return
+	}
+	if err != nil {
+		slog.Error("Failed to transcribe", "error", err)
+		loading.ShowNotification(guiApp, "Dictation Error", "Failed to transcribe the recording\n"+
+			"Check the speech-to-text settings")
+		return
+	}
+	if text == "" {
+		slog.Info("Nothing was heard")
+		return
+	}
+
+	if guiApp.Preferences().BoolWithFallback(config.DictationApplyPromptKey, false) {
+		loadingScreen = loading.LoadingScreenWithMessageAddModel(guiApp, loading.ThinkingMsg,
+			"Prompt: "+selectedPrompt.String()+"...")
+		loadingScreen.Show()
+		generated, err := ollama.AskAIToRevise(guiApp, ollamaClient, selectedPrompt, text)
+		loadingScreen.Hide()
+		if err != nil {
+			slog.Error("Failed to communicate with Ollama, pasting the transcript as is", "error", err)
+		} else {
+			text = generated.Response
+		}
+	}
+
+	LastClipboardContent = sha256.Sum256([]byte(text))
+	err = clipboard.WriteAll(text)
+	if err != nil {
+		slog.Error("Failed to write to clipboard", "error", err)
+		return
+	}
+	_ = pasteCommand()
+}
+
+// Transcriber returns the speech-to-text backend configured in the settings,
+// a whisper.cpp server when a URL is set, otherwise the whisper.cpp binary.
+func Transcriber(guiApp fyne.App) transcribe.Transcriber {
+	language := guiApp.Preferences().StringWithFallback(config.WhisperLanguageKey, "auto")
+	serverURL := guiApp.Preferences().String(config.WhisperURLKey)
+	if serverURL != "" {
+		return &transcribe.Server{URL: serverURL, Language: language}
+	}
+	return &transcribe.Binary{
+		Path:     guiApp.Preferences().StringWithFallback(config.WhisperBinaryKey, "whisper-cli"),
+		Model:    guiApp.Preferences().String(config.WhisperModelKey),
+		Language: language,
+	}
+}
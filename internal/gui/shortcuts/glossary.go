@@ -0,0 +1,29 @@
+package shortcuts
+
+import (
+	"log/slog"
+	"strings"
+
+	"fyne.io/fyne/v2"
+
+	"github.com/bahelit/ctrl_plus_revise/internal/ollama"
+)
+
+// flagGlossaryViolations warns the user when the AI changed a glossary term,
+// toLang is empty for revisions. Revisions are only checked for prompts that
+// keep the text, a summary or a headline is free to leave terms out.
+func flagGlossaryViolations(guiApp fyne.App, input, output string, toLang ollama.Language) {
+	violations := ollama.VerifyGlossary(input, output, toLang)
+	if len(violations) == 0 {
+		return
+	}
+	var problems []string
+	for _, v := range violations {
+		problems = append(problems, v.String())
+	}
+	slog.Warn("The AI changed glossary terms", "violations", problems)
+	guiApp.SendNotification(&fyne.Notification{
+		Title:   "Ctrl+Revise: Check the glossary terms",
+		Content: strings.Join(problems, "\n"),
+	})
+}
@@ -2,21 +2,28 @@ package shortcuts
 
 import (
 	"crypto/sha256"
-	"fyne.io/fyne/v2"
+	"errors"
+	"fmt"
 	"log/slog"
 	"time"
 
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/data/binding"
+	"fyne.io/fyne/v2/widget"
 	"github.com/go-vgo/robotgo"
-	htgotts "github.com/hegedustibor/htgo-tts"
 	"github.com/ollama/ollama/api"
 	ollamaApi "github.com/ollama/ollama/api"
 	hook "github.com/robotn/gohook"
 
 	"github.com/bahelit/ctrl_plus_revise/internal/config"
 	"github.com/bahelit/ctrl_plus_revise/internal/gui/bindings"
+	"github.com/bahelit/ctrl_plus_revise/internal/gui/clippy"
+	"github.com/bahelit/ctrl_plus_revise/internal/gui/delivery"
 	"github.com/bahelit/ctrl_plus_revise/internal/gui/loading"
 	"github.com/bahelit/ctrl_plus_revise/internal/ollama"
 	"github.com/bahelit/ctrl_plus_revise/pkg/clipboard"
+	"github.com/bahelit/ctrl_plus_revise/pkg/mask"
+	"github.com/bahelit/ctrl_plus_revise/pkg/speech"
 	"github.com/bahelit/ctrl_plus_revise/pkg/throttle"
 )
 
@@ -28,7 +35,7 @@ type keyBoardShortcut struct {
 
 var (
 	Throttle              = throttle.NewThrottle(1)
-	Speech                *htgotts.Speech
+	Speech                *speech.Queue
 	LastClipboardContent  [32]byte
 	lastKeyPressTime      = time.Now()
 	waitBetweenKeyPresses = 1 * time.Second
@@ -90,6 +97,12 @@ func RegisterHotkeys(guiApp fyne.App, ollamaClient *ollamaApi.Client) chan hook.
 		handleReadTextPressed(guiApp)
 		lastKeyPressTime = time.Now()
 	})
+	hook.Register(hook.KeyDown, []string{"s", "alt"}, func(e hook.Event) {
+		slog.Debug("stopSpeakingKey has been pressed", "event", e)
+		handleStopSpeakingPressed()
+	})
+	registerDictationHotkeys(guiApp, ollamaClient)
+	registerModuleHotkeys(guiApp, ollamaClient)
 	hook.Register(hook.KeyDown, GetTranslateKeys(), func(e hook.Event) {
 		slog.Debug("translateTextKey has been pressed", "event", e)
 		if time.Since(lastKeyPressTime) < waitBetweenKeyPresses {
@@ -160,6 +173,8 @@ func UpdateDropDownMenus() {
 
 func ChangedPromptNotification(guiApp fyne.App) {
 	guiApp.Preferences().SetString(config.CurrentPromptKey, selectedPrompt.String())
+	guiApp.Preferences().SetString(config.CurrentTemplateKey, "")
+	guiApp.Preferences().SetString(config.CurrentWorkflowKey, "")
 	guiApp.SendNotification(&fyne.Notification{
 		Title:   "AI Action Changed",
 		Content: "AI Action has been changed to:\n" + selectedPrompt.String(),
@@ -178,28 +193,26 @@ func handleReadTextPressed(guiApp fyne.App) {
 		return
 	}
 
+	if Speech == nil || Speech.Engine() == nil {
+		slog.Warn("No text-to-speech engine available")
+		return
+	}
+
 	err = copyCommand()
 	if err != nil {
-		if Speech != nil {
-			_ = Speech.Speak("Failed to copy text")
-		}
+		Speech.Say("Failed to copy text")
 		return
 	}
 
 	clip, err := clipboard.ReadAll()
 	if err != nil {
 		slog.Error("Failed to read clipboard", "error", err)
-		if Speech != nil {
-			_ = Speech.Speak("Failed to read clipboard")
-		}
+		Speech.Say("Failed to read clipboard")
 		return
 	}
 
-	_ = Speech.Speak("")
-	speakErr := Speech.Speak(clip)
-	if speakErr != nil {
-		slog.Error("Failed to speak", "error", speakErr)
-	}
+	Speech.Interrupt()
+	Speech.Say(clip)
 }
 
 func handleUserShortcutKeyPressed(guiApp fyne.App, ollamaClient *ollamaApi.Client) {
@@ -209,16 +222,35 @@ func handleUserShortcutKeyPressed(guiApp fyne.App, ollamaClient *ollamaApi.Clien
 	}
 	defer Throttle.Done(err)
 
+	guiApp, profile, window := applyProfile(guiApp)
+	if tmpl, ok := selectedTemplate(guiApp); ok {
+		handleTemplateKeyPressed(guiApp, ollamaClient, tmpl, window)
+		return
+	}
+	if wf, ok := selectedWorkflow(guiApp); ok {
+		handleWorkflowKeyPressed(guiApp, ollamaClient, wf)
+		return
+	}
+	prompt := profilePrompt(profile)
+
 	clip, copiedText := copyTextToClipboard()
 	if !copiedText {
 		return
 	}
 
-	loadingScreen := loading.LoadingScreenWithMessageAddModel(guiApp, loading.ThinkingMsg,
-		"Prompt: "+selectedPrompt.String()+"...")
+	loadingScreen, progress := promptLoadingScreen(guiApp, prompt, clip)
 	loadingScreen.Show()
 
-	generated, err := ollama.AskAIWithPromptMsg(guiApp, ollamaClient, selectedPrompt, clip)
+	generated, err := ollama.AskAIWithChunking(guiApp, ollamaClient, prompt, clip, progress)
+	if errors.Is(err, mask.ErrLostToken) {
+		slog.Error("Rejected the AI response", "error", err)
+		loadingScreen.Hide()
+		guiApp.SendNotification(&fyne.Notification{
+			Title:   "Ctrl+Revise: The text was left unchanged",
+			Content: "The AI response lost code, links or placeholders of the highlighted text.",
+		})
+		return
+	}
 	if err != nil {
 		// TODO: Implement error handling, tell user to restart ollama, maybe we can restart ollama here?
 		slog.Error("Failed to communicate with Ollama", "error", err)
@@ -227,7 +259,30 @@ func handleUserShortcutKeyPressed(guiApp fyne.App, ollamaClient *ollamaApi.Clien
 	}
 	loadingScreen.Hide()
 
-	handleGeneratedResponse(guiApp, ollamaClient, clip, &generated)
+	if prompt.PreservesStructure() {
+		flagGlossaryViolations(guiApp, clip, generated.Response, "")
+	}
+	handleGeneratedResponse(guiApp, ollamaClient, delivery.Revise, clip, &generated, func() (ollamaApi.GenerateResponse, error) {
+		return ollama.AskAIWithChunking(guiApp, ollamaClient, prompt, clip, nil)
+	})
+}
+
+// promptLoadingScreen shows the progress of text long enough to be split into
+// chunks, short text gets the usual loading screen.
+func promptLoadingScreen(guiApp fyne.App, prompt ollama.PromptMsg, text string) (fyne.Window, func(done, total int)) {
+	msg := "Prompt: " + prompt.String() + "..."
+	chunks := len(ollama.SplitForContext(guiApp, prompt, text))
+	if chunks == 1 {
+		return loading.LoadingScreenWithMessageAddModel(guiApp, loading.ThinkingMsg, msg), nil
+	}
+	bar := widget.NewProgressBar()
+	status := binding.NewString()
+	_ = status.Set(fmt.Sprintf("The text is long, working on it in %d parts", chunks))
+	screen := loading.LoadingScreenWithProgressAndMessage(guiApp, bar, status, loading.ThinkingMsg, msg)
+	return screen, func(done, total int) {
+		bar.SetValue(float64(done) / float64(total))
+		_ = status.Set(fmt.Sprintf("Finished %d of %d parts", done, total))
+	}
 }
 
 func handleAskKeyPressed(guiApp fyne.App, ollamaClient *ollamaApi.Client) {
@@ -237,6 +292,7 @@ func handleAskKeyPressed(guiApp fyne.App, ollamaClient *ollamaApi.Client) {
 	}
 	defer Throttle.Done(err)
 
+	guiApp, _, _ = applyProfile(guiApp)
 	clip, copiedText := copyTextToClipboard()
 	if !copiedText {
 		return
@@ -254,7 +310,9 @@ func handleAskKeyPressed(guiApp fyne.App, ollamaClient *ollamaApi.Client) {
 	}
 	loadingScreen.Hide()
 
-	handleGeneratedResponse(guiApp, ollamaClient, clip, &generated)
+	handleGeneratedResponse(guiApp, ollamaClient, delivery.Ask, clip, &generated, func() (ollamaApi.GenerateResponse, error) {
+		return ollama.AskAI(guiApp, ollamaClient, clip)
+	})
 }
 
 func handleTranslatePressed(guiApp fyne.App, ollamaClient *ollamaApi.Client) {
@@ -267,20 +325,22 @@ func handleTranslatePressed(guiApp fyne.App, ollamaClient *ollamaApi.Client) {
 		Throttle.Done(err)
 	}()
 
+	guiApp, _, _ = applyProfile(guiApp)
 	clip, copiedText := copyTextToClipboard()
 	if !copiedText {
 		return
 	}
 
-	fromLang := guiApp.Preferences().StringWithFallback(config.CurrentFromLangKey, string(ollama.English))
-	toLang := guiApp.Preferences().StringWithFallback(config.CurrentToLangKey, string(ollama.Spanish))
+	fromLang := guiApp.Preferences().StringWithFallback(config.CurrentFromLangKey, string(ollama.AutoDetect))
+	toLang := guiApp.Preferences().StringWithFallback(config.CurrentToLangKey, string(ollama.MyLanguage))
 
 	loadingScreen := loading.LoadingScreenWithMessageAddModel(guiApp, loading.ThinkingMsg,
 		"Translating")
 	loadingScreen.Show()
 
-	slog.Info("Translating text", "fromLang", fromLang, "toLang", toLang)
-	generated, err := ollama.AskAIToTranslate(guiApp, ollamaClient, clip, ollama.Language(fromLang), ollama.Language(toLang))
+	source, target := ollama.ResolveTranslation(guiApp, ollamaClient, clip, ollama.Language(fromLang), ollama.Language(toLang))
+	slog.Info("Translating text", "fromLang", source, "toLang", target)
+	generated, err := ollama.AskAIToTranslate(guiApp, ollamaClient, clip, source, target)
 	if err != nil {
 		slog.Error("Failed to ask AI", "error", err)
 		loadingScreen.Hide()
@@ -288,21 +348,10 @@ func handleTranslatePressed(guiApp fyne.App, ollamaClient *ollamaApi.Client) {
 	}
 	loadingScreen.Hide()
 
-	err = clipboard.WriteAll(generated.Response)
-	if err != nil {
-		slog.Error("Failed to write to clipboard", "error", err)
-		return
-	}
-	LastClipboardContent = sha256.Sum256([]byte(generated.Response))
-
-	// Send a paste command to the operating system
-	replaceText := guiApp.Preferences().BoolWithFallback(config.ReplaceHighlightedText, true)
-	if replaceText {
-		err = pasteCommand()
-		if err != nil {
-			return
-		}
-	}
+	flagGlossaryViolations(guiApp, clip, generated.Response, target)
+	handleGeneratedResponse(guiApp, ollamaClient, delivery.Translate, clip, &generated, func() (ollamaApi.GenerateResponse, error) {
+		return ollama.AskAIToTranslate(guiApp, ollamaClient, clip, source, target)
+	})
 }
 
 func copyCommand() error {
@@ -358,27 +407,62 @@ func copyTextToClipboard() (string, bool) {
 	return clip, true
 }
 
-func handleGeneratedResponse(guiApp fyne.App, ollamaClient *ollamaApi.Client, question string, response *api.GenerateResponse) {
+// handleGeneratedResponse delivers the response the way the user picked for
+// the action, regenerate is used when the user asks for another attempt.
+func handleGeneratedResponse(guiApp fyne.App, ollamaClient *ollamaApi.Client, action delivery.Action, input string,
+	response *api.GenerateResponse, regenerate regenerateFunc) {
+	deliverResponse(guiApp, ollamaClient, action, input, response, nil, regenerate)
+}
+
+// deliverResponse is handleGeneratedResponse for a workflow, the pop-up shows
+// the response of every step.
+func deliverResponse(guiApp fyne.App, ollamaClient *ollamaApi.Client, action delivery.Action, input string,
+	response *api.GenerateResponse, steps []clippy.Step, regenerate regenerateFunc) {
 	slog.Debug("LastClipboardContent", "LastClipboardContent", LastClipboardContent)
 
-	LastClipboardContent = sha256.Sum256([]byte(response.Response))
-	err := clipboard.WriteAll(response.Response)
-	if err != nil {
-		slog.Error("Failed to write to clipboard", "error", err)
+	response.Response = delivery.CleanUp(guiApp, input, response.Response)
+	if regenerate != nil {
+		generate := regenerate
+		regenerate = func() (ollamaApi.GenerateResponse, error) {
+			regenerated, err := generate()
+			regenerated.Response = delivery.CleanUp(guiApp, input, regenerated.Response)
+			return regenerated, err
+		}
+	}
+
+	mode := delivery.Get(guiApp, action)
+	if mode == delivery.Review && action == delivery.Ask {
+		// There is nothing to compare an answer against
+		mode = delivery.PopUp
+	}
+	if mode == delivery.Review {
+		showReview(guiApp, input, response, regenerate)
 		return
 	}
 
-	// Send a paste command to the operating system
-	replaceText := guiApp.Preferences().BoolWithFallback(config.ReplaceHighlightedText, true)
-	if replaceText {
-		err = pasteCommand()
-		if err != nil {
-			return
+	speakResponse(guiApp, response.Response)
+	if mode.CopiesToClipboard() && !writeClipboard(response.Response) {
+		return
+	}
+	if mode == delivery.Paste {
+		// Send a paste command to the operating system
+		_ = pasteCommand()
+		return
+	}
+	if mode.ShowsPopUp() {
+		heading := "Highlighted Text:"
+		if action == delivery.Ask {
+			heading = "Question:"
 		}
+		clippy.ShowResult(guiApp, ollamaClient, clippy.Result{
+			Heading:  heading,
+			Input:    input,
+			Revises:  action != delivery.Ask,
+			Response: response,
+			Steps:    steps,
+			OnCopy: func(text string) {
+				LastClipboardContent = sha256.Sum256([]byte(text))
+			},
+		})
 	}
-
-	//showPopUp := guiApp.Preferences().BoolWithFallback(config.ShowPopUpKey, false)
-	//if showPopUp {
-	//	clippy.QuestionPopUp(guiApp, ollamaClient, question, response)
-	//}
 }
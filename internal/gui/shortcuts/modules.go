@@ -0,0 +1,34 @@
+package shortcuts
+
+import (
+	"log/slog"
+	"time"
+
+	"fyne.io/fyne/v2"
+	ollamaApi "github.com/ollama/ollama/api"
+	hook "github.com/robotn/gohook"
+
+	"github.com/bahelit/ctrl_plus_revise/internal/gui/modules"
+)
+
+// registerModuleHotkeys registers the hotkeys of every module, a module
+// disabled later ignores its hotkeys until it is enabled again.
+func registerModuleHotkeys(guiApp fyne.App, ollamaClient *ollamaApi.Client) {
+	for _, m := range modules.All() {
+		for _, hotkey := range m.Hotkeys {
+			hook.Register(hook.KeyDown, hotkey.Keys, func(e hook.Event) {
+				slog.Debug("Module hotkey has been pressed", "module", m.ID, "hotkey", hotkey.Name, "event", e)
+				if !modules.IsEnabled(guiApp, m.ID) {
+					return
+				}
+				if time.Since(lastKeyPressTime) < waitBetweenKeyPresses {
+					slog.Info("Ignoring key press", "waitBetweenKeyPresses", waitBetweenKeyPresses)
+					lastKeyPressTime = time.Now()
+					return
+				}
+				lastKeyPressTime = time.Now()
+				hotkey.Action(guiApp, ollamaClient)
+			})
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package shortcuts
+
+import (
+	"log/slog"
+
+	"fyne.io/fyne/v2"
+
+	"github.com/bahelit/ctrl_plus_revise/internal/gui/profiles"
+	"github.com/bahelit/ctrl_plus_revise/internal/ollama"
+	"github.com/bahelit/ctrl_plus_revise/pkg/appprofile"
+)
+
+// applyProfile returns guiApp with the settings of the profile for the focused
+// window, it has to be called before a window of the app takes the focus.
+func applyProfile(guiApp fyne.App) (fyne.App, appprofile.Profile, appprofile.Window) {
+	window := profiles.ActiveWindow()
+	profile, matched := profiles.Get(guiApp).Resolve(window)
+	if matched {
+		slog.Info("Using application profile", "profile", profile.Name, "process", window.Process, "title", window.Title)
+	}
+	return profiles.Apply(guiApp, profile), profile, window
+}
+
+// profilePrompt returns the prompt of the profile, or the one picked by the user.
+func profilePrompt(profile appprofile.Profile) ollama.PromptMsg {
+	if prompt, ok := profiles.Prompt(profile); ok {
+		return prompt
+	}
+	return selectedPrompt
+}
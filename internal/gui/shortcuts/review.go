@@ -0,0 +1,65 @@
+package shortcuts
+
+import (
+	"crypto/sha256"
+	"log/slog"
+
+	"fyne.io/fyne/v2"
+	ollamaApi "github.com/ollama/ollama/api"
+
+	"github.com/bahelit/ctrl_plus_revise/internal/gui/loading"
+	"github.com/bahelit/ctrl_plus_revise/internal/gui/review"
+	"github.com/bahelit/ctrl_plus_revise/pkg/clipboard"
+)
+
+// regenerateFunc asks the AI for a new response to the same input.
+type regenerateFunc func() (ollamaApi.GenerateResponse, error)
+
+// showReview lets the user look over the changes before they replace the
+// highlighted text.
+func showReview(guiApp fyne.App, original string, response *ollamaApi.GenerateResponse, regenerate regenerateFunc) {
+	speakResponse(guiApp, response.Response)
+	review.ShowReview(guiApp, original, response.Response, review.Actions{
+		Accept: func(text string) {
+			if writeClipboard(text) {
+				// pasteCommand waits before pressing the keys, long enough for the
+				// window manager to focus the original application again
+				_ = pasteCommand()
+			}
+		},
+		Copy: func(text string) {
+			writeClipboard(text)
+		},
+		Retry: func() {
+			go retryReview(guiApp, original, regenerate)
+		},
+		Discard: func() {
+			slog.Debug("Discarded AI revision")
+		},
+	})
+}
+
+func retryReview(guiApp fyne.App, original string, regenerate regenerateFunc) {
+	loadingScreen := loading.LoadingScreenWithMessageAddModel(guiApp, loading.ThinkingMsg,
+		"Trying Again...")
+	loadingScreen.Show()
+	generated, err := regenerate()
+	loadingScreen.Hide()
+	if err != nil {
+		slog.Error("Failed to re-generate", "error", err)
+		return
+	}
+	showReview(guiApp, original, &generated, regenerate)
+}
+
+// writeClipboard puts text in the clipboard and remembers it so the next key
+// press doesn't send our own response back to the AI.
+func writeClipboard(text string) bool {
+	LastClipboardContent = sha256.Sum256([]byte(text))
+	err := clipboard.WriteAll(text)
+	if err != nil {
+		slog.Error("Failed to write to clipboard", "error", err)
+		return false
+	}
+	return true
+}
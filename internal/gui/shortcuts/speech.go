@@ -0,0 +1,58 @@
+package shortcuts
+
+import (
+	"context"
+	"log/slog"
+
+	"fyne.io/fyne/v2"
+
+	"github.com/bahelit/ctrl_plus_revise/internal/config"
+	"github.com/bahelit/ctrl_plus_revise/pkg/speech"
+)
+
+// SetupSpeech finds the text-to-speech engine chosen in the settings and
+// starts the speech queue, calling it again applies changed settings.
+func SetupSpeech(guiApp fyne.App) {
+	engineName := guiApp.Preferences().String(config.SpeechEngineKey)
+	engine, err := speech.Find(engineName)
+	if err != nil {
+		slog.Warn("Text-to-speech is unavailable, install espeak-ng or piper", "error", err)
+	}
+	if Speech == nil {
+		Speech = speech.NewQueue(engine, SpeechOptions(guiApp))
+		Speech.Start(context.Background())
+	} else {
+		Speech.SetEngine(engine)
+		Speech.SetOptions(SpeechOptions(guiApp))
+	}
+	if engine != nil {
+		slog.Info("Text-to-speech ready", "engine", engine.Name())
+	}
+}
+
+// SpeechOptions returns the voice settings stored in the preferences.
+func SpeechOptions(guiApp fyne.App) speech.Options {
+	return speech.Options{
+		Voice:    guiApp.Preferences().String(config.SpeechVoiceKey),
+		Rate:     guiApp.Preferences().Int(config.SpeechRateKey),
+		Language: guiApp.Preferences().StringWithFallback(config.SpeechLanguageKey, "en"),
+	}
+}
+
+// speakResponse reads an AI response aloud when the user asked for it.
+func speakResponse(guiApp fyne.App, response string) {
+	speakAIResponse := guiApp.Preferences().BoolWithFallback(config.SpeakAIResponseKey, false)
+	readResponse := guiApp.Preferences().BoolWithFallback(config.ReadAIResponseKey, false)
+	if !speakAIResponse || !readResponse || Speech == nil {
+		return
+	}
+	Speech.Say(response)
+}
+
+func handleStopSpeakingPressed() {
+	if Speech == nil {
+		return
+	}
+	slog.Debug("Interrupting speech")
+	Speech.Interrupt()
+}
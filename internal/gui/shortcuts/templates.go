@@ -0,0 +1,84 @@
+package shortcuts
+
+import (
+	"log/slog"
+
+	"fyne.io/fyne/v2"
+	ollamaApi "github.com/ollama/ollama/api"
+
+	"github.com/bahelit/ctrl_plus_revise/internal/config"
+	"github.com/bahelit/ctrl_plus_revise/internal/gui/delivery"
+	"github.com/bahelit/ctrl_plus_revise/internal/gui/loading"
+	"github.com/bahelit/ctrl_plus_revise/internal/ollama"
+	"github.com/bahelit/ctrl_plus_revise/pkg/appprofile"
+	"github.com/bahelit/ctrl_plus_revise/pkg/clipboard"
+	"github.com/bahelit/ctrl_plus_revise/pkg/prompttemplate"
+)
+
+// selectedTemplate returns the prompt template picked as the AI action, if any.
+func selectedTemplate(guiApp fyne.App) (ollama.PromptTemplate, bool) {
+	name := guiApp.Preferences().String(config.CurrentTemplateKey)
+	if name == "" {
+		return ollama.PromptTemplate{}, false
+	}
+	return ollama.FindPromptTemplate(guiApp, name)
+}
+
+// templateData collects what a template can use, it has to be called before
+// the highlighted text is copied to read the clipboard.
+func templateData(guiApp fyne.App, window appprofile.Window) prompttemplate.Data {
+	previous, err := clipboard.ReadAll()
+	if err != nil {
+		slog.Debug("Failed to read clipboard", "error", err)
+	}
+	toLang := guiApp.Preferences().StringWithFallback(config.CurrentToLangKey, string(ollama.MyLanguage))
+	// An empty source skips detecting the language of the text
+	_, target := ollama.ResolveTranslation(guiApp, nil, "", "", ollama.Language(toLang))
+	return prompttemplate.Data{
+		Language:  string(target),
+		AppName:   appName(window),
+		Clipboard: previous,
+		Date:      prompttemplate.Today(),
+		Vars:      ollama.PromptVariables(guiApp),
+	}
+}
+
+// handleTemplateKeyPressed sends the highlighted text with the user's prompt
+// template instead of a built-in prompt.
+func handleTemplateKeyPressed(guiApp fyne.App, ollamaClient *ollamaApi.Client, tmpl ollama.PromptTemplate, window appprofile.Window) {
+	data := templateData(guiApp, window)
+	clip, copiedText := copyTextToClipboard()
+	if !copiedText {
+		return
+	}
+	data.Selection = clip
+
+	loadingScreen := loading.LoadingScreenWithMessageAddModel(guiApp, loading.ThinkingMsg,
+		"Template: "+tmpl.Name+"...")
+	loadingScreen.Show()
+
+	generated, err := ollama.AskAIWithTemplate(guiApp, ollamaClient, tmpl, data)
+	if err != nil {
+		slog.Error("Failed to ask AI with template", "template", tmpl.Name, "error", err)
+		loadingScreen.Hide()
+		guiApp.SendNotification(&fyne.Notification{
+			Title:   "Ctrl+Revise: The template failed",
+			Content: err.Error(),
+		})
+		return
+	}
+	loadingScreen.Hide()
+
+	handleGeneratedResponse(guiApp, ollamaClient, delivery.Revise, clip, &generated, func() (ollamaApi.GenerateResponse, error) {
+		return ollama.AskAIWithTemplate(guiApp, ollamaClient, tmpl, data)
+	})
+}
+
+// appName returns the process name of the window, or its title when the
+// process is unknown.
+func appName(window appprofile.Window) string {
+	if window.Process != "" {
+		return window.Process
+	}
+	return window.Title
+}
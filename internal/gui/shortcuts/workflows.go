@@ -0,0 +1,70 @@
+package shortcuts
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/data/binding"
+	"fyne.io/fyne/v2/widget"
+	ollamaApi "github.com/ollama/ollama/api"
+
+	"github.com/bahelit/ctrl_plus_revise/internal/config"
+	"github.com/bahelit/ctrl_plus_revise/internal/gui/clippy"
+	"github.com/bahelit/ctrl_plus_revise/internal/gui/delivery"
+	"github.com/bahelit/ctrl_plus_revise/internal/gui/loading"
+	"github.com/bahelit/ctrl_plus_revise/internal/gui/workflows"
+	"github.com/bahelit/ctrl_plus_revise/pkg/workflow"
+)
+
+// selectedWorkflow returns the workflow picked as the AI action, if any.
+func selectedWorkflow(guiApp fyne.App) (workflow.Workflow, bool) {
+	name := guiApp.Preferences().String(config.CurrentWorkflowKey)
+	if name == "" {
+		return workflow.Workflow{}, false
+	}
+	return workflows.Find(guiApp, name)
+}
+
+// handleWorkflowKeyPressed runs the highlighted text through every step of
+// the workflow.
+func handleWorkflowKeyPressed(guiApp fyne.App, ollamaClient *ollamaApi.Client, wf workflow.Workflow) {
+	clip, copiedText := copyTextToClipboard()
+	if !copiedText {
+		return
+	}
+
+	bar := widget.NewProgressBar()
+	status := binding.NewString()
+	_ = status.Set(fmt.Sprintf("Step 1 of %d: %s", len(wf.Steps), wf.Steps[0].Action))
+	loadingScreen := loading.LoadingScreenWithProgressAndMessage(guiApp, bar, status, loading.ThinkingMsg,
+		"Workflow: "+wf.Name+"...")
+	loadingScreen.Show()
+
+	results, err := wf.Run(context.Background(), clip, workflows.Executor(guiApp, ollamaClient), func(r workflow.Result) {
+		bar.SetValue(float64(r.Step+1) / float64(len(wf.Steps)))
+		if next := r.Step + 1; next < len(wf.Steps) {
+			_ = status.Set(fmt.Sprintf("Step %d of %d: %s", next+1, len(wf.Steps), wf.Steps[next].Action))
+		}
+	})
+	loadingScreen.Hide()
+	if err != nil {
+		slog.Error("Failed to run workflow", "workflow", wf.Name, "error", err)
+		guiApp.SendNotification(&fyne.Notification{
+			Title:   "Ctrl+Revise: The workflow failed",
+			Content: err.Error(),
+		})
+		return
+	}
+
+	var steps []clippy.Step
+	for _, r := range results {
+		steps = append(steps, clippy.Step{Label: r.Label(), Response: &ollamaApi.GenerateResponse{Response: r.Output}})
+	}
+	generated := ollamaApi.GenerateResponse{Response: workflow.Output(clip, results)}
+	deliverResponse(guiApp, ollamaClient, delivery.Revise, clip, &generated, steps, func() (ollamaApi.GenerateResponse, error) {
+		results, err := wf.Run(context.Background(), clip, workflows.Executor(guiApp, ollamaClient), nil)
+		return ollamaApi.GenerateResponse{Response: workflow.Output(clip, results)}, err
+	})
+}
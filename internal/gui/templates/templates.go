@@ -0,0 +1,164 @@
+package templates
+
+import (
+	"log/slog"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/bahelit/ctrl_plus_revise/internal/config"
+	"github.com/bahelit/ctrl_plus_revise/internal/ollama"
+	"github.com/bahelit/ctrl_plus_revise/pkg/prompttemplate"
+)
+
+const sampleText = "their going to the park tomorrow, do you want to come with?"
+
+// ShowTemplates opens the prompt template editor, onChange is called after a
+// template is saved or deleted.
+func ShowTemplates(guiApp fyne.App, onChange func()) {
+	slog.Debug("Showing prompt templates")
+	w := guiApp.NewWindow("Ctrl+Revise Prompt Templates")
+	w.Resize(fyne.NewSize(900, 560))
+
+	templates := ollama.PromptTemplates(guiApp)
+
+	name := widget.NewEntry()
+	name.SetPlaceHolder("Template name")
+	text := widget.NewMultiLineEntry()
+	text.Wrapping = fyne.TextWrapWord
+	text.SetPlaceHolder("Rewrite this message to {{.Vars.team}} in {{.Language}}: {{.Selection}}")
+	text.SetMinRowsVisible(6)
+	vars := widget.NewMultiLineEntry()
+	vars.SetPlaceHolder("team=the support team")
+	vars.SetText(guiApp.Preferences().String(config.PromptVariablesKey))
+	vars.SetMinRowsVisible(3)
+	sample := widget.NewMultiLineEntry()
+	sample.Wrapping = fyne.TextWrapWord
+	sample.SetText(sampleText)
+	sample.SetMinRowsVisible(2)
+	preview := widget.NewLabel("")
+	preview.Wrapping = fyne.TextWrapWord
+
+	updatePreview := func(string) {
+		preview.SetText(renderPreview(text.Text, vars.Text, sample.Text))
+	}
+	text.OnChanged = updatePreview
+	vars.OnChanged = updatePreview
+	sample.OnChanged = updatePreview
+
+	var selected = -1
+	list := widget.NewList(
+		func() int { return len(templates) },
+		func() fyne.CanvasObject { return widget.NewLabel("Template Name") },
+		func(id widget.ListItemID, o fyne.CanvasObject) {
+			o.(*widget.Label).SetText(templates[id].Name)
+		})
+	list.OnSelected = func(id widget.ListItemID) {
+		selected = id
+		name.SetText(templates[id].Name)
+		text.SetText(templates[id].Text)
+	}
+
+	reload := func() {
+		templates = ollama.PromptTemplates(guiApp)
+		list.UnselectAll()
+		list.Refresh()
+		selected = -1
+		if onChange != nil {
+			onChange()
+		}
+	}
+
+	saveButton := widget.NewButtonWithIcon("Save", theme.DocumentSaveIcon(), func() {
+		if err := ollama.SavePromptVariables(guiApp, vars.Text); err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		if strings.TrimSpace(name.Text) == "" && strings.TrimSpace(text.Text) == "" {
+			// Only the variables changed
+			return
+		}
+		err := ollama.SavePromptTemplate(guiApp, ollama.PromptTemplate{Name: name.Text, Text: text.Text})
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		reload()
+	})
+	newButton := widget.NewButtonWithIcon("New", theme.ContentAddIcon(), func() {
+		list.UnselectAll()
+		selected = -1
+		name.SetText("")
+		text.SetText("")
+	})
+	deleteButton := widget.NewButtonWithIcon("Delete", theme.DeleteIcon(), func() {
+		if selected < 0 {
+			return
+		}
+		if err := ollama.DeletePromptTemplate(guiApp, templates[selected].Name); err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		name.SetText("")
+		text.SetText("")
+		reload()
+	})
+
+	editor := container.NewVBox(
+		widget.NewLabel("Name"),
+		name,
+		widget.NewLabel("Template"),
+		text,
+		widget.NewAccordion(widget.NewAccordionItem("Variables", variableHelp())),
+		widget.NewLabel("Your variables, one name=value per line, used as {{.Vars.name}}"),
+		vars,
+		widget.NewLabel("Sample text"),
+		sample,
+		widget.NewLabelWithStyle("Preview", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		preview,
+	)
+	buttons := container.NewHBox(newButton, deleteButton, layout.NewSpacer(), saveButton)
+	split := container.NewHSplit(list, container.NewBorder(nil, buttons, nil, nil, container.NewVScroll(editor)))
+	split.Offset = 0.25
+	w.SetContent(split)
+	updatePreview("")
+	w.Show()
+}
+
+// variableHelp lists the variables a template can use.
+func variableHelp() fyne.CanvasObject {
+	form := widget.NewForm()
+	for _, v := range prompttemplate.Variables() {
+		form.Append(v.Name, widget.NewLabel(v.Description))
+	}
+	return form
+}
+
+// renderPreview returns the prompt the template produces for the sample text,
+// or why it can't be saved.
+func renderPreview(text, rawVars, sample string) string {
+	if strings.TrimSpace(text) == "" {
+		return ""
+	}
+	vars, err := prompttemplate.ParseVars(rawVars)
+	if err != nil {
+		return "Variables: " + err.Error()
+	}
+	if err = prompttemplate.Validate(text, vars); err != nil {
+		return "Error: " + err.Error()
+	}
+	tmpl, err := prompttemplate.Parse("preview", text)
+	if err != nil {
+		return "Error: " + err.Error()
+	}
+	rendered, err := tmpl.Render(prompttemplate.Sample(sample, vars))
+	if err != nil {
+		return "Error: " + err.Error()
+	}
+	return rendered
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/bahelit/ctrl_plus_revise/internal/gui/loading"
 	"github.com/bahelit/ctrl_plus_revise/internal/gui/settings"
 	"log/slog"
+	"strings"
 	"time"
 
 	"fyne.io/fyne/v2"
@@ -34,6 +35,8 @@ func TranslateText(guiApp fyne.App, ollamaClient *ollamaApi.Client) {
 		to           = widget.NewMultiLineEntry()
 		fromDropdown = settings.SelectTranslationFromDropDown(guiApp)
 		toDropdown   = settings.SelectTranslationToDropDown(guiApp)
+		detected     = widget.NewLabel("")
+		warning      = widget.NewLabel("")
 	)
 
 	to.Wrapping = fyne.TextWrapWord
@@ -52,7 +55,7 @@ func TranslateText(guiApp fyne.App, ollamaClient *ollamaApi.Client) {
 				slog.Warn("text validating failed for translation", "error", err)
 				return
 			}
-			handleTranslateRequest(guiApp, ollamaClient, from, to)
+			handleTranslateRequest(guiApp, ollamaClient, from, to, detected, warning)
 			translator.Canvas().Focus(from)
 		})
 	}
@@ -67,12 +70,14 @@ func TranslateText(guiApp fyne.App, ollamaClient *ollamaApi.Client) {
 	}
 
 	top := container.NewBorder(nil, nil,
-		container.NewHBox(fromDropdown),
+		container.NewHBox(fromDropdown, detected),
 		container.NewHBox(toDropdown),
 		container.NewHBox(layout.NewSpacer(), layout.NewSpacer()),
 	)
 
-	combo := container.NewBorder(top, nil, nil, nil,
+	warning.Importance = widget.WarningImportance
+	warning.Wrapping = fyne.TextWrapWord
+	combo := container.NewBorder(top, warning, nil, nil,
 		container.NewGridWithColumns(2, from, to),
 	)
 
@@ -82,7 +87,7 @@ func TranslateText(guiApp fyne.App, ollamaClient *ollamaApi.Client) {
 
 }
 
-func handleTranslateRequest(guiApp fyne.App, ollamaClient *ollamaApi.Client, from, to *widget.Entry) {
+func handleTranslateRequest(guiApp fyne.App, ollamaClient *ollamaApi.Client, from, to *widget.Entry, detected, warning *widget.Label) {
 	err := shortcuts.Throttle.Do()
 	if err != nil {
 		slog.Error("Failed to create throttle", "error", err)
@@ -92,14 +97,24 @@ func handleTranslateRequest(guiApp fyne.App, ollamaClient *ollamaApi.Client, fro
 		shortcuts.Throttle.Done(err)
 	}()
 
-	fromLang := guiApp.Preferences().StringWithFallback(config.CurrentFromLangKey, string(ollama.English))
-	toLang := guiApp.Preferences().StringWithFallback(config.CurrentToLangKey, string(ollama.Spanish))
+	fromLang := guiApp.Preferences().StringWithFallback(config.CurrentFromLangKey, string(ollama.AutoDetect))
+	toLang := guiApp.Preferences().StringWithFallback(config.CurrentToLangKey, string(ollama.MyLanguage))
 
 	loadingScreen := loading.LoadingScreenWithMessageAddModel(guiApp, loading.ThinkingMsg, "Translating text")
 	loadingScreen.Show()
 
-	slog.Debug("Translating text", "fromLang", fromLang, "toLang", toLang)
-	generated, err := ollama.AskAIToTranslate(guiApp, ollamaClient, from.Text, ollama.Language(fromLang), ollama.Language(toLang))
+	source, target := ollama.ResolveTranslation(guiApp, ollamaClient, from.Text, ollama.Language(fromLang), ollama.Language(toLang))
+	switch {
+	case ollama.Language(fromLang) != ollama.AutoDetect:
+		detected.SetText("")
+	case source == "":
+		detected.SetText("Language not recognised")
+	default:
+		detected.SetText("Detected: " + string(source))
+	}
+
+	slog.Debug("Translating text", "fromLang", source, "toLang", target)
+	generated, err := ollama.AskAIToTranslate(guiApp, ollamaClient, from.Text, source, target)
 	if err != nil {
 		slog.Error("Failed to ask AI", "error", err)
 		loadingScreen.Hide()
@@ -107,6 +122,7 @@ func handleTranslateRequest(guiApp fyne.App, ollamaClient *ollamaApi.Client, fro
 	}
 	loadingScreen.Hide()
 	to.SetText(generated.Response)
+	warning.SetText(glossaryWarning(from.Text, generated.Response, target))
 	err = clipboard.WriteAll(generated.Response)
 	if err != nil {
 		slog.Error("Failed to write to clipboard", "error", err)
@@ -115,3 +131,15 @@ func handleTranslateRequest(guiApp fyne.App, ollamaClient *ollamaApi.Client, fro
 
 	return
 }
+
+// glossaryWarning lists the glossary terms the AI changed in the translation.
+func glossaryWarning(input, output string, toLang ollama.Language) string {
+	var problems []string
+	for _, v := range ollama.VerifyGlossary(input, output, toLang) {
+		problems = append(problems, v.String())
+	}
+	if len(problems) == 0 {
+		return ""
+	}
+	return "Check the glossary terms: " + strings.Join(problems, ", ")
+}
@@ -0,0 +1,188 @@
+package workflows
+
+import (
+	"log/slog"
+	"strconv"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/bahelit/ctrl_plus_revise/internal/ollama"
+	"github.com/bahelit/ctrl_plus_revise/pkg/workflow"
+)
+
+const (
+	// defaultModel keeps the model picked in the settings for a step.
+	defaultModel = "Model from the settings"
+	// skipStep skips a step whose condition doesn't hold.
+	skipStep = "Skip the step"
+)
+
+// ShowWorkflows opens the workflow editor, onChange is called after a
+// workflow is saved or deleted.
+func ShowWorkflows(guiApp fyne.App, onChange func()) {
+	slog.Debug("Showing workflows")
+	w := guiApp.NewWindow("Ctrl+Revise Workflows")
+	w.Resize(fyne.NewSize(980, 520))
+
+	workflows := Get(guiApp)
+	editor := newWorkflowEditor(guiApp)
+
+	var selected = -1
+	list := widget.NewList(
+		func() int { return len(workflows) },
+		func() fyne.CanvasObject { return widget.NewLabel("Workflow Name") },
+		func(id widget.ListItemID, o fyne.CanvasObject) {
+			o.(*widget.Label).SetText(workflows[id].Name)
+		})
+	list.OnSelected = func(id widget.ListItemID) {
+		selected = id
+		editor.edit(workflows[id])
+	}
+
+	reload := func() {
+		workflows = Get(guiApp)
+		list.UnselectAll()
+		list.Refresh()
+		selected = -1
+		if onChange != nil {
+			onChange()
+		}
+	}
+
+	saveButton := widget.NewButtonWithIcon("Save", theme.DocumentSaveIcon(), func() {
+		if err := Save(guiApp, editor.workflow()); err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		reload()
+	})
+	newButton := widget.NewButtonWithIcon("New", theme.ContentAddIcon(), func() {
+		list.UnselectAll()
+		selected = -1
+		editor.edit(workflow.Workflow{Steps: []workflow.Step{{Action: ollama.CorrectGrammar.String()}}})
+	})
+	deleteButton := widget.NewButtonWithIcon("Delete", theme.DeleteIcon(), func() {
+		if selected < 0 {
+			return
+		}
+		if err := Delete(guiApp, workflows[selected].Name); err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		editor.edit(workflow.Workflow{})
+		reload()
+	})
+
+	note := widget.NewLabel("Each step works on the text of the step before it. A condition such as " +
+		"\"words > 50\" (chars, words or lines) runs the step only when it holds, otherwise the other action is used.")
+	note.Wrapping = fyne.TextWrapWord
+	buttons := container.NewHBox(newButton, deleteButton, layout.NewSpacer(), saveButton)
+	right := container.NewBorder(editor.header, container.NewVBox(note, buttons), nil, nil, container.NewVScroll(editor.steps))
+	split := container.NewHSplit(list, right)
+	split.Offset = 0.25
+	w.SetContent(split)
+	editor.edit(workflow.Workflow{Steps: []workflow.Step{{Action: ollama.CorrectGrammar.String()}}})
+	w.Show()
+}
+
+// workflowEditor is the form for a single workflow.
+type workflowEditor struct {
+	guiApp  fyne.App
+	name    *widget.Entry
+	header  fyne.CanvasObject
+	steps   *fyne.Container
+	current []workflow.Step
+}
+
+func newWorkflowEditor(guiApp fyne.App) *workflowEditor {
+	e := &workflowEditor{
+		guiApp: guiApp,
+		name:   widget.NewEntry(),
+		steps:  container.NewVBox(),
+	}
+	e.name.SetPlaceHolder("Publish")
+	addStep := widget.NewButtonWithIcon("Add Step", theme.ContentAddIcon(), func() {
+		e.current = append(e.current, workflow.Step{Action: ollama.CorrectGrammar.String()})
+		e.showSteps()
+	})
+	e.header = container.NewBorder(nil, nil, widget.NewLabel("Name:"), addStep, e.name)
+	return e
+}
+
+func (e *workflowEditor) edit(w workflow.Workflow) {
+	e.name.SetText(w.Name)
+	e.current = append([]workflow.Step{}, w.Steps...)
+	e.showSteps()
+}
+
+func (e *workflowEditor) workflow() workflow.Workflow {
+	return workflow.Workflow{Name: e.name.Text, Steps: append([]workflow.Step{}, e.current...)}
+}
+
+// showSteps draws a row for every step, the rows write their changes to e.current.
+func (e *workflowEditor) showSteps() {
+	actions := Actions(e.guiApp)
+	e.steps.RemoveAll()
+	for i := range e.current {
+		step := &e.current[i]
+
+		action := widget.NewSelect(actions, func(s string) { step.Action = s })
+		action.SetSelected(step.Action)
+		model := widget.NewSelect(append([]string{defaultModel}, ollama.ModelNames()...), func(s string) {
+			step.Model = ""
+			if s != defaultModel {
+				step.Model = s
+			}
+		})
+		model.SetSelected(defaultModel)
+		if step.Model != "" {
+			model.SetSelected(step.Model)
+		}
+		when := widget.NewEntry()
+		when.SetPlaceHolder("Always")
+		when.SetText(string(step.When))
+		when.OnChanged = func(s string) { step.When = workflow.Condition(s) }
+		otherwise := widget.NewSelect(append([]string{skipStep}, actions...), func(s string) {
+			step.Else = ""
+			if s != skipStep {
+				step.Else = s
+			}
+		})
+		otherwise.SetSelected(skipStep)
+		if step.Else != "" {
+			otherwise.SetSelected(step.Else)
+		}
+
+		index := i
+		remove := widget.NewButtonWithIcon("", theme.DeleteIcon(), func() {
+			e.current = append(e.current[:index], e.current[index+1:]...)
+			e.showSteps()
+		})
+		up := widget.NewButtonWithIcon("", theme.MoveUpIcon(), func() {
+			if index > 0 {
+				e.current[index-1], e.current[index] = e.current[index], e.current[index-1]
+				e.showSteps()
+			}
+		})
+
+		heading := widget.NewLabelWithStyle(stepLabel(index), fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+		form := container.New(layout.NewFormLayout(),
+			widget.NewLabel("Action:"), action,
+			widget.NewLabel("Model:"), model,
+			widget.NewLabel("Only when:"), when,
+			widget.NewLabel("Otherwise:"), otherwise,
+		)
+		e.steps.Add(container.NewBorder(nil, nil, heading, container.NewHBox(up, remove), form))
+		e.steps.Add(widget.NewSeparator())
+	}
+	e.steps.Refresh()
+}
+
+func stepLabel(i int) string {
+	return "Step " + strconv.Itoa(i+1) + ":"
+}
@@ -0,0 +1,144 @@
+package workflows
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	ollamaApi "github.com/ollama/ollama/api"
+
+	"github.com/bahelit/ctrl_plus_revise/internal/config"
+	"github.com/bahelit/ctrl_plus_revise/internal/gui/profiles"
+	"github.com/bahelit/ctrl_plus_revise/internal/ollama"
+	"github.com/bahelit/ctrl_plus_revise/pkg/appprofile"
+	"github.com/bahelit/ctrl_plus_revise/pkg/prompttemplate"
+	"github.com/bahelit/ctrl_plus_revise/pkg/workflow"
+)
+
+// translatePrefix starts the actions translating the text, e.g. "Translate to Spanish".
+const translatePrefix = "Translate to "
+
+// Get returns the user's workflows sorted by name.
+func Get(guiApp fyne.App) []workflow.Workflow {
+	var workflows []workflow.Workflow
+	saved := guiApp.Preferences().String(config.WorkflowsKey)
+	if saved == "" {
+		return nil
+	}
+	if err := json.Unmarshal([]byte(saved), &workflows); err != nil {
+		slog.Error("Failed to read workflows", "error", err)
+		return nil
+	}
+	slices.SortFunc(workflows, func(a, b workflow.Workflow) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return workflows
+}
+
+// Find returns the workflow with the name.
+func Find(guiApp fyne.App, name string) (workflow.Workflow, bool) {
+	for _, w := range Get(guiApp) {
+		if w.Name == name {
+			return w, true
+		}
+	}
+	return workflow.Workflow{}, false
+}
+
+// Save validates the workflow and adds it, or replaces the one with the same name.
+func Save(guiApp fyne.App, w workflow.Workflow) error {
+	w.Name = strings.TrimSpace(w.Name)
+	if err := w.Validate(); err != nil {
+		return err
+	}
+	actions := Actions(guiApp)
+	for i, step := range w.Steps {
+		if !slices.Contains(actions, step.Action) {
+			return fmt.Errorf("step %d: unknown action %q", i+1, step.Action)
+		}
+		if step.Else != "" && !slices.Contains(actions, step.Else) {
+			return fmt.Errorf("step %d: unknown action %q", i+1, step.Else)
+		}
+	}
+	workflows := slices.DeleteFunc(Get(guiApp), func(saved workflow.Workflow) bool {
+		return saved.Name == w.Name
+	})
+	return save(guiApp, append(workflows, w))
+}
+
+// Delete removes the workflow with the name.
+func Delete(guiApp fyne.App, name string) error {
+	workflows := slices.DeleteFunc(Get(guiApp), func(saved workflow.Workflow) bool {
+		return saved.Name == name
+	})
+	if guiApp.Preferences().String(config.CurrentWorkflowKey) == name {
+		guiApp.Preferences().SetString(config.CurrentWorkflowKey, "")
+	}
+	return save(guiApp, workflows)
+}
+
+func save(guiApp fyne.App, workflows []workflow.Workflow) error {
+	data, err := json.Marshal(workflows)
+	if err != nil {
+		return err
+	}
+	guiApp.Preferences().SetString(config.WorkflowsKey, string(data))
+	return nil
+}
+
+// Actions returns what a step can do: the revision prompts, translating to a
+// language and the user's prompt templates.
+func Actions(guiApp fyne.App) []string {
+	var actions []string
+	for _, prompt := range ollama.RevisionPrompts() {
+		actions = append(actions, prompt.String())
+	}
+	actions = append(actions, translatePrefix+string(ollama.MyLanguage))
+	for _, l := range ollama.TranslationLanguages(guiApp) {
+		actions = append(actions, translatePrefix+string(l))
+	}
+	for _, t := range ollama.PromptTemplates(guiApp) {
+		actions = append(actions, profiles.TemplatePrefix+t.Name)
+	}
+	return actions
+}
+
+// Executor runs the steps of a workflow with Ollama.
+func Executor(guiApp fyne.App, client *ollamaApi.Client) workflow.Executor {
+	return func(ctx context.Context, action, model, text string) (string, error) {
+		stepApp := guiApp
+		if model != "" {
+			stepApp = profiles.Apply(guiApp, appprofile.Profile{Name: action, Model: model})
+		}
+		response, err := runAction(stepApp, client, action, text)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(response.Response), nil
+	}
+}
+
+func runAction(guiApp fyne.App, client *ollamaApi.Client, action, text string) (ollamaApi.GenerateResponse, error) {
+	if prompt, ok := ollama.PromptFromName(action); ok {
+		return ollama.AskAIWithChunking(guiApp, client, prompt, text, nil)
+	}
+	if toLang, ok := strings.CutPrefix(action, translatePrefix); ok {
+		source, target := ollama.ResolveTranslation(guiApp, client, text, ollama.AutoDetect, ollama.Language(toLang))
+		return ollama.AskAIToTranslate(guiApp, client, text, source, target)
+	}
+	if name, ok := strings.CutPrefix(action, profiles.TemplatePrefix); ok {
+		tmpl, found := ollama.FindPromptTemplate(guiApp, name)
+		if !found {
+			return ollamaApi.GenerateResponse{}, fmt.Errorf("the template %q doesn't exist", name)
+		}
+		toLang := guiApp.Preferences().StringWithFallback(config.CurrentToLangKey, string(ollama.MyLanguage))
+		_, target := ollama.ResolveTranslation(guiApp, nil, "", "", ollama.Language(toLang))
+		return ollama.AskAIWithTemplate(guiApp, client, tmpl, prompttemplate.Data{Selection: text, Language: string(target)})
+	}
+	return ollamaApi.GenerateResponse{}, errors.New("unknown action " + action)
+}
@@ -0,0 +1,279 @@
+// Package writing is an editor that checks the text paragraph by paragraph
+// while it is written and lists grammar and style suggestions to apply one
+// at a time.
+package writing
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	ollamaApi "github.com/ollama/ollama/api"
+
+	"github.com/bahelit/ctrl_plus_revise/internal/gui/metrics"
+	"github.com/bahelit/ctrl_plus_revise/internal/ollama"
+	"github.com/bahelit/ctrl_plus_revise/pkg/suggest"
+	"github.com/bahelit/ctrl_plus_revise/pkg/throttle"
+)
+
+const (
+	// analyseDelay is how long the writer has to pause before the text is checked.
+	analyseDelay = time.Second
+	// minParagraphLength skips headings and stray words.
+	minParagraphLength = 8
+)
+
+type assistant struct {
+	guiApp       fyne.App
+	ollamaClient *ollamaApi.Client
+	w            fyne.Window
+	editor       *widget.Entry
+	suggestions  *fyne.Container
+	status       *widget.Label
+	readability  *widget.Label
+	// baseline is the text before the first suggestion was applied, the
+	// readability of the text is compared with it.
+	baseline string
+	// throttle sends one paragraph at a time, the hotkeys have their own so
+	// checking a long text doesn't hold them up.
+	throttle *throttle.Throttle
+
+	lock sync.Mutex
+	// checked holds the suggestions of every paragraph the model has seen,
+	// keyed by the text of the paragraph.
+	checked map[string][]suggest.Suggestion
+	running bool
+	// pending is set when the text changed while it was being checked.
+	pending bool
+}
+
+// WritingAssistant opens an editor that suggests grammar and style changes.
+func WritingAssistant(guiApp fyne.App, ollamaClient *ollamaApi.Client) {
+	slog.Debug("Showing Writing Assistant")
+	w := guiApp.NewWindow("Ctrl+Revise Writing Assistant")
+	a := &assistant{
+		guiApp:       guiApp,
+		ollamaClient: ollamaClient,
+		w:            w,
+		editor:       widget.NewMultiLineEntry(),
+		suggestions:  container.NewVBox(),
+		status:       widget.NewLabel("Start writing, suggestions appear when you pause"),
+		readability:  widget.NewLabel(""),
+		throttle:     throttle.NewThrottle(1),
+		checked:      make(map[string][]suggest.Suggestion),
+	}
+	a.status.Wrapping = fyne.TextWrapWord
+	a.readability.TextStyle = fyne.TextStyle{Italic: true}
+
+	var keyPressDelay *time.Timer
+	a.editor.SetPlaceHolder("Write or paste your text, paragraphs are separated by blank lines")
+	a.editor.Wrapping = fyne.TextWrapWord
+	a.editor.OnChanged = func(string) {
+		if keyPressDelay != nil {
+			keyPressDelay.Stop()
+		}
+		keyPressDelay = time.AfterFunc(analyseDelay, a.analyse)
+		a.refresh()
+	}
+
+	checkAgain := widget.NewButtonWithIcon("Check Again", theme.ViewRefreshIcon(), func() {
+		a.lock.Lock()
+		clear(a.checked)
+		a.lock.Unlock()
+		a.baseline = ""
+		a.refresh()
+		go a.analyse()
+	})
+	showReadability := widget.NewButtonWithIcon("Readability", theme.InfoIcon(), a.showReadability)
+	copyText := widget.NewButtonWithIcon("Copy", theme.ContentCopyIcon(), func() {
+		w.Clipboard().SetContent(a.editor.Text)
+	})
+
+	side := container.NewBorder(a.status, nil, nil, nil, container.NewVScroll(a.suggestions))
+	split := container.NewHSplit(a.editor, side)
+	split.SetOffset(0.6)
+	buttons := container.NewHBox(a.readability, layout.NewSpacer(), showReadability, checkAgain, copyText)
+
+	w.SetContent(container.NewBorder(nil, buttons, nil, nil, split))
+	w.Resize(fyne.NewSize(900, 600))
+	w.Canvas().Focus(a.editor)
+	w.Show()
+}
+
+// analyse asks for suggestions for the paragraphs that haven't been checked,
+// only one analysis runs at a time and picks up the changes made meanwhile.
+func (a *assistant) analyse() {
+	a.lock.Lock()
+	if a.running {
+		a.pending = true
+		a.lock.Unlock()
+		return
+	}
+	a.running = true
+	a.lock.Unlock()
+
+	var err error
+	for {
+		err = a.checkParagraphs()
+
+		a.lock.Lock()
+		if !a.pending || err != nil {
+			a.running, a.pending = false, false
+			a.lock.Unlock()
+			break
+		}
+		a.pending = false
+		a.lock.Unlock()
+	}
+
+	if err != nil {
+		slog.Error("Failed to check the text", "error", err)
+		a.status.SetText("Failed to check the text: " + err.Error())
+		return
+	}
+	a.refresh()
+}
+
+// checkParagraphs sends the paragraphs that haven't been checked to the
+// model one by one, a paragraph edited while it waits is skipped.
+func (a *assistant) checkParagraphs() error {
+	todo := a.unchecked()
+	for i, paragraph := range todo {
+		if !slices.Contains(suggest.Paragraphs(a.editor.Text), paragraph) {
+			continue
+		}
+		a.status.SetText(fmt.Sprintf("Checking paragraph %d of %d…", i+1, len(todo)))
+
+		if err := a.throttle.Do(); err != nil {
+			return err
+		}
+		response, err := ollama.AskAIForData(a.guiApp, a.ollamaClient, ollama.SuggestCorrections, paragraph)
+		a.throttle.Done(nil)
+		if err != nil {
+			return err
+		}
+
+		a.lock.Lock()
+		a.checked[paragraph] = suggest.Filter(paragraph, response.Suggestions)
+		a.lock.Unlock()
+		a.refresh()
+	}
+	return nil
+}
+
+// unchecked returns the paragraphs of the text the model hasn't seen.
+func (a *assistant) unchecked() []string {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	var todo []string
+	for _, paragraph := range suggest.Paragraphs(a.editor.Text) {
+		if _, ok := a.checked[paragraph]; !ok && len(paragraph) >= minParagraphLength {
+			todo = append(todo, paragraph)
+		}
+	}
+	return todo
+}
+
+// refresh lists the suggestions of the paragraphs in the text, in the order
+// of the paragraphs.
+func (a *assistant) refresh() {
+	paragraphs := suggest.Paragraphs(a.editor.Text)
+	a.suggestions.RemoveAll()
+
+	a.lock.Lock()
+	count, waiting := 0, 0
+	for _, paragraph := range paragraphs {
+		suggestions, ok := a.checked[paragraph]
+		if !ok && len(paragraph) >= minParagraphLength {
+			waiting++
+		}
+		for _, s := range suggestions {
+			a.suggestions.Add(a.suggestionCard(paragraph, s))
+			count++
+		}
+	}
+	running := a.running
+	a.lock.Unlock()
+	a.readability.SetText(metrics.Summary(a.editor.Text))
+
+	switch {
+	case running:
+		// checkParagraphs shows the progress
+	case waiting > 0:
+		a.status.SetText(fmt.Sprintf("%d paragraphs waiting to be checked", waiting))
+	case count == 0 && len(paragraphs) > 0:
+		a.status.SetText("No suggestions, the text looks good")
+	case count > 0:
+		a.status.SetText(fmt.Sprintf("%d suggestions", count))
+	}
+	a.suggestions.Refresh()
+}
+
+func (a *assistant) suggestionCard(paragraph string, s suggest.Suggestion) fyne.CanvasObject {
+	explanation := widget.NewLabel(s.Explanation)
+	explanation.Wrapping = fyne.TextWrapWord
+
+	apply := widget.NewButtonWithIcon("Apply", theme.ConfirmIcon(), func() {
+		a.apply(paragraph, s)
+	})
+	apply.Importance = widget.HighImportance
+	dismiss := widget.NewButtonWithIcon("Dismiss", theme.CancelIcon(), func() {
+		a.lock.Lock()
+		a.checked[paragraph] = without(a.checked[paragraph], s)
+		a.lock.Unlock()
+		a.refresh()
+	})
+
+	title := widget.NewLabelWithStyle(s.Label(), fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	title.Wrapping = fyne.TextWrapWord
+	return widget.NewCard("", "", container.NewVBox(title, explanation,
+		container.NewHBox(layout.NewSpacer(), dismiss, apply)))
+}
+
+// apply makes the change in the editor, the other suggestions for the
+// paragraph move to its new text so it isn't checked again.
+func (a *assistant) apply(paragraph string, s suggest.Suggestion) {
+	text, changed, ok := suggest.Apply(a.editor.Text, paragraph, s)
+	a.lock.Lock()
+	remaining := without(a.checked[paragraph], s)
+	if ok {
+		delete(a.checked, paragraph)
+		a.checked[changed] = suggest.Filter(changed, remaining)
+	} else {
+		a.checked[paragraph] = remaining
+	}
+	a.lock.Unlock()
+
+	if !ok {
+		slog.Warn("The suggestion no longer fits the text", "original", s.Original)
+		a.refresh()
+		a.status.SetText("The text changed, the suggestion no longer fits")
+		return
+	}
+	if a.baseline == "" {
+		a.baseline = a.editor.Text
+	}
+	a.editor.SetText(text)
+}
+
+// showReadability compares the text with how it was before the suggestions
+// were applied.
+func (a *assistant) showReadability() {
+	content := metrics.Single(a.editor.Text)
+	if a.baseline != "" {
+		content = metrics.Table(a.baseline, a.editor.Text)
+	}
+	dialog.ShowCustom("Readability", "Close", content, a.w)
+}
+
+func without(suggestions []suggest.Suggestion, s suggest.Suggestion) []suggest.Suggestion {
+	return slices.DeleteFunc(slices.Clone(suggestions), func(other suggest.Suggestion) bool { return other == s })
+}
@@ -0,0 +1,159 @@
+package ollama
+
+import (
+	"log/slog"
+	"strings"
+	"sync"
+	"unicode"
+
+	"fyne.io/fyne/v2"
+	"github.com/ollama/ollama/api"
+
+	"github.com/bahelit/ctrl_plus_revise/internal/config"
+	"github.com/bahelit/ctrl_plus_revise/pkg/docsplit"
+	"github.com/bahelit/ctrl_plus_revise/pkg/throttle"
+)
+
+const (
+	// DefaultNumCtx is the context window Ollama gives a model unless told otherwise.
+	DefaultNumCtx = 2048
+	// minChunkTokens stops tiny context windows from splitting text into single words.
+	minChunkTokens = 256
+	// parallelChunks is how many chunks are sent to Ollama at the same time.
+	parallelChunks = 2
+)
+
+// NumCtx returns the context window, in tokens, requested from Ollama.
+func NumCtx(guiApp fyne.App) int {
+	return guiApp.Preferences().IntWithFallback(config.NumCtxKey, DefaultNumCtx)
+}
+
+func requestOptions(guiApp fyne.App) map[string]interface{} {
+	return map[string]interface{}{"num_ctx": NumCtx(guiApp)}
+}
+
+// EstimateTokens guesses how many tokens the model needs for the text,
+// Chinese, Japanese and Korean characters are about a token each and other
+// languages about four characters or three quarters of a word per token.
+func EstimateTokens(text string) int {
+	var cjk, other int
+	for _, r := range text {
+		if unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) {
+			cjk++
+		} else {
+			other++
+		}
+	}
+	byChars := other / 4
+	byWords := len(strings.Fields(text)) * 4 / 3
+	return cjk + max(byChars, byWords)
+}
+
+// chunkBudget returns how many tokens of input fit in one request, half the
+// context window is left for the response.
+func chunkBudget(guiApp fyne.App, prompt PromptMsg) int {
+	overhead := EstimateTokens(prompt.PromptToText() + prompt.PromptExtraToText())
+	return max((NumCtx(guiApp)-overhead)/2, minChunkTokens)
+}
+
+// SplitForContext splits text that doesn't fit in the context window on
+// paragraph boundaries, short text is returned as the only chunk.
+func SplitForContext(guiApp fyne.App, prompt PromptMsg, text string) []string {
+	budget := chunkBudget(guiApp, prompt)
+	tokens := EstimateTokens(text)
+	if tokens <= budget {
+		return []string{text}
+	}
+	maxChars := len(text) * budget / tokens
+	doc, err := docsplit.Parse(docsplit.Text, []byte(text), maxChars)
+	if err != nil || len(doc.Units()) == 0 {
+		return []string{text}
+	}
+	return doc.Units()
+}
+
+// AskAIWithChunking runs the prompt over text of any length. Text too long for
+// the context window is split, the chunks are processed in parallel and the
+// responses merged, progress is called after every request.
+func AskAIWithChunking(guiApp fyne.App, client *api.Client, prompt PromptMsg, inputForPrompt string,
+	progress func(done, total int)) (api.GenerateResponse, error) {
+	if prompt.Schema() != nil {
+		// The data of the chunks can't be merged, the model gets the whole text
+		return AskAIToRevise(guiApp, client, prompt, inputForPrompt)
+	}
+	chunks := SplitForContext(guiApp, prompt, inputForPrompt)
+	if len(chunks) == 1 {
+		return AskAIToRevise(guiApp, client, prompt, inputForPrompt)
+	}
+	total := len(chunks)
+	if PromptToText[prompt].reduce != "" {
+		total++
+	}
+	slog.Info("Text is too long for the context window, splitting it", "chunks", len(chunks), "numCtx", NumCtx(guiApp))
+
+	var (
+		lock      sync.Mutex
+		done      int
+		responses = make([]api.GenerateResponse, len(chunks))
+		t         = throttle.NewThrottle(parallelChunks)
+	)
+	var err error
+	for i, chunk := range chunks {
+		if err = t.Do(); err != nil {
+			break
+		}
+		go func() {
+			response, err := AskAIToRevise(guiApp, client, prompt, chunk)
+			responses[i] = response
+			lock.Lock()
+			done++
+			if progress != nil {
+				progress(done, total)
+			}
+			lock.Unlock()
+			t.Done(err)
+		}()
+	}
+	if finishErr := t.Finish(); finishErr != nil {
+		err = finishErr
+	}
+	if err != nil {
+		slog.Error("Failed to process a chunk", "error", err)
+		return api.GenerateResponse{}, err
+	}
+
+	var texts []string
+	for _, r := range responses {
+		texts = append(texts, strings.TrimSpace(r.Response))
+	}
+	merged := responses[len(responses)-1]
+	if PromptToText[prompt].reduce == "" {
+		merged.Response = strings.Join(texts, "\n\n")
+		return merged, nil
+	}
+	reduced, err := reduceResponses(guiApp, client, prompt, texts)
+	if progress != nil {
+		progress(total, total)
+	}
+	return reduced, err
+}
+
+// reduceResponses merges the responses to the chunks with the prompt's reduce
+// prompt, responses that together don't fit in the context window are merged
+// in halves first.
+func reduceResponses(guiApp fyne.App, client *api.Client, prompt PromptMsg, texts []string) (api.GenerateResponse, error) {
+	combined := strings.Join(texts, "\n\n---\n\n")
+	if len(texts) > 2 && EstimateTokens(combined) > chunkBudget(guiApp, prompt) {
+		half := len(texts) / 2
+		first, err := reduceResponses(guiApp, client, prompt, texts[:half])
+		if err != nil {
+			return api.GenerateResponse{}, err
+		}
+		second, err := reduceResponses(guiApp, client, prompt, texts[half:])
+		if err != nil {
+			return api.GenerateResponse{}, err
+		}
+		combined = first.Response + "\n\n---\n\n" + second.Response
+	}
+	return AskAiWithStringAndContext(guiApp, client, nil, PromptToText[prompt].reduce+" [ "+combined+" ]")
+}
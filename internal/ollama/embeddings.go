@@ -0,0 +1,86 @@
+package ollama
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"github.com/ollama/ollama/api"
+
+	"github.com/bahelit/ctrl_plus_revise/internal/config"
+	"github.com/bahelit/ctrl_plus_revise/pkg/docindex"
+)
+
+// DefaultEmbeddingModel is small and made for retrieval.
+const DefaultEmbeddingModel = "nomic-embed-text"
+
+// EmbeddingModel returns the model that embeds documents and questions.
+func EmbeddingModel(guiApp fyne.App) string {
+	model := strings.TrimSpace(guiApp.Preferences().StringWithFallback(config.EmbeddingModelKey, DefaultEmbeddingModel))
+	if model == "" {
+		return DefaultEmbeddingModel
+	}
+	return model
+}
+
+// Embed returns the embedding vector of the text.
+func Embed(guiApp fyne.App, client *api.Client, text string) (docindex.Vector, error) {
+	req := &api.EmbeddingRequest{
+		Model:  EmbeddingModel(guiApp),
+		Prompt: text,
+	}
+	resp, err := client.Embeddings(context.Background(), req)
+	if err != nil {
+		slog.Error("Failed to embed", "error", err, "model", req.Model)
+		return nil, err
+	}
+	return docindex.FromFloat64(resp.Embedding), nil
+}
+
+// PullEmbeddingModel downloads the embedding model unless it is installed.
+func PullEmbeddingModel(guiApp fyne.App, client *api.Client, pf api.PullProgressFunc) error {
+	ctx := context.Background()
+	model := EmbeddingModel(guiApp)
+	if !strings.Contains(model, ":") {
+		model += ":latest"
+	}
+	found, err := FindModel(ctx, client, model)
+	if err != nil || found {
+		return err
+	}
+	slog.Info("Pulling embedding model", "model", model)
+	err = client.Pull(ctx, &api.PullRequest{Model: model}, pf)
+	if err != nil {
+		slog.Error("Failed to pull model", "error", err)
+		return err
+	}
+	return nil
+}
+
+// AskAIWithDocuments answers the question from the passages, the answer
+// cites them by number.
+func AskAIWithDocuments(guiApp fyne.App, client *api.Client, question string, matches []docindex.Match) (api.GenerateResponse, error) {
+	var response api.GenerateResponse
+	req := &api.GenerateRequest{
+		Model:  GetActiveModel(guiApp).String(),
+		Prompt: docindex.Prompt(question, matches),
+		// set streaming to false
+		Stream:  new(bool),
+		Options: requestOptions(guiApp),
+	}
+
+	ctx := context.Background()
+	respFunc := func(resp api.GenerateResponse) error {
+		response = resp
+		return nil
+	}
+
+	err := client.Generate(ctx, req, respFunc)
+	if err != nil {
+		slog.Error("Failed to generate", "error", err)
+		return api.GenerateResponse{}, err
+	}
+
+	return response, nil
+}
@@ -0,0 +1,37 @@
+package ollama
+
+import (
+	"sync"
+
+	"github.com/bahelit/ctrl_plus_revise/pkg/glossary"
+)
+
+var (
+	glossaryLock   sync.RWMutex
+	activeGlossary glossary.Glossary
+)
+
+// SetGlossary replaces the terms added to translation and revision prompts.
+func SetGlossary(g glossary.Glossary) {
+	glossaryLock.Lock()
+	defer glossaryLock.Unlock()
+	activeGlossary = g
+}
+
+func currentGlossary() glossary.Glossary {
+	glossaryLock.RLock()
+	defer glossaryLock.RUnlock()
+	return activeGlossary
+}
+
+// glossaryInstructions returns the prompt text for the glossary terms used in
+// the text, toLang is empty for revisions.
+func glossaryInstructions(text string, toLang Language) string {
+	return currentGlossary().Instructions(text, string(toLang))
+}
+
+// VerifyGlossary returns the glossary terms of the input the model changed in
+// the response, toLang is empty for revisions.
+func VerifyGlossary(input, output string, toLang Language) []glossary.Violation {
+	return currentGlossary().Verify(input, output, string(toLang))
+}
@@ -0,0 +1,170 @@
+package ollama
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"github.com/ollama/ollama/api"
+	"golang.org/x/text/language"
+
+	"github.com/bahelit/ctrl_plus_revise/internal/config"
+	"github.com/bahelit/ctrl_plus_revise/pkg/langdetect"
+)
+
+const (
+	// AutoDetect as the source language works out the language of the text.
+	AutoDetect Language = "Auto-detect"
+	// MyLanguage as the target translates to the user's language, or to their
+	// second language when the text is already in their language.
+	MyLanguage Language = "My language"
+)
+
+var defaultLanguageTags = []language.Tag{
+	language.English,
+	language.Arabic,
+	language.Chinese,
+	language.French,
+	language.German,
+	language.Italian,
+	language.Japanese,
+	language.Portuguese,
+	language.Russian,
+	language.Spanish,
+	language.Turkish,
+}
+
+// LanguageFromTag returns the language with the BCP-47 tag, e.g. "Swahili" for sw.
+func LanguageFromTag(tag language.Tag) Language {
+	return Language(langdetect.Name(tag))
+}
+
+// languageTags returns the built-in languages followed by the ones the user added.
+func languageTags(guiApp fyne.App) []language.Tag {
+	tags := append([]language.Tag{}, defaultLanguageTags...)
+	for _, s := range guiApp.Preferences().StringList(config.TranslationLanguagesKey) {
+		tag, err := langdetect.Parse(s)
+		if err != nil {
+			slog.Warn("Ignoring invalid language tag", "tag", s, "error", err)
+			continue
+		}
+		tags = append(tags, tag)
+	}
+	return tags
+}
+
+// TranslationLanguages returns every language that can be translated to and from.
+func TranslationLanguages(guiApp fyne.App) []Language {
+	var languages []Language
+	for _, tag := range languageTags(guiApp) {
+		languages = append(languages, LanguageFromTag(tag))
+	}
+	return languages
+}
+
+// AddTranslationLanguage adds a BCP-47 language such as "sw" or "pt-BR" to the
+// list of languages.
+func AddTranslationLanguage(guiApp fyne.App, rawTag string) (Language, error) {
+	tag, err := langdetect.Parse(rawTag)
+	if err != nil {
+		return "", err
+	}
+	for _, known := range languageTags(guiApp) {
+		if known == tag {
+			return LanguageFromTag(tag), nil
+		}
+	}
+	added := guiApp.Preferences().StringList(config.TranslationLanguagesKey)
+	guiApp.Preferences().SetStringList(config.TranslationLanguagesKey, append(added, tag.String()))
+	return LanguageFromTag(tag), nil
+}
+
+// LanguageTag returns the BCP-47 tag of a language from the list of
+// translation languages.
+func LanguageTag(guiApp fyne.App, l Language) (language.Tag, bool) {
+	for _, tag := range languageTags(guiApp) {
+		if LanguageFromTag(tag) == l {
+			return tag, true
+		}
+	}
+	return language.Und, false
+}
+
+// sameLanguage reports whether both are the same language, regional variants
+// such as British and American English count as the same language.
+func sameLanguage(guiApp fyne.App, a, b Language) bool {
+	if a == b {
+		return true
+	}
+	aTag, aOK := LanguageTag(guiApp, a)
+	bTag, bOK := LanguageTag(guiApp, b)
+	if !aOK || !bOK {
+		return false
+	}
+	aBase, _ := aTag.Base()
+	bBase, _ := bTag.Base()
+	return aBase == bBase
+}
+
+// DetectLanguage works out the language of the text, the model is only asked
+// when the offline detector isn't sure.
+func DetectLanguage(guiApp fyne.App, client *api.Client, text string) (Language, error) {
+	result := langdetect.Detect(text)
+	if result.Reliable() {
+		slog.Debug("Detected language", "language", result.Tag, "confidence", result.Confidence)
+		return LanguageFromTag(result.Tag), nil
+	}
+	return AskAIToDetectLanguage(guiApp, client, text)
+}
+
+func AskAIToDetectLanguage(guiApp fyne.App, client *api.Client, inputForPrompt string) (Language, error) {
+	var response api.GenerateResponse
+	req := &api.GenerateRequest{
+		Model: GetActiveModel(guiApp).String(),
+		Prompt: "Which language is the following text written in? Answer with only the BCP-47 language code, " +
+			"for example en, fr or pt-BR, and nothing else: [ " + inputForPrompt + " ]",
+		// set streaming to false
+		Stream:  new(bool),
+		Options: requestOptions(guiApp),
+	}
+
+	ctx := context.Background()
+	respFunc := func(resp api.GenerateResponse) error {
+		response = resp
+		return nil
+	}
+
+	err := client.Generate(ctx, req, respFunc)
+	if err != nil {
+		slog.Error("Failed to generate", "error", err)
+		return "", err
+	}
+
+	code := strings.Trim(strings.TrimSpace(response.Response), "\"'`.[]")
+	tag, err := langdetect.Parse(code)
+	if err != nil {
+		slog.Warn("The model didn't answer with a language code", "response", response.Response)
+		return "", err
+	}
+	return LanguageFromTag(tag), nil
+}
+
+// ResolveTranslation replaces AutoDetect and MyLanguage with real languages,
+// the source is empty when it couldn't be detected.
+func ResolveTranslation(guiApp fyne.App, client *api.Client, text string, fromLang, toLang Language) (Language, Language) {
+	if fromLang == AutoDetect {
+		detected, err := DetectLanguage(guiApp, client, text)
+		if err != nil {
+			slog.Warn("Unable to detect the language", "error", err)
+		}
+		fromLang = detected
+	}
+	if toLang == MyLanguage {
+		toLang = Language(guiApp.Preferences().StringWithFallback(config.MyLanguageKey, string(English)))
+		if fromLang != "" && sameLanguage(guiApp, fromLang, toLang) {
+			toLang = Language(guiApp.Preferences().StringWithFallback(config.SecondLanguageKey, string(Spanish)))
+		}
+	}
+	return fromLang, toLang
+}
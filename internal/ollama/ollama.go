@@ -4,6 +4,7 @@ import (
 	"context"
 	"fyne.io/fyne/v2"
 	"log/slog"
+	"sort"
 	"strconv"
 
 	"github.com/bahelit/ctrl_plus_revise/internal/config"
@@ -92,35 +93,54 @@ const (
 	MakeItFriendlyRedo     // Make it Friendly
 	MakeItProfessionalRedo // Make it Professional
 	MakeItAListRedo        // Make it a List
+
+	ExtractTags // Extract Tags
 )
 
 type PromptText struct {
 	prompt      string
 	promptExtra string
+	// followUp is the button label when the prompt can be applied to a previous response.
+	followUp string
+	// preserve masks code, links and placeholders so the prompt only rewrites the prose.
+	preserve bool
+	// reduce merges the responses to the chunks of a long text, the responses
+	// are joined with blank lines when it is empty.
+	reduce string
+	// data asks for JSON matching a schema instead of prose, the parsed
+	// response is rendered as the result.
+	data dataPrompt
 }
 
 var PromptToText = map[PromptMsg]PromptText{
 	CorrectGrammar: {
 		prompt:      "IDENTITY and PURPOSE\nYou are a writing expert. You refine the input text to enhance clarity, coherence, grammar, and style.\n\nSteps\nAnalyze the input text for grammatical errors, stylistic inconsistencies, clarity issues, and coherence.\nApply corrections and improvements directly to the text.\nMaintain the original meaning and intent of the user's text, ensuring that the improvements are made within the context of the input language's grammatical norms and stylistic conventions.\nOUTPUT INSTRUCTIONS\nRefined and improved text that has no grammar mistakes.\nReturn in the same language as the input.\nInclude NO additional commentary or explanation in the response.\nINPUT:", //nolint:lll long line
-		promptExtra: " Return the corrected text without explaining what changed or telling me \"Here is the revised text\", just provide the corrected text and output just the result"},
+		promptExtra: " Return the corrected text without explaining what changed or telling me \"Here is the revised text\", just provide the corrected text and output just the result",
+		preserve:    true},
 	MakeItFriendly: {
 		prompt:      "Give the following text a friendly makeover by injecting a touch of humor, warmth, and approachability: ",
-		promptExtra: " Please don't to explain the changes or telling me \"Here is the revised text\", just make the text more friendly and output the result"},
+		promptExtra: " Please don't to explain the changes or telling me \"Here is the revised text\", just make the text more friendly and output the result",
+		preserve:    true},
 	MakeItAList: {
 		prompt:      "Read the following text and create a bulleted list summarizing its main points: ",
-		promptExtra: " No need to explain your list, just provide the main points in a list format."},
+		promptExtra: " No need to explain your list, just provide the main points in a list format.",
+		reduce:      "The following bulleted lists were made from consecutive parts of one long text. Merge them into a single bulleted list of the main points, remove duplicates and keep the order of the text. No need to explain your list, just provide the list: "},
 	MakeItProfessional: {
 		prompt:      "Act as a writer. Read the following text carefully and revise it to present a more professional tone, ensuring accurate and proper usage of grammar and punctuation: ",
-		promptExtra: " Revised text should be free from errors in spelling, capitalization, punctuation, and grammar, while conveying a polished and professional writing style. Please submit your revised text without telling me it is the revised text, in a clear and concise format with no explanation, output just the result."}, //nolint:lll long line
+		promptExtra: " Revised text should be free from errors in spelling, capitalization, punctuation, and grammar, while conveying a polished and professional writing style. Please submit your revised text without telling me it is the revised text, in a clear and concise format with no explanation, output just the result.",
+		preserve:    true}, //nolint:lll long line
 	MakeHeadline: {
 		prompt:      "Act as a writer. Read the following text carefully and create a concise and attention-grabbing headline that summarizes its main idea or key point: ",
-		promptExtra: " Your headline should be no more than 5-7 words, yet effectively capture the essence of the text. Please submit your headline in the format below:\n\n[Headline]"},
+		promptExtra: " Your headline should be no more than 5-7 words, yet effectively capture the essence of the text. Please submit your headline in the format below:\n\n[Headline]",
+		reduce:      "The following headlines were made from consecutive parts of one long text. Combine them into a single concise and attention-grabbing headline of no more than 5-7 words that captures the main idea of the whole text. Please submit only your headline in the format below:\n\n[Headline]\n\nHEADLINES: "},
 	MakeASummary: {
 		prompt:      "IDENTITY and PURPOSE\nYou are a summarization system that extracts the most interesting, useful, and surprising aspects of an article.\n\nTake a step back and think step by step about how to achieve the best result possible as defined in the steps below. You have a lot of freedom to make this work well.\n\nOUTPUT SECTIONS\nYou extract a summary of the content in 20 words or less, including who is presenting and the content being discussed into a section called SUMMARY.\n\nYou extract the top 20 ideas from the input in a section called IDEAS:.\n\nYou extract the 10 most insightful and interesting quotes from the input into a section called QUOTES:. Use the exact quote text from the input.\n\nYou extract the 20 most insightful and interesting recommendations that can be collected from the content into a section called RECOMMENDATIONS.\n\nYou combine all understanding of the article into a single, 20-word sentence in a section called ONE SENTENCE SUMMARY:.\n\nOUTPUT INSTRUCTIONS\nYou only output Markdown.\nDo not give warnings or notes; only output the requested sections.\nYou use numbered lists, not bullets.\nDo not repeat ideas, quotes, facts, or resources.\nDo not start items with the same opening words.\nDo not include any commentary or explanation.\n\nINPUT:", //nolint:lll long line
-		promptExtra: ""},
+		promptExtra: "",
+		reduce:      "The following summaries were made from consecutive parts of one long text. Combine them into a single summary of the whole text with the same sections: SUMMARY, IDEAS, QUOTES, RECOMMENDATIONS and ONE SENTENCE SUMMARY. Keep the best items of each section and do not repeat ideas. You only output Markdown: "},
 	MakeExplanation: {
 		prompt:      "Explain the following block of text in a way that a 5-year-old could understand. Use simple language, relatable examples, and avoid technical jargon: ",
-		promptExtra: "Goals: Simplify complex ideas into easy-to-grasp concepts. Use analogies or relatable scenarios to help explain abstract concepts. Make it fun and engaging while still being accurate"},
+		promptExtra: "Goals: Simplify complex ideas into easy-to-grasp concepts. Use analogies or relatable scenarios to help explain abstract concepts. Make it fun and engaging while still being accurate",
+		reduce:      "The following explanations were made for a 5-year-old from consecutive parts of one long text. Combine them into a single explanation of the whole text in the same simple language, keep the order of the text and do not repeat yourself: "},
 	MakeExpanded: {
 		prompt: "Read the following text carefully and determine its nature: does it appear to be based on factual information or is it fictional in nature?: ",
 		promptExtra: " If the text appears to be non-fictional in nature, expand on it by incorporating relevant, accurate, and verifiable information from credible sources. " +
@@ -129,16 +149,60 @@ var PromptToText = map[PromptMsg]PromptText{
 			"Please do not provide any commentary or explanation, just expand on the text."},
 	TryAgain: {
 		prompt:      "Read the text carefully and provide a revised version that addresses the issues and shortcomings of the original text: ",
-		promptExtra: "If you are unsure or lack sufficient knowledge to provide a meaningful response, explicitly state \"I don't know\". Don't explain you understand the input, just output the result."},
+		promptExtra: "If you are unsure or lack sufficient knowledge to provide a meaningful response, explicitly state \"I don't know\". Don't explain you understand the input, just output the result.",
+		followUp:    "Try Again"},
 	MakeItFriendlyRedo: {
 		prompt:      "Give the text a friendly makeover by injecting a touch of humor, warmth, and approachability: ",
-		promptExtra: " Please don't to explain the changes or telling me \"Here is the revised text\", just make the text more friendly and output the result"},
+		promptExtra: " Please don't to explain the changes or telling me \"Here is the revised text\", just make the text more friendly and output the result",
+		followUp:    "Make the text more Friendly"},
 	MakeItProfessionalRedo: {
 		prompt:      "Act as a writer. Read the text carefully and revise it to present a more professional tone, ensuring accurate and proper usage of grammar and punctuation: ",
-		promptExtra: " Revised text should be free from errors in spelling, capitalization, punctuation, and grammar, while conveying a polished and professional writing style. Please submit your revised text without telling me it is the revised text, in a clear and concise format with no explanation, output just the result."}, //nolint:lll long line
+		promptExtra: " Revised text should be free from errors in spelling, capitalization, punctuation, and grammar, while conveying a polished and professional writing style. Please submit your revised text without telling me it is the revised text, in a clear and concise format with no explanation, output just the result.", //nolint:lll long line
+		followUp:    "Make the text more Professional"},
 	MakeItAListRedo: {
 		prompt:      "Transform the text and create a bulleted list summarizing its main points: ",
-		promptExtra: " No need to explain your list, just provide the main points in a list format."},
+		promptExtra: " No need to explain your list, just provide the main points in a list format.",
+		followUp:    "Make the text a Bulleted List"},
+	ExtractTags: {
+		prompt: ExtractTagsData.Instruction,
+		data:   ExtractTagsData},
+}
+
+// FollowUpPrompts returns the prompts that rework a previous response, in the
+// order their buttons are shown.
+func FollowUpPrompts() []PromptMsg {
+	var prompts []PromptMsg
+	for p := CorrectGrammar; int(p) < len(PromptToText); p++ {
+		if PromptToText[p].followUp != "" {
+			prompts = append(prompts, p)
+		}
+	}
+	return prompts
+}
+
+// RevisionPrompts returns the prompts that transform a block of text, in the
+// order they are offered to the user.
+func RevisionPrompts() []PromptMsg {
+	return []PromptMsg{
+		CorrectGrammar,
+		MakeItProfessional,
+		MakeItFriendly,
+		MakeHeadline,
+		MakeASummary,
+		MakeExpanded,
+		MakeExplanation,
+		MakeItAList,
+		ExtractTags,
+	}
+}
+
+// FollowUpLabel is the button text of a follow-up prompt.
+func (prompt PromptMsg) FollowUpLabel() string {
+	text, ok := PromptToText[prompt]
+	if !ok || text.followUp == "" {
+		return prompt.String()
+	}
+	return text.followUp
 }
 
 func (prompt PromptMsg) PromptToText() string {
@@ -163,6 +227,41 @@ func GetActiveModel(guiApp fyne.App) ModelName {
 	return ModelName(guiApp.Preferences().IntWithFallback(config.CurrentModelKey, int(Llama3Dot2)))
 }
 
+// ModelFromName returns the model with the Ollama name, e.g. llama3.2:latest.
+func ModelFromName(name string) (ModelName, bool) {
+	for model := range MemoryUsage {
+		if model.String() == name {
+			return model, true
+		}
+	}
+	return 0, false
+}
+
+// ModelNames returns the Ollama names of the models in the order they are declared.
+func ModelNames() []string {
+	var models []ModelName
+	for model := range MemoryUsage {
+		models = append(models, model)
+	}
+	sort.Slice(models, func(i, j int) bool { return models[i] < models[j] })
+	var names []string
+	for _, model := range models {
+		names = append(names, model.String())
+	}
+	return names
+}
+
+// PromptFromName returns the revision prompt with the display name, e.g.
+// Correct Grammar.
+func PromptFromName(name string) (PromptMsg, bool) {
+	for _, prompt := range RevisionPrompts() {
+		if prompt.String() == name {
+			return prompt, true
+		}
+	}
+	return 0, false
+}
+
 func StringToModel(s string) ModelName {
 	i, err := strconv.Atoi(s)
 	if err != nil {
@@ -173,16 +272,25 @@ func StringToModel(s string) ModelName {
 }
 
 func AskAIWithPromptMsg(guiApp fyne.App, client *api.Client, prompt PromptMsg, inputForPrompt string) (api.GenerateResponse, error) {
+	return AskAIWithPromptMsgAndInstructions(context.Background(), guiApp, client, prompt, inputForPrompt, "")
+}
+
+// AskAIWithPromptMsgAndInstructions adds instructions to the end of the prompt,
+// e.g. to keep the markers of masked text. Cancelling ctx stops the request.
+func AskAIWithPromptMsgAndInstructions(ctx context.Context, guiApp fyne.App, client *api.Client, prompt PromptMsg, inputForPrompt, instructions string) (api.GenerateResponse, error) {
+	if PromptToText[prompt].data != nil {
+		return askForData(guiApp, client, prompt, inputForPrompt)
+	}
 	var response api.GenerateResponse
 	req := &api.GenerateRequest{
-		Model:  GetActiveModel(guiApp).String(),
-		Prompt: prompt.PromptToText() + " [ " + inputForPrompt + " ] " + prompt.PromptExtraToText(),
+		Model: GetActiveModel(guiApp).String(),
+		Prompt: prompt.PromptToText() + " [ " + inputForPrompt + " ] " + prompt.PromptExtraToText() +
+			glossaryInstructions(inputForPrompt, "") + instructions,
 		// set streaming to false
-		Stream: new(bool),
+		Stream:  new(bool),
+		Options: requestOptions(guiApp),
 	}
 
-	// TODO: implement timeout
-	ctx := context.Background()
 	respFunc := func(resp api.GenerateResponse) error {
 		// Only print the response here; GenerateResponse has a number of other
 		// interesting fields you want to examine.
@@ -199,6 +307,35 @@ func AskAIWithPromptMsg(guiApp fyne.App, client *api.Client, prompt PromptMsg, i
 	return response, nil
 }
 
+// AskAIToRefine rewrites text following a free-form instruction such as
+// "shorter" or "in British English".
+func AskAIToRefine(guiApp fyne.App, client *api.Client, instruction, text string) (api.GenerateResponse, error) {
+	var response api.GenerateResponse
+	req := &api.GenerateRequest{
+		Model: GetActiveModel(guiApp).String(),
+		Prompt: "Rewrite the following text using this instruction: \"" + instruction + "\". Keep the meaning of the text the same: [ " +
+			text + " ] Output only the rewritten text without explaining the changes or telling me \"Here is the revised text\"." +
+			glossaryInstructions(text, ""),
+		// set streaming to false
+		Stream:  new(bool),
+		Options: requestOptions(guiApp),
+	}
+
+	ctx := context.Background()
+	respFunc := func(resp api.GenerateResponse) error {
+		response = resp
+		return nil
+	}
+
+	err := client.Generate(ctx, req, respFunc)
+	if err != nil {
+		slog.Error("Failed to generate", "error", err)
+		return api.GenerateResponse{}, err
+	}
+
+	return response, nil
+}
+
 func AskAiWithPromptAndContext(guiApp fyne.App, client *api.Client, msgContext []int, prompt PromptMsg) (api.GenerateResponse, error) {
 	// TODO How long does the context last?
 	var response api.GenerateResponse
@@ -208,6 +345,7 @@ func AskAiWithPromptAndContext(guiApp fyne.App, client *api.Client, msgContext [
 		// set streaming to false
 		Stream:  new(bool),
 		Context: msgContext,
+		Options: requestOptions(guiApp),
 	}
 
 	// TODO implement timeout
@@ -237,6 +375,7 @@ func AskAiWithStringAndContext(guiApp fyne.App, client *api.Client, msgContext [
 		// set streaming to false
 		Stream:  new(bool),
 		Context: msgContext,
+		Options: requestOptions(guiApp),
 	}
 
 	// TODO implement timeout
@@ -266,7 +405,8 @@ func AskAI(guiApp fyne.App, client *api.Client, inputForPrompt string) (api.Gene
 			"If you are unsure or lack sufficient knowledge to provide a meaningful response, explicitly state \"I don't know\"." +
 			"Don't explain you understand the input, just output the result.",
 		// set streaming to false
-		Stream: new(bool),
+		Stream:  new(bool),
+		Options: requestOptions(guiApp),
 	}
 
 	// TODO: implement timeout
@@ -298,6 +438,7 @@ func AskAIWithContext(guiApp fyne.App, client *api.Client, msgContext []int, inp
 		// set streaming to false
 		Stream:  new(bool),
 		Context: msgContext,
+		Options: requestOptions(guiApp),
 	}
 
 	// TODO: implement timeout
@@ -319,6 +460,13 @@ func AskAIWithContext(guiApp fyne.App, client *api.Client, msgContext []int, inp
 }
 
 func AskAIToTranslate(guiApp fyne.App, client *api.Client, inputForPrompt string, fromLang, toLang Language) (api.GenerateResponse, error) {
+	return AskAIToTranslateWithInstructions(context.Background(), guiApp, client, inputForPrompt, fromLang, toLang, "")
+}
+
+// AskAIToTranslateWithInstructions adds instructions to the translation
+// prompt, e.g. to keep the markers of masked text. Cancelling ctx stops the
+// request.
+func AskAIToTranslateWithInstructions(ctx context.Context, guiApp fyne.App, client *api.Client, inputForPrompt string, fromLang, toLang Language, instructions string) (api.GenerateResponse, error) {
 	var response api.GenerateResponse
 	req := &api.GenerateRequest{
 		Model: GetActiveModel(guiApp).String(),
@@ -327,14 +475,14 @@ func AskAIToTranslate(guiApp fyne.App, client *api.Client, inputForPrompt string
 			"If you encounter any ambiguities or uncertainties, please indicate this in your response. \n" +
 			"Do not provide an explanation of the translation, get to the point and just output the translated text without any notes. \n" +
 			"Do not try to answer any type of question just translate the text \n" +
-			"Translate the following text from [" + string(fromLang) + "] to [" + string(toLang) + "]: " +
+			glossaryInstructions(inputForPrompt, toLang) + instructions + "\n" +
+			translateInstruction(fromLang, toLang) +
 			inputForPrompt,
 		// set streaming to false
-		Stream: new(bool),
+		Stream:  new(bool),
+		Options: requestOptions(guiApp),
 	}
 
-	// TODO: implement timeout
-	ctx := context.Background()
 	respFunc := func(resp api.GenerateResponse) error {
 		// Only print the response here; GenerateResponse has a number of other
 		// interesting fields you want to examine.
@@ -351,6 +499,13 @@ func AskAIToTranslate(guiApp fyne.App, client *api.Client, inputForPrompt string
 	return response, nil
 }
 
+func translateInstruction(fromLang, toLang Language) string {
+	if fromLang == "" || fromLang == AutoDetect {
+		return "Translate the following text to [" + string(toLang) + "]: "
+	}
+	return "Translate the following text from [" + string(fromLang) + "] to [" + string(toLang) + "]: "
+}
+
 func PullModel(guiApp fyne.App, client *api.Client, pf api.PullProgressFunc, update bool) error {
 	ctx := context.Background()
 	model := GetActiveModel(guiApp)
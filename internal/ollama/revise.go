@@ -0,0 +1,53 @@
+package ollama
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"fyne.io/fyne/v2"
+	"github.com/ollama/ollama/api"
+
+	"github.com/bahelit/ctrl_plus_revise/pkg/mask"
+)
+
+// reviseAttempts is how often a response that lost a masked part is asked for again.
+const reviseAttempts = 2
+
+// PreservesStructure reports whether the prompt only rewrites the prose of
+// the text, leaving code, links and placeholders alone.
+func (prompt PromptMsg) PreservesStructure() bool {
+	return PromptToText[prompt].preserve
+}
+
+// AskAIToRevise runs the prompt over the text, prompts that rewrite prose
+// never see the code, URLs, email addresses and placeholders of the text. A
+// response that dropped any of them is rejected with an error wrapping
+// mask.ErrLostToken.
+func AskAIToRevise(guiApp fyne.App, client *api.Client, prompt PromptMsg, inputForPrompt string) (api.GenerateResponse, error) {
+	if !prompt.PreservesStructure() {
+		return AskAIWithPromptMsg(guiApp, client, prompt, inputForPrompt)
+	}
+	masked := mask.Markdown(inputForPrompt)
+	if masked.Len() == 0 {
+		return AskAIWithPromptMsg(guiApp, client, prompt, inputForPrompt)
+	}
+
+	var err error
+	for attempt := 1; attempt <= reviseAttempts; attempt++ {
+		var response api.GenerateResponse
+		response, err = AskAIWithPromptMsgAndInstructions(context.Background(), guiApp, client, prompt, masked.Text, masked.Instructions())
+		if err != nil {
+			return api.GenerateResponse{}, err
+		}
+		response.Response, err = masked.Restore(response.Response)
+		if err == nil {
+			return response, nil
+		}
+		if !errors.Is(err, mask.ErrLostToken) {
+			return api.GenerateResponse{}, err
+		}
+		slog.Warn("The response lost part of the text", "attempt", attempt, "error", err)
+	}
+	return api.GenerateResponse{}, err
+}
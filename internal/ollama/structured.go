@@ -0,0 +1,184 @@
+package ollama
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"github.com/ollama/ollama/api"
+
+	"github.com/bahelit/ctrl_plus_revise/pkg/jsonschema"
+	"github.com/bahelit/ctrl_plus_revise/pkg/recipe"
+	"github.com/bahelit/ctrl_plus_revise/pkg/suggest"
+)
+
+// jsonRepairs is how often a response that doesn't match the schema is sent
+// back to the model to be fixed.
+const jsonRepairs = 2
+
+// StructuredPrompt asks the model for data instead of prose, the response is
+// JSON matching the schema of T.
+type StructuredPrompt[T any] struct {
+	Name        string
+	Instruction string
+	// Render turns the data into text when the prompt is in the prompt
+	// library and its result is pasted or shown like any other prompt's.
+	Render func(data T) string
+}
+
+// dataPrompt is a structured prompt in the prompt library.
+type dataPrompt interface {
+	Schema() *jsonschema.Schema
+	askForText(guiApp fyne.App, client *api.Client, input string) (string, error)
+}
+
+// Schema returns the JSON schema the response has to match.
+func (p StructuredPrompt[T]) Schema() *jsonschema.Schema {
+	var data T
+	return jsonschema.Reflect(data)
+}
+
+// Tags are the keywords of a text.
+type Tags struct {
+	Tags []string `json:"tags" desc:"Short lowercase keywords, at most ten"`
+}
+
+// GroceryItem is one line of a grocery list.
+type GroceryItem struct {
+	Name     string `json:"name" desc:"The product to buy"`
+	Quantity string `json:"quantity" desc:"How much to buy, e.g. 2 lb or 1 dozen"`
+	Category string `json:"category" enum:"Produce|Meat & Seafood|Dairy & Eggs|Bakery|Pantry|Frozen|Herbs & Spices|Other"`
+}
+
+// GroceryList is a grocery list grouped by category.
+type GroceryList struct {
+	Items []GroceryItem `json:"items"`
+	Tips  []string      `json:"tips,omitempty" desc:"Tips for saving money or making the food last"`
+}
+
+var (
+	ExtractTagsData = StructuredPrompt[Tags]{
+		Name:        "Extract Tags",
+		Instruction: "List the keywords that describe the main topics of the following text.",
+		Render: func(data Tags) string {
+			return strings.Join(data.Tags, ", ")
+		},
+	}
+	MakeGroceryList = StructuredPrompt[GroceryList]{
+		Name:        "Grocery List",
+		Instruction: "Answer the following request with a grocery list.",
+	}
+	MakeRecipe = StructuredPrompt[recipe.Recipe]{
+		Name:        "Recipe",
+		Instruction: "Answer the following request with a recipe.",
+	}
+	SuggestCorrections = StructuredPrompt[suggest.Suggestions]{
+		Name:        "Writing Suggestions",
+		Instruction: suggestionInstruction(),
+	}
+)
+
+// suggestionInstruction turns the Correct Grammar prompt into one that lists
+// the changes with explanations instead of making them.
+func suggestionInstruction() string {
+	purpose, _, _ := strings.Cut(CorrectGrammar.PromptToText(), "OUTPUT INSTRUCTIONS")
+	return purpose + "OUTPUT INSTRUCTIONS\nDo not rewrite the text, list every change you would make to the following paragraph instead, " +
+		"each with the exact words to replace and a short explanation in the language of the paragraph. " +
+		"Return an empty list when the paragraph needs no changes.\nINPUT:"
+}
+
+// AskAIForData sends the input with a structured prompt and parses the
+// response, a response that doesn't match the schema is sent back to the
+// model to be repaired before giving up.
+func AskAIForData[T any](guiApp fyne.App, client *api.Client, p StructuredPrompt[T], input string) (T, error) {
+	var data T
+	schema := p.Schema()
+	prompt := p.Instruction + " [ " + input + " ] " + schemaInstructions(schema) + glossaryInstructions(input, "")
+
+	var err error
+	for attempt := 0; attempt <= jsonRepairs; attempt++ {
+		var response api.GenerateResponse
+		response, err = generateJSON(guiApp, client, prompt)
+		if err != nil {
+			return data, err
+		}
+		err = jsonschema.Decode([]byte(response.Response), &data)
+		if err == nil {
+			return data, nil
+		}
+		if !jsonschema.IsValidationError(err) {
+			return data, err
+		}
+		slog.Warn("The AI response doesn't match the schema, asking for a repair",
+			"prompt", p.Name, "attempt", attempt+1, "error", err)
+		prompt = repairInstructions(schema, response.Response, err)
+	}
+	return data, fmt.Errorf("%s: %w", p.Name, err)
+}
+
+func (p StructuredPrompt[T]) askForText(guiApp fyne.App, client *api.Client, input string) (string, error) {
+	data, err := AskAIForData(guiApp, client, p, input)
+	if err != nil {
+		return "", err
+	}
+	return p.Render(data), nil
+}
+
+// Schema returns the JSON schema a library prompt's response has to match,
+// it is nil for prompts that answer with prose.
+func (prompt PromptMsg) Schema() *jsonschema.Schema {
+	if data := PromptToText[prompt].data; data != nil {
+		return data.Schema()
+	}
+	return nil
+}
+
+// askForData runs a library prompt that declares a schema, the parsed
+// response is rendered as text so it is delivered like a prose response.
+func askForData(guiApp fyne.App, client *api.Client, prompt PromptMsg, inputForPrompt string) (api.GenerateResponse, error) {
+	text, err := PromptToText[prompt].data.askForText(guiApp, client, inputForPrompt)
+	if err != nil {
+		return api.GenerateResponse{}, err
+	}
+	return api.GenerateResponse{Model: GetActiveModel(guiApp).String(), Response: text, Done: true}, nil
+}
+
+// schemaInstructions asks for JSON matching the schema.
+func schemaInstructions(schema *jsonschema.Schema) string {
+	return "Respond only with JSON matching this JSON schema: " + schema.String() +
+		". Do not add explanations or Markdown."
+}
+
+// repairInstructions sends an invalid response back with what is wrong with it.
+func repairInstructions(schema *jsonschema.Schema, response string, problem error) string {
+	return "The following JSON is invalid: " + problem.Error() + ". Fix it without changing its content otherwise. JSON: [ " +
+		strings.TrimSpace(response) + " ] " + schemaInstructions(schema)
+}
+
+func generateJSON(guiApp fyne.App, client *api.Client, prompt string) (api.GenerateResponse, error) {
+	var response api.GenerateResponse
+	req := &api.GenerateRequest{
+		Model:  GetActiveModel(guiApp).String(),
+		Prompt: prompt,
+		Format: "json",
+		// set streaming to false
+		Stream:  new(bool),
+		Options: requestOptions(guiApp),
+	}
+
+	ctx := context.Background()
+	respFunc := func(resp api.GenerateResponse) error {
+		response = resp
+		return nil
+	}
+
+	err := client.Generate(ctx, req, respFunc)
+	if err != nil {
+		slog.Error("Failed to generate", "error", err)
+		return api.GenerateResponse{}, err
+	}
+
+	return response, nil
+}
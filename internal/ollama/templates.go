@@ -0,0 +1,152 @@
+package ollama
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"github.com/ollama/ollama/api"
+
+	"github.com/bahelit/ctrl_plus_revise/internal/config"
+	"github.com/bahelit/ctrl_plus_revise/pkg/prompttemplate"
+)
+
+// PromptTemplate is a prompt written by the user, see package prompttemplate
+// for the variables it can use.
+type PromptTemplate struct {
+	Name string `json:"name"`
+	Text string `json:"text"`
+}
+
+// PromptTemplates returns the user's templates sorted by name.
+func PromptTemplates(guiApp fyne.App) []PromptTemplate {
+	var templates []PromptTemplate
+	saved := guiApp.Preferences().String(config.PromptTemplatesKey)
+	if saved == "" {
+		return nil
+	}
+	if err := json.Unmarshal([]byte(saved), &templates); err != nil {
+		slog.Error("Failed to read prompt templates", "error", err)
+		return nil
+	}
+	slices.SortFunc(templates, func(a, b PromptTemplate) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return templates
+}
+
+// FindPromptTemplate returns the template with the name.
+func FindPromptTemplate(guiApp fyne.App, name string) (PromptTemplate, bool) {
+	for _, t := range PromptTemplates(guiApp) {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return PromptTemplate{}, false
+}
+
+// SavePromptTemplate validates the template and adds it, or replaces the one
+// with the same name.
+func SavePromptTemplate(guiApp fyne.App, t PromptTemplate) error {
+	t.Name = strings.TrimSpace(t.Name)
+	if t.Name == "" {
+		return errors.New("the template needs a name")
+	}
+	if err := prompttemplate.Validate(t.Text, PromptVariables(guiApp)); err != nil {
+		return err
+	}
+	templates := slices.DeleteFunc(PromptTemplates(guiApp), func(saved PromptTemplate) bool {
+		return saved.Name == t.Name
+	})
+	return savePromptTemplates(guiApp, append(templates, t))
+}
+
+// DeletePromptTemplate removes the template with the name.
+func DeletePromptTemplate(guiApp fyne.App, name string) error {
+	templates := slices.DeleteFunc(PromptTemplates(guiApp), func(saved PromptTemplate) bool {
+		return saved.Name == name
+	})
+	if guiApp.Preferences().String(config.CurrentTemplateKey) == name {
+		guiApp.Preferences().SetString(config.CurrentTemplateKey, "")
+	}
+	return savePromptTemplates(guiApp, templates)
+}
+
+func savePromptTemplates(guiApp fyne.App, templates []PromptTemplate) error {
+	data, err := json.Marshal(templates)
+	if err != nil {
+		return err
+	}
+	guiApp.Preferences().SetString(config.PromptTemplatesKey, string(data))
+	return nil
+}
+
+// PromptVariables returns the user's own template variables.
+func PromptVariables(guiApp fyne.App) map[string]string {
+	vars, err := prompttemplate.ParseVars(guiApp.Preferences().String(config.PromptVariablesKey))
+	if err != nil {
+		slog.Error("Failed to read prompt variables", "error", err)
+		return map[string]string{}
+	}
+	return vars
+}
+
+// SavePromptVariables saves variables written one per line as name=value.
+func SavePromptVariables(guiApp fyne.App, text string) error {
+	if _, err := prompttemplate.ParseVars(text); err != nil {
+		return err
+	}
+	guiApp.Preferences().SetString(config.PromptVariablesKey, text)
+	return nil
+}
+
+// RenderPromptTemplate fills in the template, the user's variables are added
+// to data.
+func RenderPromptTemplate(guiApp fyne.App, t PromptTemplate, data prompttemplate.Data) (string, error) {
+	tmpl, err := prompttemplate.Parse(t.Name, t.Text)
+	if err != nil {
+		return "", err
+	}
+	if data.Vars == nil {
+		data.Vars = PromptVariables(guiApp)
+	}
+	if data.Date == "" {
+		data.Date = prompttemplate.Today()
+	}
+	return tmpl.Render(data)
+}
+
+// AskAIWithTemplate sends the rendered template to the model.
+func AskAIWithTemplate(guiApp fyne.App, client *api.Client, t PromptTemplate, data prompttemplate.Data) (api.GenerateResponse, error) {
+	var response api.GenerateResponse
+	prompt, err := RenderPromptTemplate(guiApp, t, data)
+	if err != nil {
+		slog.Error("Failed to render prompt template", "template", t.Name, "error", err)
+		return api.GenerateResponse{}, err
+	}
+	req := &api.GenerateRequest{
+		Model:  GetActiveModel(guiApp).String(),
+		Prompt: prompt + glossaryInstructions(data.Selection, ""),
+		// set streaming to false
+		Stream:  new(bool),
+		Options: requestOptions(guiApp),
+	}
+
+	ctx := context.Background()
+	respFunc := func(resp api.GenerateResponse) error {
+		response = resp
+		return nil
+	}
+
+	err = client.Generate(ctx, req, respFunc)
+	if err != nil {
+		slog.Error("Failed to generate", "error", err)
+		return api.GenerateResponse{}, err
+	}
+
+	return response, nil
+}
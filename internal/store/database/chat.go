@@ -39,7 +39,7 @@ func (db *ChatBot) CreateTable() error {
 	`
 	_, err := db.SQL.Conn.Exec(sqlStmt)
 	if err != nil {
-		slog.Error("Failed to crate table", "error", err, "SQL", sqlStmt)
+		slog.Error("Failed to create table", "error", err, "SQL", sqlStmt)
 		return err
 	}
 	return nil
@@ -0,0 +1,209 @@
+package database
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/bahelit/ctrl_plus_revise/internal/store/database/sqlite"
+	"github.com/bahelit/ctrl_plus_revise/pkg/docindex"
+)
+
+type DocumentStore struct {
+	SQL *sqlite.DB
+}
+
+// IndexedFile is a document whose passages are in the index.
+type IndexedFile struct {
+	// Folder is the indexed folder the document was found in.
+	Folder   string
+	Path     string
+	Size     int64
+	Modified time.Time
+	// Model made the vectors, they can't be compared with another model's.
+	Model string
+}
+
+func NewDocumentStore() (*DocumentStore, error) {
+	db, err := sqlite.GetDatabase()
+	if err != nil {
+		return nil, err
+	}
+	ds := &DocumentStore{SQL: db}
+	err = ds.CreateTable()
+	if err != nil {
+		return nil, err
+	}
+	return ds, nil
+}
+
+// CreateTable creates the document index, a file has many passages and each
+// passage has the embedding vector of its text.
+func (db *DocumentStore) CreateTable() error {
+	sqlStmt := `
+	create table if not exists doc_files (id integer not null primary key, folder text, path text unique, size integer, modified text, model text);
+	create table if not exists doc_chunks (id integer not null primary key, file_id integer not null, line integer, text text, vector blob);
+	create index if not exists doc_chunks_file on doc_chunks (file_id);
+	`
+	_, err := db.SQL.Conn.Exec(sqlStmt)
+	if err != nil {
+		slog.Error("Failed to create table", "error", err, "SQL", sqlStmt)
+		return err
+	}
+	return nil
+}
+
+// GetFiles returns the indexed documents of the folder by their path.
+func (db *DocumentStore) GetFiles(folder string) (map[string]IndexedFile, error) {
+	rows, err := db.SQL.Conn.Query("select folder, path, size, modified, model from doc_files where folder = ?", folder)
+	if err != nil {
+		slog.Error("Failed to query indexed files", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	files := make(map[string]IndexedFile)
+	for rows.Next() {
+		var (
+			file     IndexedFile
+			modified string
+		)
+		err = rows.Scan(&file.Folder, &file.Path, &file.Size, &modified, &file.Model)
+		if err != nil {
+			slog.Error("Failed to scan row", "error", err, "row", rows)
+			return nil, err
+		}
+		file.Modified, _ = time.Parse(time.RFC3339Nano, modified)
+		files[file.Path] = file
+	}
+	err = rows.Err()
+	if err != nil {
+		slog.Error("Failed to scan rows", "error", err, "rows", rows)
+		return nil, err
+	}
+	return files, nil
+}
+
+// SaveFile replaces the passages of the document, the vectors are in the
+// order of the chunks.
+func (db *DocumentStore) SaveFile(file IndexedFile, chunks []docindex.Chunk, vectors []docindex.Vector) error {
+	tx, err := db.SQL.Conn.Begin()
+	if err != nil {
+		slog.Error("Failed to begin transaction", "error", err)
+		return err
+	}
+	_, err = tx.Exec("delete from doc_chunks where file_id in (select id from doc_files where path = ?)", file.Path)
+	if err == nil {
+		_, err = tx.Exec("delete from doc_files where path = ?", file.Path)
+	}
+	if err != nil {
+		slog.Error("Failed to delete the old passages", "error", err, "path", file.Path)
+		_ = tx.Rollback()
+		return err
+	}
+
+	result, err := tx.Exec("INSERT INTO doc_files(folder, path, size, modified, model) VALUES (?, ?, ?, ?, ?)",
+		file.Folder, file.Path, file.Size, file.Modified.Format(time.RFC3339Nano), file.Model)
+	if err != nil {
+		slog.Error("Failed to save indexed file", "error", err)
+		_ = tx.Rollback()
+		return err
+	}
+	fileID, err := result.LastInsertId()
+	if err != nil {
+		slog.Error("Failed to get last insert ID", "error", err)
+		_ = tx.Rollback()
+		return err
+	}
+
+	stmt, err := tx.Prepare("INSERT INTO doc_chunks(file_id, line, text, vector) VALUES (?, ?, ?, ?)")
+	if err != nil {
+		slog.Error("Failed to prepare statement", "error", err)
+		_ = tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+	for i, chunk := range chunks {
+		_, err = stmt.Exec(fileID, chunk.Line, chunk.Text, vectors[i].Encode())
+		if err != nil {
+			slog.Error("Failed to save passage", "error", err)
+			_ = tx.Rollback()
+			return err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		slog.Error("Failed to commit transaction", "error", err)
+		return err
+	}
+	return nil
+}
+
+// DeleteFile removes a document that no longer exists from the index.
+func (db *DocumentStore) DeleteFile(path string) error {
+	_, err := db.SQL.Conn.Exec("delete from doc_chunks where file_id in (select id from doc_files where path = ?)", path)
+	if err == nil {
+		_, err = db.SQL.Conn.Exec("delete from doc_files where path = ?", path)
+	}
+	if err != nil {
+		slog.Error("Failed to delete indexed file", "error", err, "path", path)
+	}
+	return err
+}
+
+// DeleteFolder removes every document of the folder from the index.
+func (db *DocumentStore) DeleteFolder(folder string) error {
+	_, err := db.SQL.Conn.Exec("delete from doc_chunks where file_id in (select id from doc_files where folder = ?)", folder)
+	if err == nil {
+		_, err = db.SQL.Conn.Exec("delete from doc_files where folder = ?", folder)
+	}
+	if err != nil {
+		slog.Error("Failed to delete indexed folder", "error", err, "folder", folder)
+	}
+	return err
+}
+
+// GetPassages returns the passages of the folders embedded with the model.
+func (db *DocumentStore) GetPassages(folders []string, model string) ([]docindex.Passage, error) {
+	rows, err := db.SQL.Conn.Query(`select f.folder, f.path, c.line, c.text, c.vector from doc_chunks c
+		join doc_files f on f.id = c.file_id where f.model = ? order by f.path, c.line`, model)
+	if err != nil {
+		slog.Error("Failed to query passages", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	wanted := make(map[string]bool)
+	for _, folder := range folders {
+		wanted[folder] = true
+	}
+	var passages []docindex.Passage
+	for rows.Next() {
+		var (
+			passage docindex.Passage
+			folder  string
+			vector  []byte
+		)
+		err = rows.Scan(&folder, &passage.Path, &passage.Line, &passage.Text, &vector)
+		if err != nil {
+			slog.Error("Failed to scan row", "error", err, "row", rows)
+			return nil, err
+		}
+		if !wanted[folder] {
+			continue
+		}
+		passage.Vector, err = docindex.DecodeVector(vector)
+		if err != nil {
+			slog.Error("Failed to decode vector", "error", err, "path", passage.Path)
+			continue
+		}
+		passages = append(passages, passage)
+	}
+	err = rows.Err()
+	if err != nil {
+		slog.Error("Failed to scan rows", "error", err, "rows", rows)
+		return nil, err
+	}
+	slog.Debug("Getting passages", "found", len(passages))
+	return passages, nil
+}
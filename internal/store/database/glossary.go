@@ -0,0 +1,144 @@
+package database
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+
+	"github.com/bahelit/ctrl_plus_revise/internal/store/database/sqlite"
+	"github.com/bahelit/ctrl_plus_revise/pkg/glossary"
+)
+
+type GlossaryStore struct {
+	SQL *sqlite.DB
+}
+
+func NewGlossaryStore() (*GlossaryStore, error) {
+	db, err := sqlite.GetDatabase()
+	if err != nil {
+		return nil, err
+	}
+	gs := &GlossaryStore{SQL: db}
+	err = gs.CreateTable()
+	if err != nil {
+		return nil, err
+	}
+	return gs, nil
+}
+
+func (db *GlossaryStore) CreateTable() error {
+	sqlStmt := `
+	create table if not exists glossary (id integer not null primary key, source text, translations text, do_not_translate integer, case_sensitive integer);
+	`
+	_, err := db.SQL.Conn.Exec(sqlStmt)
+	if err != nil {
+		slog.Error("Failed to create table", "error", err, "SQL", sqlStmt)
+		return err
+	}
+	return nil
+}
+
+func (db *GlossaryStore) GetTerms() (glossary.Glossary, error) {
+	rows, err := db.SQL.Conn.Query("select id, source, translations, do_not_translate, case_sensitive from glossary order by source")
+	if err != nil {
+		slog.Error("Failed to query glossary", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var terms glossary.Glossary
+	for rows.Next() {
+		var (
+			term         glossary.Term
+			translations string
+		)
+		term.ID = new(int64)
+		err = rows.Scan(term.ID, &term.Source, &translations, &term.DoNotTranslate, &term.CaseSensitive)
+		if err != nil {
+			slog.Error("Failed to scan row", "error", err, "row", rows)
+			return nil, err
+		}
+		if translations != "" {
+			err = json.Unmarshal([]byte(translations), &term.Translations)
+			if err != nil {
+				slog.Error("Failed to parse term's translations", "error", err, "term", term.Source)
+			}
+		}
+		terms = append(terms, term)
+	}
+	err = rows.Err()
+	if err != nil {
+		slog.Error("Failed to scan rows", "error", err, "rows", rows)
+		return nil, err
+	}
+	slog.Debug("Getting glossary", "found", len(terms))
+	return terms, nil
+}
+
+// SaveTerm inserts a new term or updates it when it has an ID.
+func (db *GlossaryStore) SaveTerm(term *glossary.Term) error {
+	translations, err := json.Marshal(term.Translations)
+	if err != nil {
+		slog.Error("Failed to encode term's translations", "error", err)
+		return err
+	}
+
+	tx, err := db.SQL.Conn.Begin()
+	if err != nil {
+		slog.Error("Failed to begin transaction", "error", err)
+		return err
+	}
+	query := "INSERT INTO glossary(source, translations, do_not_translate, case_sensitive) VALUES (?, ?, ?, ?)"
+	args := []any{term.Source, string(translations), term.DoNotTranslate, term.CaseSensitive}
+	if term.ID != nil {
+		query = "UPDATE glossary SET source = ?, translations = ?, do_not_translate = ?, case_sensitive = ? WHERE id = ?"
+		args = append(args, *term.ID)
+	}
+	stmt, err := tx.Prepare(query)
+	if err != nil {
+		slog.Error("Failed to prepare statement", "error", err)
+		_ = tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+
+	result, err := stmt.Exec(args...)
+	if err != nil {
+		slog.Error("Failed to save term", "error", err)
+		_ = tx.Rollback()
+		return err
+	}
+	err = tx.Commit()
+	if err != nil {
+		slog.Error("Failed to commit transaction", "error", err)
+		return err
+	}
+	if term.ID != nil {
+		return nil
+	}
+	termID, err := result.LastInsertId()
+	if err != nil {
+		slog.Error("Failed to get last insert id", "error", err)
+		return err
+	}
+	term.ID = &termID
+	return nil
+}
+
+func (db *GlossaryStore) DeleteTerm(id int64) error {
+	result, err := db.SQL.Conn.Exec("delete from glossary where id=?", id)
+	if err != nil {
+		slog.Error("Failed to delete term", "error", err)
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		slog.Error("Failed to get rows affected", "error", err)
+		return err
+	}
+	if rowsAffected == 0 {
+		slog.Warn("Glossary term not deleted", "id", id)
+		return errors.New("no rows updated")
+	}
+	return nil
+}
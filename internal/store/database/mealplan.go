@@ -0,0 +1,113 @@
+package database
+
+import (
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/bahelit/ctrl_plus_revise/internal/store/database/sqlite"
+	"github.com/bahelit/ctrl_plus_revise/pkg/mealplan"
+)
+
+type MealPlanStore struct {
+	SQL *sqlite.DB
+}
+
+func NewMealPlanStore() (*MealPlanStore, error) {
+	db, err := sqlite.GetDatabase()
+	if err != nil {
+		return nil, err
+	}
+	ms := &MealPlanStore{SQL: db}
+	err = ms.CreateTable()
+	if err != nil {
+		return nil, err
+	}
+	return ms, nil
+}
+
+// CreateTable creates the meal plan, there is one recipe per meal of a day.
+func (db *MealPlanStore) CreateTable() error {
+	sqlStmt := `
+	create table if not exists meal_plan (day text not null, meal text not null, recipe_id integer, title text, servings integer, primary key (day, meal));
+	`
+	_, err := db.SQL.Conn.Exec(sqlStmt)
+	if err != nil {
+		slog.Error("Failed to create table", "error", err, "SQL", sqlStmt)
+		return err
+	}
+	return db.addColumn("eaters", "text default ''")
+}
+
+// addColumn adds a column to a meal plan saved before the column existed.
+func (db *MealPlanStore) addColumn(name, definition string) error {
+	var count int
+	err := db.SQL.Conn.QueryRow("select count(*) from pragma_table_info('meal_plan') where name = ?", name).Scan(&count)
+	if err != nil || count > 0 {
+		return err
+	}
+	_, err = db.SQL.Conn.Exec("alter table meal_plan add column " + name + " " + definition)
+	if err != nil {
+		slog.Error("Failed to add column", "error", err, "column", name)
+	}
+	return err
+}
+
+// GetWeek returns the plan of the week starting on start.
+func (db *MealPlanStore) GetWeek(start time.Time) (mealplan.Week, error) {
+	week := mealplan.Week{Start: start}
+	end := start.AddDate(0, 0, mealplan.DaysPerWeek)
+	rows, err := db.SQL.Conn.Query("select day, meal, recipe_id, title, servings, eaters from meal_plan where day >= ? and day < ?",
+		start.Format(time.DateOnly), end.Format(time.DateOnly))
+	if err != nil {
+		slog.Error("Failed to query meal plan", "error", err)
+		return week, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			entry       mealplan.Entry
+			day, eaters string
+		)
+		err = rows.Scan(&day, &entry.Meal, &entry.RecipeID, &entry.Title, &entry.Servings, &eaters)
+		if err != nil {
+			slog.Error("Failed to scan row", "error", err, "row", rows)
+			return week, err
+		}
+		if entry.Date, err = time.ParseInLocation(time.DateOnly, day, start.Location()); err != nil {
+			slog.Error("Failed to parse meal plan date", "error", err, "day", day)
+			continue
+		}
+		if eaters != "" {
+			entry.Eaters = strings.Split(eaters, "\n")
+		}
+		week.Entries = append(week.Entries, entry)
+	}
+	err = rows.Err()
+	if err != nil {
+		slog.Error("Failed to scan rows", "error", err, "rows", rows)
+		return week, err
+	}
+	slog.Debug("Getting meal plan", "week", start, "found", len(week.Entries))
+	return week, nil
+}
+
+// SaveEntry plans the entry, replacing what was planned for the meal.
+func (db *MealPlanStore) SaveEntry(entry mealplan.Entry) error {
+	_, err := db.SQL.Conn.Exec("insert or replace into meal_plan(day, meal, recipe_id, title, servings, eaters) values (?, ?, ?, ?, ?, ?)",
+		entry.Date.Format(time.DateOnly), entry.Meal, entry.RecipeID, entry.Title, entry.Servings, strings.Join(entry.Eaters, "\n"))
+	if err != nil {
+		slog.Error("Failed to save meal plan entry", "error", err)
+	}
+	return err
+}
+
+// DeleteEntry clears the meal of the day.
+func (db *MealPlanStore) DeleteEntry(day time.Time, meal string) error {
+	_, err := db.SQL.Conn.Exec("delete from meal_plan where day=? and meal=?", day.Format(time.DateOnly), meal)
+	if err != nil {
+		slog.Error("Failed to delete meal plan entry", "error", err)
+	}
+	return err
+}
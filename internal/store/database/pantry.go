@@ -0,0 +1,143 @@
+package database
+
+import (
+	"errors"
+	"log/slog"
+
+	"github.com/bahelit/ctrl_plus_revise/internal/store/database/sqlite"
+	"github.com/bahelit/ctrl_plus_revise/pkg/pantry"
+)
+
+type PantryStore struct {
+	SQL *sqlite.DB
+}
+
+func NewPantryStore() (*PantryStore, error) {
+	db, err := sqlite.GetDatabase()
+	if err != nil {
+		return nil, err
+	}
+	ps := &PantryStore{SQL: db}
+	err = ps.CreateTable()
+	if err != nil {
+		return nil, err
+	}
+	return ps, nil
+}
+
+func (db *PantryStore) CreateTable() error {
+	sqlStmt := `
+	create table if not exists pantry (id integer not null primary key, name text, category text, quantity real, unit text, purchased text, expires text);
+	`
+	_, err := db.SQL.Conn.Exec(sqlStmt)
+	if err != nil {
+		slog.Error("Failed to create table", "error", err, "SQL", sqlStmt)
+		return err
+	}
+	return nil
+}
+
+// GetItems returns the inventory, the first item to expire first and food
+// that keeps last.
+func (db *PantryStore) GetItems() (pantry.Inventory, error) {
+	rows, err := db.SQL.Conn.Query("select id, name, category, quantity, unit, purchased, expires from pantry " +
+		"order by expires = '', expires, name")
+	if err != nil {
+		slog.Error("Failed to query pantry", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items pantry.Inventory
+	for rows.Next() {
+		var (
+			item               pantry.Item
+			category           string
+			purchased, expires string
+		)
+		item.ID = new(int64)
+		err = rows.Scan(item.ID, &item.Name, &category, &item.Quantity, &item.Unit, &purchased, &expires)
+		if err != nil {
+			slog.Error("Failed to scan row", "error", err, "row", rows)
+			return nil, err
+		}
+		item.Category = pantry.Category(category)
+		if item.Purchased, err = pantry.ParseDate(purchased); err != nil {
+			slog.Error("Failed to parse purchase date", "error", err, "item", item.Name)
+		}
+		if item.Expires, err = pantry.ParseDate(expires); err != nil {
+			slog.Error("Failed to parse expiry date", "error", err, "item", item.Name)
+		}
+		items = append(items, item)
+	}
+	err = rows.Err()
+	if err != nil {
+		slog.Error("Failed to scan rows", "error", err, "rows", rows)
+		return nil, err
+	}
+	slog.Debug("Getting pantry", "found", len(items))
+	return items, nil
+}
+
+// SaveItem inserts a new item or updates it when it has an ID.
+func (db *PantryStore) SaveItem(item *pantry.Item) error {
+	tx, err := db.SQL.Conn.Begin()
+	if err != nil {
+		slog.Error("Failed to begin transaction", "error", err)
+		return err
+	}
+	query := "INSERT INTO pantry(name, category, quantity, unit, purchased, expires) VALUES (?, ?, ?, ?, ?, ?)"
+	args := []any{item.Name, string(item.Category), item.Quantity, item.Unit,
+		pantry.FormatDate(item.Purchased), pantry.FormatDate(item.Expires)}
+	if item.ID != nil {
+		query = "UPDATE pantry SET name = ?, category = ?, quantity = ?, unit = ?, purchased = ?, expires = ? WHERE id = ?"
+		args = append(args, *item.ID)
+	}
+	stmt, err := tx.Prepare(query)
+	if err != nil {
+		slog.Error("Failed to prepare statement", "error", err)
+		_ = tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+
+	result, err := stmt.Exec(args...)
+	if err != nil {
+		slog.Error("Failed to save pantry item", "error", err)
+		_ = tx.Rollback()
+		return err
+	}
+	err = tx.Commit()
+	if err != nil {
+		slog.Error("Failed to commit transaction", "error", err)
+		return err
+	}
+	if item.ID != nil {
+		return nil
+	}
+	itemID, err := result.LastInsertId()
+	if err != nil {
+		slog.Error("Failed to get last insert id", "error", err)
+		return err
+	}
+	item.ID = &itemID
+	return nil
+}
+
+func (db *PantryStore) DeleteItem(id int64) error {
+	result, err := db.SQL.Conn.Exec("delete from pantry where id=?", id)
+	if err != nil {
+		slog.Error("Failed to delete pantry item", "error", err)
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		slog.Error("Failed to get rows affected", "error", err)
+		return err
+	}
+	if rowsAffected == 0 {
+		slog.Warn("Pantry item not deleted", "id", id)
+		return errors.New("no rows updated")
+	}
+	return nil
+}
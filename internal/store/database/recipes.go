@@ -0,0 +1,156 @@
+package database
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/bahelit/ctrl_plus_revise/internal/store/database/sqlite"
+	"github.com/bahelit/ctrl_plus_revise/pkg/recipe"
+)
+
+type RecipeStore struct {
+	SQL *sqlite.DB
+}
+
+func NewRecipeStore() (*RecipeStore, error) {
+	db, err := sqlite.GetDatabase()
+	if err != nil {
+		return nil, err
+	}
+	rs := &RecipeStore{SQL: db}
+	err = rs.CreateTable()
+	if err != nil {
+		return nil, err
+	}
+	return rs, nil
+}
+
+// CreateTable creates the recipe book, the recipe itself is kept as JSON and
+// the columns the user edits next to it.
+func (db *RecipeStore) CreateTable() error {
+	sqlStmt := `
+	create table if not exists recipes (id integer not null primary key, title text, data text, rating integer, notes text, saved text);
+	`
+	_, err := db.SQL.Conn.Exec(sqlStmt)
+	if err != nil {
+		slog.Error("Failed to create table", "error", err, "SQL", sqlStmt)
+		return err
+	}
+	return nil
+}
+
+// GetRecipes returns the recipes matching the query, the best rated first.
+func (db *RecipeStore) GetRecipes(query string) ([]recipe.Recipe, error) {
+	rows, err := db.SQL.Conn.Query("select id, data, rating, notes, saved from recipes order by rating desc, title")
+	if err != nil {
+		slog.Error("Failed to query recipes", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var recipes []recipe.Recipe
+	for rows.Next() {
+		var (
+			r           recipe.Recipe
+			data, saved string
+			id          int64
+			rating      int
+			notes       string
+		)
+		err = rows.Scan(&id, &data, &rating, &notes, &saved)
+		if err != nil {
+			slog.Error("Failed to scan row", "error", err, "row", rows)
+			return nil, err
+		}
+		if err = json.Unmarshal([]byte(data), &r); err != nil {
+			slog.Error("Failed to read recipe", "error", err, "id", id)
+			continue
+		}
+		r.ID, r.Rating, r.Notes = &id, rating, notes
+		if r.Saved, err = time.Parse(time.RFC3339, saved); err != nil {
+			slog.Error("Failed to parse saved date", "error", err, "recipe", r.Title)
+		}
+		if r.Matches(query) {
+			recipes = append(recipes, r)
+		}
+	}
+	err = rows.Err()
+	if err != nil {
+		slog.Error("Failed to scan rows", "error", err, "rows", rows)
+		return nil, err
+	}
+	slog.Debug("Getting recipes", "query", query, "found", len(recipes))
+	return recipes, nil
+}
+
+// SaveRecipe inserts a new recipe or updates it when it has an ID.
+func (db *RecipeStore) SaveRecipe(r *recipe.Recipe) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		slog.Error("Failed to write recipe", "error", err)
+		return err
+	}
+	if r.Saved.IsZero() {
+		r.Saved = time.Now()
+	}
+	tx, err := db.SQL.Conn.Begin()
+	if err != nil {
+		slog.Error("Failed to begin transaction", "error", err)
+		return err
+	}
+	query := "INSERT INTO recipes(title, data, rating, notes, saved) VALUES (?, ?, ?, ?, ?)"
+	args := []any{r.Title, string(data), r.Rating, r.Notes, r.Saved.Format(time.RFC3339)}
+	if r.ID != nil {
+		query = "UPDATE recipes SET title = ?, data = ?, rating = ?, notes = ?, saved = ? WHERE id = ?"
+		args = append(args, *r.ID)
+	}
+	stmt, err := tx.Prepare(query)
+	if err != nil {
+		slog.Error("Failed to prepare statement", "error", err)
+		_ = tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+
+	result, err := stmt.Exec(args...)
+	if err != nil {
+		slog.Error("Failed to save recipe", "error", err)
+		_ = tx.Rollback()
+		return err
+	}
+	err = tx.Commit()
+	if err != nil {
+		slog.Error("Failed to commit transaction", "error", err)
+		return err
+	}
+	if r.ID != nil {
+		return nil
+	}
+	recipeID, err := result.LastInsertId()
+	if err != nil {
+		slog.Error("Failed to get last insert id", "error", err)
+		return err
+	}
+	r.ID = &recipeID
+	return nil
+}
+
+func (db *RecipeStore) DeleteRecipe(id int64) error {
+	result, err := db.SQL.Conn.Exec("delete from recipes where id=?", id)
+	if err != nil {
+		slog.Error("Failed to delete recipe", "error", err)
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		slog.Error("Failed to get rows affected", "error", err)
+		return err
+	}
+	if rowsAffected == 0 {
+		slog.Warn("Recipe not deleted", "id", id)
+		return errors.New("no rows updated")
+	}
+	return nil
+}
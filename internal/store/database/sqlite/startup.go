@@ -6,6 +6,7 @@ import (
 	"os"
 	"os/user"
 	"path/filepath"
+	"sync"
 
 	_ "github.com/mattn/go-sqlite3"
 )
@@ -14,7 +15,20 @@ type DB struct {
 	Conn *sql.DB
 }
 
+var (
+	lock   sync.Mutex
+	shared *DB
+)
+
+// GetDatabase returns the connection pool of the database, it is opened on
+// the first call and shared by every store so opening a store doesn't leak a
+// connection.
 func GetDatabase() (*DB, error) {
+	lock.Lock()
+	defer lock.Unlock()
+	if shared != nil {
+		return shared, nil
+	}
 	sqlFile := filepath.Join(createFolder(), "ctrl_plus_revise.db")
 
 	SQLiteConn, err := sql.Open("sqlite3", sqlFile)
@@ -22,7 +36,8 @@ func GetDatabase() (*DB, error) {
 		slog.Error("failed to open database connection", "path", sqlFile, "error", err)
 		return nil, err
 	}
-	return &DB{Conn: SQLiteConn}, nil
+	shared = &DB{Conn: SQLiteConn}
+	return shared, nil
 }
 
 func createFolder() string {
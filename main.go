@@ -14,6 +14,7 @@ import (
 	ollamaApi "github.com/ollama/ollama/api"
 
 	"github.com/bahelit/ctrl_plus_revise/internal/config"
+	"github.com/bahelit/ctrl_plus_revise/internal/gui/glossary"
 	"github.com/bahelit/ctrl_plus_revise/internal/gui/loading"
 	"github.com/bahelit/ctrl_plus_revise/internal/gui/settings"
 	"github.com/bahelit/ctrl_plus_revise/internal/gui/shortcuts"
@@ -28,6 +29,10 @@ var (
 )
 
 func main() {
+	if opts := parseFlags(); opts.workflow != "" {
+		os.Exit(runWorkflow(opts))
+	}
+
 	slog.Info("Starting Ctr+Revise gui Service...", "Version", version.Version, "Compiler", runtime.Version())
 	guiApp = app.NewWithID("com.ctrlplusrevise.app")
 	guiApp.Settings().SetTheme(theme.AdwaitaTheme())
@@ -37,6 +42,7 @@ func main() {
 	ollamaClient = ollama.CheckOllamaConnection(guiApp, ollamaClient, nil)
 
 	// Prepare the loading screen and system tray
+	registerModules()
 	startupWindow := loading.StartupScreen(guiApp)
 	sysTray := SetupSysTray(guiApp, ollamaClient)
 	if guiApp.Preferences().BoolWithFallback(config.ShowStartWindowKey, true) {
@@ -61,6 +67,8 @@ func main() {
 		startupWindow.Close()
 	}()
 
+	shortcuts.SetupSpeech(guiApp)
+	glossary.Load()
 	//sayHello()
 
 	// Listen for global hotkeys
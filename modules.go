@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/theme"
+	ollamaApi "github.com/ollama/ollama/api"
+
+	"github.com/bahelit/ctrl_plus_revise/internal/gui/batch"
+	"github.com/bahelit/ctrl_plus_revise/internal/gui/chat"
+	"github.com/bahelit/ctrl_plus_revise/internal/gui/documents"
+	"github.com/bahelit/ctrl_plus_revise/internal/gui/food"
+	"github.com/bahelit/ctrl_plus_revise/internal/gui/modules"
+	"github.com/bahelit/ctrl_plus_revise/internal/gui/question"
+	"github.com/bahelit/ctrl_plus_revise/internal/gui/settings"
+	"github.com/bahelit/ctrl_plus_revise/internal/gui/translator"
+	"github.com/bahelit/ctrl_plus_revise/internal/gui/writing"
+)
+
+// registerModules adds the feature windows to the module registry, in the
+// order they are shown in the system tray and on the home screen.
+func registerModules() {
+	modules.Register(
+		modules.Module{
+			ID:          "question",
+			Name:        "Ask a Question",
+			Description: "Ask the AI a question and get a single answer.",
+			Icon:        theme.QuestionIcon(),
+			Open:        question.AskQuestionWindow,
+			Hotkeys: []modules.Hotkey{
+				{Name: "Open the question window", Keys: []string{"q", "alt"}, Action: question.AskQuestionWindow},
+			},
+		},
+		modules.Module{
+			ID:          "chat",
+			Name:        "Chat with AI",
+			Description: "Hold a conversation with the AI and keep the history.",
+			Icon:        theme.MailComposeIcon(),
+			Open:        chat.ConversationManager,
+		},
+		modules.Module{
+			ID:          "documents",
+			Name:        "Ask Your Documents",
+			Description: "Index folders of docs, notes and code and ask questions answered from them with the sources cited.",
+			Icon:        theme.DocumentIcon(),
+			Open:        documents.DocumentsWindow,
+		},
+		modules.Module{
+			ID:          "writing",
+			Name:        "Writing Assistant",
+			Description: "An editor that checks grammar and style as you write and explains every suggestion.",
+			Icon:        theme.DocumentCreateIcon(),
+			Open:        writing.WritingAssistant,
+			Hotkeys: []modules.Hotkey{
+				{Name: "Open the writing assistant", Keys: []string{"w", "alt"}, Action: writing.WritingAssistant},
+			},
+		},
+		modules.Module{
+			ID:          "meal-planner",
+			Name:        "Meal Planner",
+			Description: "Plan meals from the pantry, keep a recipe book and a weekly calendar.",
+			Icon:        theme.HomeIcon(),
+			Open:        food.MealPlanner,
+			Settings: func(guiApp fyne.App, _ *ollamaApi.Client) {
+				food.HouseholdWindow(guiApp, nil)
+			},
+			Hotkeys: []modules.Hotkey{
+				{Name: "Open the meal planner", Keys: []string{"m", "alt"}, Action: food.MealPlanner},
+			},
+		},
+		modules.Module{
+			ID:          "translator",
+			Name:        "Translate Text",
+			Description: "Translate text between the languages you speak.",
+			Icon:        theme.ViewRefreshIcon(),
+			Open:        translator.TranslateText,
+			Settings: func(guiApp fyne.App, _ *ollamaApi.Client) {
+				settings.ShowTranslationSettings(guiApp)
+			},
+		},
+		modules.Module{
+			ID:          "batch",
+			Name:        "Batch Files",
+			Description: "Run a prompt over many files at once.",
+			Icon:        theme.FolderOpenIcon(),
+			Open:        batch.BatchWindow,
+		},
+	)
+}
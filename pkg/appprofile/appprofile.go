@@ -0,0 +1,100 @@
+// Package appprofile picks the settings for the application the user is
+// working in, e.g. a casual tone in a chat program and a formal one in email.
+package appprofile
+
+import (
+	"path"
+	"strings"
+)
+
+// Window is the focused window when a keyboard shortcut was pressed.
+type Window struct {
+	// Process is the name of the program, e.g. slack or thunderbird.
+	Process string
+	// Title is the title of the window.
+	Title string
+}
+
+// Profile holds the settings for the applications matching Pattern, empty
+// settings are taken from the fallback profile.
+type Profile struct {
+	Name string `json:"name"`
+	// Pattern is matched against the process name and window title, ignoring
+	// case. Patterns with * or ? are globs, the others match a part of the
+	// name or title. Several patterns can be separated by |.
+	Pattern  string `json:"pattern"`
+	Prompt   string `json:"prompt,omitempty"`
+	Model    string `json:"model,omitempty"`
+	Delivery string `json:"delivery,omitempty"`
+	Language string `json:"language,omitempty"`
+}
+
+// Matches reports whether the profile applies to the window.
+func (p Profile) Matches(w Window) bool {
+	for _, pattern := range strings.Split(p.Pattern, "|") {
+		pattern = strings.ToLower(strings.TrimSpace(pattern))
+		if pattern == "" {
+			continue
+		}
+		if matches(pattern, w.Process) || matches(pattern, w.Title) {
+			return true
+		}
+	}
+	return false
+}
+
+func matches(pattern, name string) bool {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return false
+	}
+	if !strings.ContainsAny(pattern, "*?") {
+		return strings.Contains(name, pattern)
+	}
+	ok, err := path.Match(pattern, name)
+	return err == nil && ok
+}
+
+// Valid reports whether the glob patterns of the profile can be used.
+func (p Profile) Valid() error {
+	for _, pattern := range strings.Split(p.Pattern, "|") {
+		if _, err := path.Match(strings.TrimSpace(pattern), ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// over fills the empty settings of p with the ones of fallback.
+func (p Profile) over(fallback Profile) Profile {
+	if p.Prompt == "" {
+		p.Prompt = fallback.Prompt
+	}
+	if p.Model == "" {
+		p.Model = fallback.Model
+	}
+	if p.Delivery == "" {
+		p.Delivery = fallback.Delivery
+	}
+	if p.Language == "" {
+		p.Language = fallback.Language
+	}
+	return p
+}
+
+// Profiles are checked in order, the first one matching the window is used.
+type Profiles struct {
+	Rules    []Profile `json:"rules"`
+	Fallback Profile   `json:"fallback"`
+}
+
+// Resolve returns the profile for the window with the empty settings taken
+// from the fallback, matched is false when only the fallback applies.
+func (ps Profiles) Resolve(w Window) (profile Profile, matched bool) {
+	for _, p := range ps.Rules {
+		if p.Matches(w) {
+			return p.over(ps.Fallback), true
+		}
+	}
+	return ps.Fallback, false
+}
@@ -0,0 +1,50 @@
+package appprofile_test
+
+import (
+	"testing"
+
+	"github.com/bahelit/ctrl_plus_revise/pkg/appprofile"
+)
+
+var profiles = appprofile.Profiles{
+	Rules: []appprofile.Profile{
+		{Name: "Chat", Pattern: "slack|discord", Prompt: "Make it Friendly"},
+		{Name: "Email", Pattern: "thunderbird|*outlook*", Prompt: "Make it Professional", Delivery: "Review the changes before pasting"},
+		{Name: "Code", Pattern: "code", Model: "codellama:latest"},
+	},
+	Fallback: appprofile.Profile{Name: "Fallback", Prompt: "Correct Grammar", Model: "llama3.2:latest"},
+}
+
+var resolveTable = []struct {
+	Window  appprofile.Window
+	Name    string
+	Matched bool
+	Prompt  string
+	Model   string
+}{
+	{appprofile.Window{Process: "slack", Title: "general"}, "Chat", true, "Make it Friendly", "llama3.2:latest"},
+	{appprofile.Window{Process: "Discord", Title: ""}, "Chat", true, "Make it Friendly", "llama3.2:latest"},
+	{appprofile.Window{Process: "firefox", Title: "Inbox - Outlook"}, "Email", true, "Make it Professional", "llama3.2:latest"},
+	{appprofile.Window{Process: "code", Title: "main.go"}, "Code", true, "Correct Grammar", "codellama:latest"},
+	{appprofile.Window{Process: "gedit", Title: "notes.txt"}, "Fallback", false, "Correct Grammar", "llama3.2:latest"},
+	{appprofile.Window{}, "Fallback", false, "Correct Grammar", "llama3.2:latest"},
+}
+
+func Test_Resolve(t *testing.T) {
+	for _, v := range resolveTable {
+		p, matched := profiles.Resolve(v.Window)
+		if p.Name != v.Name || matched != v.Matched || p.Prompt != v.Prompt || p.Model != v.Model {
+			t.Errorf("Expected %s (matched=%v, %s, %s) for %+v, received %s (matched=%v, %s, %s)",
+				v.Name, v.Matched, v.Prompt, v.Model, v.Window, p.Name, matched, p.Prompt, p.Model)
+		}
+	}
+}
+
+func Test_Valid(t *testing.T) {
+	if err := (appprofile.Profile{Pattern: "slack|*mail*"}).Valid(); err != nil {
+		t.Errorf("Expected a valid pattern, received %v", err)
+	}
+	if err := (appprofile.Profile{Pattern: "[slack"}).Valid(); err == nil {
+		t.Error("Expected an error for an unclosed bracket")
+	}
+}
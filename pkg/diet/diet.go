@@ -0,0 +1,245 @@
+// Package diet keeps the household's allergies, diets and dislikes, turns
+// them into instructions for a meal prompt and checks the ingredients of a
+// generated recipe against them.
+package diet
+
+import (
+	"slices"
+	"strings"
+)
+
+// rule is what an allergy or diet forbids, keywords match the start of a
+// word of the ingredient and exceptions are ingredients that only look like
+// they match, e.g. eggplant isn't an egg and vegan butter isn't dairy.
+type rule struct {
+	name       string
+	keywords   []string
+	exceptions []string
+}
+
+var (
+	plantMilks  = []string{"coconut milk", "almond milk", "oat milk", "soy milk", "rice milk", "cashew milk"}
+	notWheat    = []string{"buckwheat", "almond flour", "rice flour", "coconut flour", "corn flour", "chickpea flour", "gluten-free", "gluten free", "tamari", "gluten-free soy sauce"}
+	meat        = []string{"chicken", "beef", "pork", "turkey", "bacon", "sausage", "ham", "lamb", "veal", "duck", "steak", "mince", "prosciutto", "salami", "pepperoni", "chorizo", "lard", "gelatin", "venison"}
+	seafood     = []string{"fish", "salmon", "tuna", "cod", "anchov", "sardine", "tilapia", "halibut", "trout", "shrimp", "prawn", "crab", "lobster", "clam", "mussel", "oyster", "scallop", "squid"}
+	dairy       = []string{"milk", "cheese", "butter", "cream", "yogurt", "yoghurt", "whey", "casein", "ghee", "parmesan", "mozzarella", "feta", "ricotta"}
+	nonDairy    = append([]string{"dairy-free", "peanut butter", "almond butter", "cocoa butter", "cream of tartar"}, plantMilks...)
+	alcohol     = []string{"wine", "beer", "rum", "vodka", "brandy", "sake", "mirin", "bourbon", "whiskey"}
+	highCarb    = []string{"sugar", "rice", "pasta", "spaghetti", "noodle", "bread", "potato", "flour", "tortilla", "oat", "honey", "corn"}
+	gluten      = []string{"wheat", "flour", "barley", "rye", "bread", "pasta", "spaghetti", "couscous", "seitan", "beer", "noodle", "tortilla", "breadcrumb", "panko", "soy sauce", "malt", "worcestershire"}
+	glutenFree  = append(slices.Clone(notWheat), "rice noodle", "corn tortilla")
+	highSodium  = []string{"soy sauce", "fish sauce", "bouillon", "stock cube", "bacon", "ham", "salami", "pickle", "anchov", "miso"}
+	allergyList = []rule{
+		{"Peanut", []string{"peanut"}, nil},
+		{"Tree Nut", []string{"almond", "cashew", "walnut", "pecan", "hazelnut", "pistachio", "macadamia", "brazil nut", "pine nut", "praline", "marzipan"}, nil},
+		{"Milk", dairy, nonDairy},
+		{"Egg", []string{"egg", "mayonnaise", "mayo", "meringue", "aioli"}, []string{"eggplant"}},
+		{"Wheat", []string{"wheat", "flour", "bread", "pasta", "spaghetti", "couscous", "semolina", "bulgur", "spelt", "noodle", "tortilla", "breadcrumb", "panko", "soy sauce", "malt", "worcestershire"}, append(notWheat, "rice noodle")},
+		{"Soy", []string{"soy", "tofu", "tempeh", "edamame", "miso"}, nil},
+		{"Fish", []string{"fish", "salmon", "tuna", "cod", "anchov", "sardine", "tilapia", "halibut", "trout", "mackerel"}, []string{"shellfish"}},
+		{"Shellfish", []string{"shellfish", "shrimp", "prawn", "crab", "lobster", "clam", "mussel", "oyster", "scallop", "crayfish"}, nil},
+		{"Sesame", []string{"sesame", "tahini"}, nil},
+		{"Mustard", []string{"mustard"}, nil},
+		{"Corn", []string{"corn", "polenta", "maize", "grits"}, []string{"peppercorn"}},
+		{"Gluten", gluten, glutenFree},
+		{"Sulfite", []string{"wine", "dried apricot", "sulfite"}, nil},
+		{"Celery", []string{"celery", "celeriac"}, nil},
+		{"Lupin", []string{"lupin"}, nil},
+	}
+	dietList = []rule{
+		{"Vegetarian", append(slices.Clone(meat), seafood...), nil},
+		{"Vegan", append(append(append(slices.Clone(meat), seafood...), dairy...), "egg", "honey", "mayonnaise"), append(slices.Clone(nonDairy), "eggplant", "vegan")},
+		{"Pescatarian", meat, nil},
+		{"Keto", highCarb, []string{"cauliflower rice", "almond flour", "coconut flour", "sugar-free", "sugar free"}},
+		{"Halal", append([]string{"pork", "bacon", "ham", "lard", "gelatin", "prosciutto", "salami", "pepperoni", "chorizo"}, alcohol...), []string{"halal"}},
+		{"Low-Sodium", highSodium, []string{"low sodium", "low-sodium", "reduced sodium"}},
+		{"Gluten-Free", gluten, glutenFree},
+		{"Dairy-Free", dairy, nonDairy},
+	}
+)
+
+// Allergies returns the allergies that can be declared.
+func Allergies() []string {
+	return names(allergyList)
+}
+
+// Diets returns the diets that can be followed.
+func Diets() []string {
+	return names(dietList)
+}
+
+func names(rules []rule) []string {
+	var list []string
+	for _, r := range rules {
+		list = append(list, r.name)
+	}
+	return list
+}
+
+// Restrictions are what the household can't or won't eat.
+type Restrictions struct {
+	Allergies []string `json:"allergies,omitempty"`
+	Diets     []string `json:"diets,omitempty"`
+	Dislikes  []string `json:"dislikes,omitempty"`
+}
+
+// Empty reports whether nothing is restricted.
+func (r Restrictions) Empty() bool {
+	return len(r.Allergies) == 0 && len(r.Diets) == 0 && len(r.Dislikes) == 0
+}
+
+// Merge returns the restrictions of both, e.g. of everybody eating.
+func (r Restrictions) Merge(other Restrictions) Restrictions {
+	return Restrictions{
+		Allergies: union(r.Allergies, other.Allergies),
+		Diets:     union(r.Diets, other.Diets),
+		Dislikes:  union(r.Dislikes, other.Dislikes),
+	}
+}
+
+func union(a, b []string) []string {
+	var list []string
+	for _, s := range append(slices.Clone(a), b...) {
+		s = strings.TrimSpace(s)
+		if s != "" && !slices.ContainsFunc(list, func(l string) bool { return strings.EqualFold(l, s) }) {
+			list = append(list, s)
+		}
+	}
+	return list
+}
+
+// Prompt returns the instructions for a meal prompt, it starts with a period
+// so it can be appended to the prompt and is empty without restrictions.
+func (r Restrictions) Prompt() string {
+	var b strings.Builder
+	if len(r.Allergies) > 0 {
+		b.WriteString(". Please be aware of our food allergy to " + strings.Join(r.Allergies, ", ") +
+			", never use an ingredient that contains them")
+	}
+	if len(r.Diets) > 0 {
+		b.WriteString(". Every dish must be " + strings.Join(r.Diets, ", "))
+	}
+	if len(r.Dislikes) > 0 {
+		b.WriteString(". Please avoid " + strings.Join(r.Dislikes, ", ") + ", we don't like them")
+	}
+	return b.String()
+}
+
+// ParseList reads a comma separated list, e.g. of dislikes.
+func ParseList(s string) []string {
+	return union(strings.Split(s, ","), nil)
+}
+
+// Flag is an ingredient that breaks a restriction.
+type Flag struct {
+	Ingredient string
+	// Reason is the allergy, diet or dislike it breaks.
+	Reason string
+	// Allergen is set when the ingredient may cause an allergic reaction.
+	Allergen bool
+}
+
+// Check returns the ingredients that break the restrictions, allergens first.
+func (r Restrictions) Check(ingredients []string) []Flag {
+	var flags []Flag
+	for _, ingredient := range ingredients {
+		for _, allergy := range r.Allergies {
+			if rule, ok := findRule(allergyList, allergy); ok && rule.matches(ingredient) {
+				flags = append(flags, Flag{Ingredient: ingredient, Reason: rule.name, Allergen: true})
+			} else if !ok && containsWord(ingredient, allergy) {
+				flags = append(flags, Flag{Ingredient: ingredient, Reason: allergy, Allergen: true})
+			}
+		}
+	}
+	for _, ingredient := range ingredients {
+		for _, name := range r.Diets {
+			if rule, ok := findRule(dietList, name); ok && rule.matches(ingredient) {
+				flags = append(flags, Flag{Ingredient: ingredient, Reason: "not " + rule.name})
+			}
+		}
+		for _, dislike := range r.Dislikes {
+			if containsWord(ingredient, dislike) {
+				flags = append(flags, Flag{Ingredient: ingredient, Reason: "disliked " + dislike})
+			}
+		}
+	}
+	return flags
+}
+
+// HasAllergen reports whether any flag is an allergen.
+func HasAllergen(flags []Flag) bool {
+	return slices.ContainsFunc(flags, func(f Flag) bool { return f.Allergen })
+}
+
+func findRule(rules []rule, name string) (rule, bool) {
+	for _, r := range rules {
+		if strings.EqualFold(r.name, strings.TrimSpace(name)) {
+			return r, true
+		}
+	}
+	return rule{}, false
+}
+
+// modifiers are exceptions that describe the word after them, e.g. the
+// flour of "gluten-free flour" and "buckwheat flour".
+var modifiers = map[string]bool{
+	"gluten free": true, "dairy free": true, "sugar free": true, "vegan": true, "halal": true, "buckwheat": true,
+	"low sodium": true, "reduced sodium": true,
+}
+
+// matches removes the exceptions from the ingredient and checks what is left,
+// so "eggplant and egg batter" still has an egg. Longer exceptions are
+// removed first so "gluten-free soy sauce" isn't left with "soy sauce".
+func (r rule) matches(ingredient string) bool {
+	text := " " + normalize(ingredient) + " "
+	exceptions := slices.Clone(r.exceptions)
+	slices.SortStableFunc(exceptions, func(a, b string) int { return len(b) - len(a) })
+	for _, exception := range exceptions {
+		text = removeWords(text, normalize(exception))
+	}
+	for _, keyword := range r.keywords {
+		if containsWord(text, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// removeWords removes the words starting with the phrase from the text, and
+// the word after it when the phrase is a modifier. The text starts and ends
+// with a space.
+func removeWords(text, phrase string) string {
+	if phrase == "" {
+		return text
+	}
+	for {
+		i := strings.Index(text, " "+phrase)
+		if i < 0 {
+			return text
+		}
+		end := i + 1 + len(phrase)
+		end += strings.IndexByte(text[end:], ' ')
+		if modifiers[phrase] {
+			if next := strings.IndexByte(text[end+1:], ' '); next >= 0 {
+				end += 1 + next
+			}
+		}
+		text = text[:i] + text[end:]
+	}
+}
+
+// containsWord reports whether a word of the text starts with the keyword,
+// ignoring case and punctuation, so "eggs" contains "egg" but "veggie" doesn't.
+func containsWord(text, keyword string) bool {
+	keyword = normalize(keyword)
+	if keyword == "" {
+		return false
+	}
+	return strings.Contains(" "+normalize(text), " "+keyword)
+}
+
+func normalize(s string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return r == ' ' || r == ',' || r == '(' || r == ')' || r == '/' || r == '-'
+	}), " ")
+}
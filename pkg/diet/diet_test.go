@@ -0,0 +1,101 @@
+package diet_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/bahelit/ctrl_plus_revise/pkg/diet"
+)
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name         string
+		restrictions diet.Restrictions
+		ingredients  []string
+		want         []string
+	}{
+		{
+			name:         "allergens",
+			restrictions: diet.Restrictions{Allergies: []string{"Peanut", "Milk", "Egg"}},
+			ingredients:  []string{"2 eggs", "1 eggplant", "peanut butter", "coconut milk", "Butter", "Parmesan cheese"},
+			want:         []string{"2 eggs: Egg!", "peanut butter: Peanut!", "Butter: Milk!", "Parmesan cheese: Milk!"},
+		},
+		{
+			name:         "gluten exceptions",
+			restrictions: diet.Restrictions{Allergies: []string{"gluten"}},
+			ingredients:  []string{"buckwheat flour", "almond flour", "all-purpose flour", "rice noodles", "soy sauce", "tamari", "gluten-free soy sauce", "malt vinegar", "Worcestershire sauce"},
+			want:         []string{"all-purpose flour: Gluten!", "soy sauce: Gluten!", "malt vinegar: Gluten!", "Worcestershire sauce: Gluten!"},
+		},
+		{
+			name:         "exceptions next to allergens",
+			restrictions: diet.Restrictions{Allergies: []string{"Egg", "Milk", "Fish", "Wheat"}},
+			ingredients:  []string{"eggplant and egg batter", "peanut butter and butter", "fish and shellfish stock", "almond flour and wheat flour", "gluten-free flour"},
+			want:         []string{"eggplant and egg batter: Egg!", "peanut butter and butter: Milk!", "fish and shellfish stock: Fish!", "almond flour and wheat flour: Wheat!"},
+		},
+		{
+			name:         "undeclared allergy matches by name",
+			restrictions: diet.Restrictions{Allergies: []string{"Kiwi"}},
+			ingredients:  []string{"kiwis", "apple"},
+			want:         []string{"kiwis: Kiwi!"},
+		},
+		{
+			name:         "diets and dislikes",
+			restrictions: diet.Restrictions{Diets: []string{"Vegetarian", "Halal"}, Dislikes: []string{"cilantro"}},
+			ingredients:  []string{"chicken thighs", "white wine", "fresh cilantro", "veggie stock"},
+			want:         []string{"chicken thighs: not Vegetarian", "white wine: not Halal", "fresh cilantro: disliked cilantro"},
+		},
+		{
+			name:         "vegan",
+			restrictions: diet.Restrictions{Diets: []string{"vegan"}},
+			ingredients:  []string{"oat milk", "vegan butter", "honey", "tofu", "dairy-free cheese"},
+			want:         []string{"honey: not Vegan"},
+		},
+		{
+			name:         "nothing restricted",
+			restrictions: diet.Restrictions{},
+			ingredients:  []string{"bacon"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, f := range tt.restrictions.Check(tt.ingredients) {
+				s := fmt.Sprintf("%s: %s", f.Ingredient, f.Reason)
+				if f.Allergen {
+					s += "!"
+				}
+				got = append(got, s)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("Check() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrompt(t *testing.T) {
+	r := diet.Restrictions{Allergies: []string{"Peanut"}, Diets: []string{"Keto", "Low-Sodium"}, Dislikes: []string{"olives", "tuna"}}
+	want := ". Please be aware of our food allergy to Peanut, never use an ingredient that contains them" +
+		". Every dish must be Keto, Low-Sodium. Please avoid olives, tuna, we don't like them"
+	if got := r.Prompt(); got != want {
+		t.Errorf("Prompt() = %q, want %q", got, want)
+	}
+	if got := (diet.Restrictions{}).Prompt(); got != "" {
+		t.Errorf("Prompt() without restrictions = %q", got)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	a := diet.Restrictions{Allergies: []string{"Peanut"}, Dislikes: []string{"olives"}}
+	b := diet.Restrictions{Allergies: []string{"peanut", "Egg"}, Diets: []string{"Halal"}}
+	got := a.Merge(b)
+	if fmt.Sprint(got.Allergies) != "[Peanut Egg]" || fmt.Sprint(got.Diets) != "[Halal]" || fmt.Sprint(got.Dislikes) != "[olives]" {
+		t.Errorf("Merge() = %+v", got)
+	}
+}
+
+func TestParseList(t *testing.T) {
+	if got := diet.ParseList(" olives, Mushrooms ,,olives "); fmt.Sprint(got) != "[olives Mushrooms]" {
+		t.Errorf("ParseList() = %q", got)
+	}
+}
@@ -0,0 +1,151 @@
+package docindex
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// ChunkChars keeps a passage well inside the context of embedding
+	// models, which is often 512 tokens.
+	ChunkChars = 1200
+	// OverlapChars repeats the end of a passage at the start of the next one
+	// so a sentence that spans them is found with either.
+	OverlapChars = 200
+)
+
+var paragraphBreak = regexp.MustCompile(`\n[ \t]*\n`)
+
+// Chunk is a passage of a document.
+type Chunk struct {
+	// Line is where the passage starts, counting from 1.
+	Line int
+	Text string
+}
+
+// Split breaks the text into passages of at most maxChars on paragraph
+// breaks, paragraphs that are too long are split on lines and then on
+// words. Each passage starts with the last overlap characters of the one
+// before it.
+func Split(text string, maxChars, overlap int) []Chunk {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	var (
+		chunks  []Chunk
+		current strings.Builder
+		// carried is how much of current was repeated from the passage before
+		carried int
+		// line is where the next piece starts and start where current does
+		line  = 1
+		start = 1
+	)
+	flush := func() {
+		body := current.String()
+		if len(body) > carried {
+			chunks = append(chunks, Chunk{Line: start, Text: strings.TrimSpace(body)})
+		}
+		current.Reset()
+		carried = 0
+		if overlap <= 0 || len(body) <= overlap {
+			return
+		}
+		tail := body[wordStart(body, len(body)-overlap):]
+		if strings.TrimSpace(tail) == "" {
+			return
+		}
+		current.WriteString(tail)
+		carried = len(tail)
+		start = line - strings.Count(tail, "\n") + leadingLines(tail)
+	}
+
+	for _, piece := range pieces(text, maxChars) {
+		if current.Len()+len(piece) > maxChars {
+			if current.Len() > carried {
+				flush()
+			}
+			if current.Len()+len(piece) > maxChars {
+				// the overlap doesn't fit with the piece
+				current.Reset()
+				carried = 0
+			}
+		}
+		if strings.TrimSpace(current.String()) == "" {
+			current.Reset()
+			carried = 0
+			start = line + leadingLines(piece)
+		}
+		current.WriteString(piece)
+		line += strings.Count(piece, "\n")
+	}
+	if strings.TrimSpace(current.String()) != "" {
+		flush()
+	}
+	return chunks
+}
+
+// pieces splits the text into paragraphs no longer than maxChars, each with
+// the line breaks after it so the lines can be counted.
+func pieces(text string, maxChars int) []string {
+	var all []string
+	prev := 0
+	for _, loc := range append(paragraphBreak.FindAllStringIndex(text, -1), []int{len(text), len(text)}) {
+		paragraph := text[prev:loc[1]]
+		prev = loc[1]
+		if paragraph == "" {
+			continue
+		}
+		if len(paragraph) <= maxChars {
+			all = append(all, paragraph)
+			continue
+		}
+		all = append(all, splitLong(paragraph, maxChars)...)
+	}
+	return all
+}
+
+// splitLong splits a long paragraph after line breaks or spaces, a word
+// longer than maxChars is cut between two runes.
+func splitLong(paragraph string, maxChars int) []string {
+	var parts []string
+	for len(paragraph) > maxChars {
+		cut := strings.LastIndex(paragraph[:maxChars], "\n")
+		if cut <= 0 {
+			cut = strings.LastIndex(paragraph[:maxChars], " ")
+		}
+		if cut <= 0 {
+			cut = runeStart(paragraph, maxChars) - 1
+		}
+		parts = append(parts, paragraph[:cut+1])
+		paragraph = paragraph[cut+1:]
+	}
+	return append(parts, paragraph)
+}
+
+// wordStart moves i forward to the start of a word, or to the start of a
+// rune when the text has no more spaces.
+func wordStart(s string, i int) int {
+	if j := strings.IndexAny(s[i:], " \n\t"); j >= 0 {
+		return i + j + 1
+	}
+	for i < len(s) && !utf8.RuneStart(s[i]) {
+		i++
+	}
+	return i
+}
+
+// runeStart moves i back to the start of a rune, or past the first rune when
+// it is longer than i.
+func runeStart(s string, i int) int {
+	for i > 0 && !utf8.RuneStart(s[i]) {
+		i--
+	}
+	if i == 0 {
+		_, size := utf8.DecodeRuneInString(s)
+		return size
+	}
+	return i
+}
+
+func leadingLines(s string) int {
+	return strings.Count(s[:len(s)-len(strings.TrimLeft(s, " \t\n"))], "\n")
+}
@@ -0,0 +1,197 @@
+package docindex_test
+
+import (
+	"math"
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"unicode/utf8"
+
+	"github.com/bahelit/ctrl_plus_revise/pkg/docindex"
+)
+
+func TestScan(t *testing.T) {
+	fSys := fstest.MapFS{
+		"README.md":                 {Data: []byte("# Hello")},
+		"docs/setup.txt":            {Data: []byte("Install it")},
+		"docs/manual.PDF":           {Data: []byte("%PDF")},
+		"docs/logo.png":             {Data: []byte("\x89PNG")},
+		"docs/empty.md":             {Data: nil},
+		"docs/huge.txt":             {Data: make([]byte, docindex.MaxFileSize+1)},
+		"src/main.go":               {Data: []byte("package main")},
+		".git/config":               {Data: []byte("[core]")},
+		".hidden.md":                {Data: []byte("secret")},
+		"node_modules/lib/index.js": {Data: []byte("module.exports = {}")},
+	}
+	files, errs := docindex.Scan(fSys)
+	if len(errs) > 0 {
+		t.Fatalf("Scan() errors = %v", errs)
+	}
+	var got []string
+	for _, f := range files {
+		got = append(got, f.Path)
+	}
+	want := []string{"README.md", "docs/manual.PDF", "docs/setup.txt", "src/main.go"}
+	if !slices.Equal(got, want) {
+		t.Errorf("Scan() = %v, want %v", got, want)
+	}
+}
+
+func TestOverlaps(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"/home/me/docs", "/home/me/docs", true},
+		{"/home/me/docs", "/home/me/docs/notes", true},
+		{"/home/me/docs/notes/", "/home/me/docs", true},
+		{"/home/me/docs", "/home/me/docs2", false},
+		{"/home/me/docs", "/home/me/work", false},
+		{"/home/me/..docs", "/home/me", true},
+	}
+	for _, tt := range tests {
+		if got := docindex.Overlaps(tt.a, tt.b); got != tt.want {
+			t.Errorf("Overlaps(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestSplit(t *testing.T) {
+	text := "Title\n\nFirst paragraph is here.\n\n\nSecond paragraph\nspans two lines.\n\nThird."
+	tests := []struct {
+		name     string
+		maxChars int
+		overlap  int
+		want     []docindex.Chunk
+	}{
+		{
+			name:     "fits",
+			maxChars: 200,
+			want:     []docindex.Chunk{{Line: 1, Text: strings.TrimSpace(text)}},
+		},
+		{
+			name:     "paragraphs",
+			maxChars: 40,
+			want: []docindex.Chunk{
+				{Line: 1, Text: "Title\n\nFirst paragraph is here."},
+				{Line: 6, Text: "Second paragraph\nspans two lines."},
+				{Line: 9, Text: "Third."},
+			},
+		},
+		{
+			name:     "overlap",
+			maxChars: 50,
+			overlap:  12,
+			want: []docindex.Chunk{
+				{Line: 1, Text: "Title\n\nFirst paragraph is here."},
+				{Line: 3, Text: "is here.\n\n\nSecond paragraph\nspans two lines."},
+				{Line: 7, Text: "lines.\n\nThird."},
+			},
+		},
+		{
+			name:     "long lines",
+			maxChars: 12,
+			want: []docindex.Chunk{
+				{Line: 1, Text: "Title"},
+				{Line: 3, Text: "First"},
+				{Line: 3, Text: "paragraph"},
+				{Line: 3, Text: "is here."},
+				{Line: 6, Text: "Second"},
+				{Line: 6, Text: "paragraph"},
+				{Line: 7, Text: "spans two"},
+				{Line: 7, Text: "lines."},
+				{Line: 9, Text: "Third."},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := docindex.Split(text, tt.maxChars, tt.overlap)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Split() =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+	if got := docindex.Split(" \n\n ", 100, 10); len(got) != 0 {
+		t.Errorf("Split() of blank text = %q", got)
+	}
+}
+
+func TestSplitRunes(t *testing.T) {
+	texts := []string{
+		strings.Repeat("日本語", 20),
+		strings.Repeat("ü", 35) + " " + strings.Repeat("€", 30),
+	}
+	for _, text := range texts {
+		for _, maxChars := range []int{2, 7, 10, 31} {
+			for _, chunk := range docindex.Split(text, maxChars, 5) {
+				if !utf8.ValidString(chunk.Text) {
+					t.Errorf("Split(%d) cut a rune: %q", maxChars, chunk.Text)
+				}
+			}
+		}
+	}
+}
+
+func TestVector(t *testing.T) {
+	v := docindex.FromFloat64([]float64{0.5, -1.25, 3})
+	decoded, err := docindex.DecodeVector(v.Encode())
+	if err != nil || !slices.Equal(decoded, v) {
+		t.Errorf("DecodeVector(Encode()) = %v, %v, want %v", decoded, err, v)
+	}
+	if _, err = docindex.DecodeVector([]byte{1, 2, 3}); err == nil {
+		t.Error("DecodeVector() of 3 bytes didn't fail")
+	}
+
+	tests := []struct {
+		name string
+		a, b docindex.Vector
+		want float64
+	}{
+		{"same", docindex.Vector{1, 2}, docindex.Vector{2, 4}, 1},
+		{"opposite", docindex.Vector{1, 0}, docindex.Vector{-1, 0}, -1},
+		{"orthogonal", docindex.Vector{1, 0}, docindex.Vector{0, 3}, 0},
+		{"other model", docindex.Vector{1, 0}, docindex.Vector{1, 0, 0}, 0},
+		{"zero", docindex.Vector{0, 0}, docindex.Vector{1, 0}, 0},
+	}
+	for _, tt := range tests {
+		if got := docindex.Cosine(tt.a, tt.b); math.Abs(got-tt.want) > 1e-6 {
+			t.Errorf("Cosine(%s) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSearch(t *testing.T) {
+	passages := []docindex.Passage{
+		{Path: "a.md", Line: 1, Text: "far", Vector: docindex.Vector{0, 1}},
+		{Path: "b.md", Line: 4, Text: "close", Vector: docindex.Vector{1, 0.1}},
+		{Path: "c.md", Line: 9, Text: "closer", Vector: docindex.Vector{1, 0}},
+		{Path: "d.md", Line: 2, Text: "near", Vector: docindex.Vector{1, 1}},
+	}
+	matches := docindex.Search(docindex.Vector{1, 0}, passages, 2, 0.1)
+	var got []string
+	for _, m := range matches {
+		got = append(got, m.Text)
+	}
+	if want := []string{"closer", "close"}; !slices.Equal(got, want) {
+		t.Errorf("Search() = %v, want %v", got, want)
+	}
+	if got := docindex.Search(docindex.Vector{1, 0}, passages, 10, 0.5); len(got) != 3 {
+		t.Errorf("Search() with a minimum score = %d matches, want 3", len(got))
+	}
+
+	prompt := docindex.Prompt("What is close?", matches)
+	for _, want := range []string{"SOURCE [1] c.md:9\ncloser", "SOURCE [2] b.md:4\nclose", "QUESTION: What is close?"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("Prompt() doesn't contain %q:\n%s", want, prompt)
+		}
+	}
+	sources := docindex.Sources(matches)
+	if want := "- [1] `c.md:9` (100% match)\n- [2] `b.md:4` (100% match)\n"; !strings.HasSuffix(sources, want) {
+		t.Errorf("Sources() = %q, want suffix %q", sources, want)
+	}
+	if docindex.Sources(nil) != "" {
+		t.Error("Sources() without matches isn't empty")
+	}
+}
@@ -0,0 +1,96 @@
+// Package docindex finds the documents in a folder, splits them into
+// passages and finds the passages closest to a question by their embedding
+// vectors, so the answer can be grounded in them and cite its sources.
+package docindex
+
+import (
+	"errors"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// MaxFileSize skips logs, dumps and generated files.
+const MaxFileSize = 2 << 20
+
+// extensions are the files with text worth indexing.
+var extensions = map[string]bool{
+	".md": true, ".markdown": true, ".txt": true, ".rst": true, ".adoc": true, ".org": true,
+	".html": true, ".htm": true, ".csv": true, ".json": true, ".yaml": true, ".yml": true, ".toml": true,
+	".pdf": true,
+	".go":  true, ".py": true, ".js": true, ".ts": true, ".tsx": true, ".jsx": true, ".java": true,
+	".kt": true, ".rs": true, ".c": true, ".h": true, ".cpp": true, ".hpp": true, ".cs": true,
+	".rb": true, ".php": true, ".swift": true, ".sh": true, ".sql": true, ".css": true,
+}
+
+// skipDirs are folders of dependencies and tools, not documents.
+var skipDirs = map[string]bool{
+	"node_modules": true, "vendor": true, "__pycache__": true, "target": true, "dist": true, "build": true,
+}
+
+// File is a document found in a folder.
+type File struct {
+	// Path is slash separated and relative to the folder.
+	Path    string
+	Size    int64
+	ModTime time.Time
+}
+
+// Supported reports whether the file is indexed.
+func Supported(name string) bool {
+	return extensions[strings.ToLower(path.Ext(name))]
+}
+
+// Scan walks the folder for documents, hidden files and folders are
+// skipped. Files that can't be read are reported and skipped.
+func Scan(fSys fs.FS) (files []File, errs []error) {
+	err := fs.WalkDir(fSys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			errs = append(errs, err)
+			if d != nil && d.IsDir() && p != "." {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		hidden := p != "." && strings.HasPrefix(d.Name(), ".")
+		if d.IsDir() {
+			if hidden || skipDirs[d.Name()] {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if hidden || !Supported(p) {
+			return nil
+		}
+
+		fileInfo, infoErr := d.Info()
+		if infoErr != nil {
+			if !errors.Is(infoErr, fs.ErrNotExist) {
+				errs = append(errs, infoErr)
+			}
+			return nil
+		}
+		if fileInfo.Size() == 0 || fileInfo.Size() > MaxFileSize {
+			return nil
+		}
+		files = append(files, File{Path: p, Size: fileInfo.Size(), ModTime: fileInfo.ModTime()})
+		return nil
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+	return files, errs
+}
+
+// Overlaps reports whether one folder is inside the other or they are the
+// same, the documents of both would be indexed twice.
+func Overlaps(a, b string) bool {
+	inside := func(parent, child string) bool {
+		rel, err := filepath.Rel(parent, child)
+		return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+	}
+	a, b = filepath.Clean(a), filepath.Clean(b)
+	return inside(a, b) || inside(b, a)
+}
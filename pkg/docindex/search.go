@@ -0,0 +1,75 @@
+package docindex
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// DefaultTopK is how many passages are sent with a question.
+const DefaultTopK = 5
+
+// Passage is an indexed chunk of a document.
+type Passage struct {
+	// Path is the full path of the document.
+	Path   string
+	Line   int
+	Text   string
+	Vector Vector
+}
+
+// Source names the passage, e.g. "docs/setup.md:12".
+func (p Passage) Source() string {
+	return fmt.Sprintf("%s:%d", p.Path, p.Line)
+}
+
+// Match is a passage and how close it is to the question.
+type Match struct {
+	Passage
+	Score float64
+}
+
+// Search returns the k passages closest to the question, the closest first.
+// Passages scoring below minScore aren't returned.
+func Search(question Vector, passages []Passage, k int, minScore float64) []Match {
+	var matches []Match
+	for _, p := range passages {
+		if score := Cosine(question, p.Vector); score >= minScore {
+			matches = append(matches, Match{Passage: p, Score: score})
+		}
+	}
+	slices.SortStableFunc(matches, func(a, b Match) int { return cmp.Compare(b.Score, a.Score) })
+	if len(matches) > k {
+		matches = matches[:k]
+	}
+	return matches
+}
+
+// Prompt asks the question with the passages as numbered sources, the
+// answer cites them as [1], [2] and so on.
+func Prompt(question string, matches []Match) string {
+	var b strings.Builder
+	b.WriteString("Answer the question using only the following sources. " +
+		"Cite the sources you use with their number in square brackets, e.g. [1]. " +
+		"If the sources don't contain the answer, say that you don't know instead of guessing.\n\n")
+	for i, m := range matches {
+		fmt.Fprintf(&b, "SOURCE [%d] %s\n%s\n\n", i+1, m.Source(), m.Text)
+	}
+	b.WriteString("QUESTION: " + question)
+	return b.String()
+}
+
+// Sources lists the passages in Markdown under the answer, in the numbering
+// the answer cites them with.
+func Sources(matches []Match) string {
+	if len(matches) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("**Sources**\n\n")
+	for i, m := range matches {
+		fmt.Fprintf(&b, "- [%d] `%s` (%.0f%% match)\n", i+1, m.Source(), m.Score*100)
+	}
+	return b.String()
+}
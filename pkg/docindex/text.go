@@ -0,0 +1,51 @@
+package docindex
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrNoPDFTool is returned for PDF files when pdftotext from poppler isn't
+// installed.
+var ErrNoPDFTool = errors.New("pdftotext is needed to read PDF files")
+
+// ErrBinary is returned for files that aren't text.
+var ErrBinary = errors.New("not a text file")
+
+// ReadText returns the text of the file, PDF files are converted with
+// pdftotext.
+func ReadText(name string) (string, error) {
+	if strings.EqualFold(filepath.Ext(name), ".pdf") {
+		return readPDF(name)
+	}
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return "", err
+	}
+	if bytes.IndexByte(data, 0) >= 0 || !utf8.Valid(data) {
+		return "", fmt.Errorf("%s: %w", name, ErrBinary)
+	}
+	return string(data), nil
+}
+
+func readPDF(name string) (string, error) {
+	tool, err := exec.LookPath("pdftotext")
+	if err != nil {
+		return "", ErrNoPDFTool
+	}
+	var stderr bytes.Buffer
+	cmd := exec.Command(tool, "-layout", "-enc", "UTF-8", name, "-")
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("pdftotext %s: %w: %s", name, err, strings.TrimSpace(stderr.String()))
+	}
+	// pdftotext separates pages with form feeds
+	return strings.ReplaceAll(string(out), "\f", "\n\n"), nil
+}
@@ -0,0 +1,59 @@
+package docindex
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+)
+
+// Vector is the embedding of a passage or a question.
+type Vector []float32
+
+// FromFloat64 converts the embedding returned by the backend, float32 halves
+// the size of the index without changing the ranking.
+func FromFloat64(values []float64) Vector {
+	v := make(Vector, len(values))
+	for i, f := range values {
+		v[i] = float32(f)
+	}
+	return v
+}
+
+// Encode writes the vector as little-endian float32s for a BLOB column.
+func (v Vector) Encode() []byte {
+	data := make([]byte, 4*len(v))
+	for i, f := range v {
+		binary.LittleEndian.PutUint32(data[4*i:], math.Float32bits(f))
+	}
+	return data
+}
+
+// DecodeVector reads a vector written by Encode.
+func DecodeVector(data []byte) (Vector, error) {
+	if len(data)%4 != 0 {
+		return nil, errors.New("vector data is not a multiple of 4 bytes")
+	}
+	v := make(Vector, len(data)/4)
+	for i := range v {
+		v[i] = math.Float32frombits(binary.LittleEndian.Uint32(data[4*i:]))
+	}
+	return v, nil
+}
+
+// Cosine returns the cosine similarity of the vectors, from -1 to 1, it is
+// 0 for vectors of different lengths such as those of another model.
+func Cosine(a, b Vector) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / math.Sqrt(normA*normB)
+}
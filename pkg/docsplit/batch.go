@@ -0,0 +1,181 @@
+package docsplit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bahelit/ctrl_plus_revise/pkg/mask"
+)
+
+// Transform processes one unit of text, instructions must be added to the
+// prompt so the model keeps the placeholder markers. Cancelling ctx stops
+// the request in flight.
+type Transform func(ctx context.Context, text, instructions string) (string, error)
+
+// Job processes a file and writes the result next to it.
+type Job struct {
+	Path string
+	// Suffix is added before the extension of the output file, e.g. "es"
+	// writes README.es.md next to README.md.
+	Suffix string
+	// Settings describe how the units are processed, e.g. the prompt, the
+	// language and the model. Finished units saved with other settings are
+	// not resumed.
+	Settings string
+	MaxChars int
+	// Retries is how often a unit is tried again when the response lost a
+	// placeholder.
+	Retries   int
+	Transform Transform
+	// Progress is called after every unit.
+	Progress func(done, total int)
+}
+
+// OutputPath returns where the job writes its result.
+func (j Job) OutputPath() string {
+	ext := filepath.Ext(j.Path)
+	return strings.TrimSuffix(j.Path, ext) + "." + j.Suffix + ext
+}
+
+// checkpointPath is where finished units are saved so a failed job resumes
+// where it stopped.
+func (j Job) checkpointPath() string {
+	return j.OutputPath() + ".partial"
+}
+
+type checkpoint struct {
+	// Source is the hash of the file and the settings of the job.
+	Source  string         `json:"source"`
+	Results map[int]string `json:"results"`
+}
+
+// Run processes the file, if it fails the finished units are kept and running
+// the same job again continues with the remaining units.
+func (j Job) Run(ctx context.Context) error {
+	format, err := FormatOf(j.Path)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(j.Path)
+	if err != nil {
+		return err
+	}
+	doc, err := Parse(format, data, j.MaxChars)
+	if err != nil {
+		return fmt.Errorf("%s: %w", filepath.Base(j.Path), err)
+	}
+
+	sum := sha256.Sum256(data)
+	saved := j.loadCheckpoint(hex.EncodeToString(sum[:]) + " " + j.Settings)
+	units := doc.Units()
+	results := make([]string, len(units))
+	done := 0
+	for i := range units {
+		if result, ok := saved.Results[i]; ok {
+			results[i] = result
+			done++
+		}
+	}
+	if done > 0 {
+		slog.Info("Resuming batch job", "file", j.Path, "done", done, "total", len(units))
+	}
+	j.progress(done, len(units))
+
+	for i, unit := range units {
+		if _, ok := saved.Results[i]; ok {
+			continue
+		}
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+		results[i], err = j.transform(ctx, format, unit)
+		if err != nil {
+			return fmt.Errorf("%s, part %d of %d: %w", filepath.Base(j.Path), i+1, len(units), err)
+		}
+		saved.Results[i] = results[i]
+		if err = j.saveCheckpoint(saved); err != nil {
+			slog.Warn("Unable to save batch progress", "file", j.Path, "error", err)
+		}
+		done++
+		j.progress(done, len(units))
+	}
+
+	out, err := doc.Render(results)
+	if err != nil {
+		return err
+	}
+	if err = os.WriteFile(j.OutputPath(), out, 0644); err != nil {
+		return err
+	}
+	_ = os.Remove(j.checkpointPath())
+	return nil
+}
+
+// transform runs a unit through the model, placeholders, and the code and
+// links of Markdown, are masked and the surrounding whitespace is kept.
+func (j Job) transform(ctx context.Context, format Format, unit string) (string, error) {
+	body := strings.TrimSpace(unit)
+	if body == "" {
+		return unit, nil
+	}
+	start := strings.Index(unit, body)
+	lead, trail := unit[:start], unit[start+len(body):]
+
+	masked := mask.Placeholders(body)
+	if format == Markdown {
+		masked = mask.Markdown(body)
+	}
+	var err error
+	for attempt := 0; attempt <= j.Retries; attempt++ {
+		var response, restored string
+		response, err = j.Transform(ctx, masked.Text, masked.Instructions())
+		if err != nil {
+			return "", err
+		}
+		restored, err = masked.Restore(strings.TrimSpace(response))
+		if err == nil {
+			return lead + restored + trail, nil
+		}
+		if !errors.Is(err, mask.ErrLostToken) {
+			return "", err
+		}
+		slog.Warn("Response lost a placeholder, trying again", "attempt", attempt+1, "error", err)
+	}
+	return "", err
+}
+
+func (j Job) progress(done, total int) {
+	if j.Progress != nil {
+		j.Progress(done, total)
+	}
+}
+
+func (j Job) loadCheckpoint(source string) checkpoint {
+	fresh := checkpoint{Source: source, Results: map[int]string{}}
+	data, err := os.ReadFile(j.checkpointPath())
+	if err != nil {
+		return fresh
+	}
+	var saved checkpoint
+	if err = json.Unmarshal(data, &saved); err != nil || saved.Source != source || saved.Results == nil {
+		// The file or the settings changed since the job failed, start over
+		return fresh
+	}
+	return saved
+}
+
+func (j Job) saveCheckpoint(c checkpoint) error {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(j.checkpointPath(), data, 0644)
+}
@@ -0,0 +1,140 @@
+// Package docsplit splits documents into pieces small enough for an AI model
+// and puts the processed pieces back together without touching the structure
+// around them: the keys of a JSON bundle, the timings of subtitles, the code
+// blocks of Markdown and so on.
+package docsplit
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Format is a supported file format.
+type Format string
+
+const (
+	Text     Format = "txt"
+	Markdown Format = "md"
+	PO       Format = "po"
+	JSON     Format = "json"
+	SRT      Format = "srt"
+)
+
+// DefaultMaxChars keeps chunks well inside the context of small models.
+const DefaultMaxChars = 2000
+
+var ErrUnsupported = errors.New("unsupported file format")
+
+// FormatOf returns the format of the file from its extension.
+func FormatOf(path string) (Format, error) {
+	switch strings.ToLower(strings.TrimPrefix(filepath.Ext(path), ".")) {
+	case "txt", "text":
+		return Text, nil
+	case "md", "markdown":
+		return Markdown, nil
+	case "po", "pot":
+		return PO, nil
+	case "json":
+		return JSON, nil
+	case "srt":
+		return SRT, nil
+	}
+	return "", fmt.Errorf("%w: %s", ErrUnsupported, filepath.Base(path))
+}
+
+// part is a piece of the document, either kept as it is or the result of a unit.
+type part struct {
+	literal string
+	unit    int
+	// encode escapes the result of the unit for the file format
+	encode func(string) string
+}
+
+// Document is a parsed file.
+type Document struct {
+	Format Format
+	units  []string
+	parts  []part
+	crlf   bool
+}
+
+func (d *Document) keep(s string) {
+	if s == "" {
+		return
+	}
+	d.parts = append(d.parts, part{literal: s, unit: -1})
+}
+
+func (d *Document) add(text string, encode func(string) string) {
+	d.units = append(d.units, text)
+	d.parts = append(d.parts, part{unit: len(d.units) - 1, encode: encode})
+}
+
+// addUnit reuses a unit already added, e.g. for the plural forms of a PO entry.
+func (d *Document) addUnit(unit int, encode func(string) string) {
+	d.parts = append(d.parts, part{unit: unit, encode: encode})
+}
+
+// Units returns the pieces of text to process.
+func (d *Document) Units() []string {
+	return d.units
+}
+
+// Render puts the document back together with results in place of the units.
+func (d *Document) Render(results []string) ([]byte, error) {
+	if len(results) != len(d.units) {
+		return nil, fmt.Errorf("expected %d results, received %d", len(d.units), len(results))
+	}
+	var b strings.Builder
+	for _, p := range d.parts {
+		if p.unit < 0 {
+			b.WriteString(p.literal)
+			continue
+		}
+		text := results[p.unit]
+		if p.encode != nil {
+			text = p.encode(text)
+		}
+		b.WriteString(text)
+	}
+	out := b.String()
+	if d.crlf {
+		out = strings.ReplaceAll(out, "\n", "\r\n")
+	}
+	return []byte(out), nil
+}
+
+// Parse splits the file, maxChars is the longest chunk of running text sent
+// to the model at once.
+func Parse(format Format, data []byte, maxChars int) (*Document, error) {
+	if maxChars <= 0 {
+		maxChars = DefaultMaxChars
+	}
+	text := string(data)
+	d := &Document{Format: format, crlf: strings.Contains(text, "\r\n")}
+	if d.crlf {
+		text = strings.ReplaceAll(text, "\r\n", "\n")
+	}
+
+	var err error
+	switch format {
+	case Text:
+		d.splitProse(text, maxChars)
+	case Markdown:
+		d.splitMarkdown(text, maxChars)
+	case PO:
+		err = d.splitPO(text)
+	case JSON:
+		err = d.splitJSON(text)
+	case SRT:
+		d.splitSRT(text)
+	default:
+		err = fmt.Errorf("%w: %s", ErrUnsupported, format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return d, nil
+}
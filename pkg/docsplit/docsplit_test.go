@@ -0,0 +1,244 @@
+package docsplit_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bahelit/ctrl_plus_revise/pkg/docsplit"
+)
+
+func parseFile(t *testing.T, name string, maxChars int) (*docsplit.Document, []byte) {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	format, err := docsplit.FormatOf(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := docsplit.Parse(format, data, maxChars)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return doc, data
+}
+
+var unitsTable = []struct {
+	File  string
+	Units []string
+}{
+	{"notes.txt", []string{"Hello {name}, welcome back.\nYou have %d messages.\n\n\nSecond paragraph here."}},
+	{"guide.md", []string{"# Getting started\n\nRun the app and press the shortcut.", "Read the [docs](https://example.com) for more."}},
+	{"en.json", []string{"Settings", "Open {file}", "Cut", "Copy", "Paste \"now\""}},
+	{"movie.srt", []string{"Hello there.\nHow are you?", "Fine, thanks."}},
+	{"messages.po", []string{"Hello %s", "One file", "%d files", "Long message on two lines"}},
+}
+
+func Test_Units(t *testing.T) {
+	for _, v := range unitsTable {
+		doc, _ := parseFile(t, v.File, 0)
+		if !reflect.DeepEqual(doc.Units(), v.Units) {
+			t.Errorf("%s: expected units %q, received %q", v.File, v.Units, doc.Units())
+		}
+	}
+}
+
+func Test_RenderKeepsStructure(t *testing.T) {
+	for _, name := range []string{"notes.txt", "guide.md", "en.json", "movie.srt"} {
+		doc, data := parseFile(t, name, 0)
+		out, err := doc.Render(doc.Units())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(out) != string(data) {
+			t.Errorf("%s: expected the file back unchanged, received:\n%s", name, out)
+		}
+	}
+}
+
+func Test_RenderPO(t *testing.T) {
+	doc, _ := parseFile(t, "messages.po", 0)
+	out, err := doc.Render([]string{"Hola %s", "Un archivo", "%d archivos", "Mensaje \"largo\""})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"#: main.go:10\nmsgid \"Hello %s\"\nmsgstr \"Hola %s\"\n",
+		"msgstr \"Ya hecho\"",
+		"msgstr[0] \"Un archivo\"\nmsgstr[1] \"%d archivos\"\n",
+		"\"on two lines\"\nmsgstr \"Mensaje \\\"largo\\\"\"\n",
+		"\"Content-Type: text/plain; charset=UTF-8\\n\"\n",
+	} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("Expected %q in:\n%s", want, out)
+		}
+	}
+}
+
+func Test_ChunkSize(t *testing.T) {
+	doc, _ := parseFile(t, "notes.txt", 30)
+	want := []string{"Hello {name}, welcome back.", "You have %d messages.", "Second paragraph here."}
+	if !reflect.DeepEqual(doc.Units(), want) {
+		t.Errorf("Expected units %q, received %q", want, doc.Units())
+	}
+	for _, u := range doc.Units() {
+		if len(u) > 30 {
+			t.Errorf("Unit is longer than the limit: %q", u)
+		}
+	}
+}
+
+func Test_FormatOf(t *testing.T) {
+	if _, err := docsplit.FormatOf("photo.png"); !errors.Is(err, docsplit.ErrUnsupported) {
+		t.Errorf("Expected ErrUnsupported, received %v", err)
+	}
+	if f, err := docsplit.FormatOf("README.MD"); err != nil || f != docsplit.Markdown {
+		t.Errorf("Expected Markdown, received %s %v", f, err)
+	}
+}
+
+func copyTestFile(t *testing.T, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), name)
+	if err = os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func Test_JobResumes(t *testing.T) {
+	path := copyTestFile(t, "en.json")
+	calls := 0
+	failing := docsplit.Job{
+		Path:     path,
+		Suffix:   "es",
+		Settings: "Spanish llama3",
+		Transform: func(_ context.Context, text, _ string) (string, error) {
+			calls++
+			if calls == 3 {
+				return "", errors.New("ollama went away")
+			}
+			return strings.ToUpper(text), nil
+		},
+	}
+	if err := failing.Run(context.Background()); err == nil {
+		t.Fatal("Expected the job to fail")
+	}
+	if _, err := os.Stat(failing.OutputPath()); !os.IsNotExist(err) {
+		t.Fatal("Expected no output for a failed job")
+	}
+
+	var fresh int
+	other := failing
+	other.Settings = "Spanish mistral"
+	other.Transform = func(_ context.Context, text, _ string) (string, error) {
+		fresh++
+		return "", errors.New("ollama went away")
+	}
+	if err := other.Run(context.Background()); err == nil || fresh != 1 {
+		t.Fatalf("Expected other settings to start over, transformed %d units", fresh)
+	}
+
+	var resumed []string
+	var progress []int
+	job := failing
+	job.Transform = func(_ context.Context, text, _ string) (string, error) {
+		resumed = append(resumed, text)
+		return strings.ToUpper(text), nil
+	}
+	job.Progress = func(done, total int) { progress = append(progress, done) }
+	if err := job.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(resumed) != 3 || progress[0] != 2 || progress[len(progress)-1] != 5 {
+		t.Errorf("Expected the job to resume at the third unit, transformed %q with progress %v", resumed, progress)
+	}
+
+	out, err := os.ReadFile(filepath.Join(filepath.Dir(path), "en.es.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(out), `"open": "OPEN {file}"`) || !strings.Contains(string(out), `"count": 3`) {
+		t.Errorf("Unexpected output:\n%s", out)
+	}
+	if _, err = os.Stat(job.OutputPath() + ".partial"); !os.IsNotExist(err) {
+		t.Error("Expected the checkpoint to be removed")
+	}
+}
+
+func Test_JobRetriesLostPlaceholder(t *testing.T) {
+	path := copyTestFile(t, "notes.txt")
+	attempts := 0
+	job := docsplit.Job{
+		Path:    path,
+		Suffix:  "revised",
+		Retries: 1,
+		Transform: func(_ context.Context, text, instructions string) (string, error) {
+			attempts++
+			if instructions == "" {
+				t.Error("Expected instructions about the markers")
+			}
+			if attempts == 1 {
+				return "Hello, welcome back.", nil
+			}
+			return text, nil
+		},
+	}
+	if err := job.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	out, _ := os.ReadFile(job.OutputPath())
+	if attempts != 2 || !strings.Contains(string(out), "Hello {name}") {
+		t.Errorf("Expected a second attempt to keep the placeholder, attempts %d, output %q", attempts, out)
+	}
+}
+
+func Test_JobMasksMarkdown(t *testing.T) {
+	path := copyTestFile(t, "guide.md")
+	job := docsplit.Job{
+		Path:   path,
+		Suffix: "fr",
+		Transform: func(_ context.Context, text, _ string) (string, error) {
+			if strings.Contains(text, "https://") {
+				t.Errorf("Expected the link to be masked in %q", text)
+			}
+			return text, nil
+		},
+	}
+	if err := job.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	out, _ := os.ReadFile(job.OutputPath())
+	if !strings.Contains(string(out), "[docs](https://example.com)") {
+		t.Errorf("Expected the link back in the output:\n%s", out)
+	}
+}
+
+func Test_JobStops(t *testing.T) {
+	path := copyTestFile(t, "en.json")
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	job := docsplit.Job{
+		Path:   path,
+		Suffix: "es",
+		Transform: func(ctx context.Context, text, _ string) (string, error) {
+			calls++
+			cancel()
+			<-ctx.Done()
+			return "", ctx.Err()
+		},
+	}
+	if err := job.Run(ctx); !errors.Is(err, context.Canceled) || calls != 1 {
+		t.Errorf("Expected the job to stop during the first unit, error %v after %d units", err, calls)
+	}
+}
@@ -0,0 +1,72 @@
+package docsplit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// splitJSON makes a unit of every string value of an i18n bundle, keys,
+// numbers and the formatting of the file are kept as they are.
+func (d *Document) splitJSON(text string) error {
+	if !json.Valid([]byte(text)) {
+		return fmt.Errorf("invalid JSON")
+	}
+
+	// containers holds the open objects and arrays, a string is a key when it
+	// starts an object member
+	var containers []byte
+	expectKey := false
+	last := 0
+	for i := 0; i < len(text); i++ {
+		switch text[i] {
+		case '{':
+			containers = append(containers, '{')
+			expectKey = true
+		case '[':
+			containers = append(containers, '[')
+		case '}', ']':
+			containers = containers[:len(containers)-1]
+			expectKey = false
+		case ':':
+			expectKey = false
+		case ',':
+			expectKey = containers[len(containers)-1] == '{'
+		case '"':
+			end := stringEnd(text, i)
+			if !expectKey {
+				var value string
+				if err := json.Unmarshal([]byte(text[i:end]), &value); err != nil {
+					return err
+				}
+				d.keep(text[last:i])
+				d.add(value, encodeJSONString)
+				last = end
+			}
+			i = end - 1
+		}
+	}
+	d.keep(text[last:])
+	return nil
+}
+
+// stringEnd returns the index after the closing quote of the string starting at i.
+func stringEnd(text string, i int) int {
+	for j := i + 1; j < len(text); j++ {
+		switch text[j] {
+		case '\\':
+			j++
+		case '"':
+			return j + 1
+		}
+	}
+	return len(text)
+}
+
+func encodeJSONString(s string) string {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(s)
+	return string(bytes.TrimRight(b.Bytes(), "\n"))
+}
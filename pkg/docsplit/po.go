@@ -0,0 +1,112 @@
+package docsplit
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// poEntry is a message of a gettext PO file.
+type poEntry struct {
+	lines []string
+	// field is the keyword each line belongs to, continuation lines included
+	field    []string
+	msgid    string
+	plural   string
+	hasMsgid bool
+	// translated is true when any msgstr already has text
+	translated bool
+}
+
+// splitPO makes units of the messages that have no translation yet, messages
+// that are already translated and the header are kept as they are.
+func (d *Document) splitPO(text string) error {
+	blocks := strings.SplitAfter(text, "\n\n")
+	for _, block := range blocks {
+		entry, err := parsePOEntry(block)
+		if err != nil {
+			return err
+		}
+		if !entry.hasMsgid || entry.msgid == "" || entry.translated {
+			d.keep(block)
+			continue
+		}
+		d.renderPOEntry(entry)
+	}
+	return nil
+}
+
+func parsePOEntry(block string) (poEntry, error) {
+	var e poEntry
+	current := ""
+	for _, line := range strings.SplitAfter(block, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "" || strings.HasPrefix(trimmed, "#"):
+			current = ""
+		case strings.HasPrefix(trimmed, `"`):
+			// continuation of the current field
+		default:
+			current, _, _ = strings.Cut(trimmed, " ")
+		}
+		e.lines = append(e.lines, line)
+		e.field = append(e.field, current)
+		if current == "" {
+			continue
+		}
+
+		value := trimmed
+		if !strings.HasPrefix(value, `"`) {
+			_, value, _ = strings.Cut(trimmed, " ")
+		}
+		s, err := strconv.Unquote(strings.TrimSpace(value))
+		if err != nil {
+			return e, fmt.Errorf("invalid PO string %q: %w", trimmed, err)
+		}
+		switch {
+		case current == "msgid":
+			e.hasMsgid = true
+			e.msgid += s
+		case current == "msgid_plural":
+			e.plural += s
+		case strings.HasPrefix(current, "msgstr"):
+			if s != "" {
+				e.translated = true
+			}
+		}
+	}
+	return e, nil
+}
+
+// renderPOEntry keeps every line but the msgstr ones, which are replaced by
+// the translated msgid, or the translated msgid_plural for plural forms.
+func (d *Document) renderPOEntry(e poEntry) {
+	msgid := len(d.units)
+	d.units = append(d.units, e.msgid)
+	plural := -1
+	if e.plural != "" {
+		plural = len(d.units)
+		d.units = append(d.units, e.plural)
+	}
+
+	for i, line := range e.lines {
+		field := e.field[i]
+		if !strings.HasPrefix(field, "msgstr") {
+			d.keep(line)
+			continue
+		}
+		if strings.HasPrefix(strings.TrimSpace(line), `"`) {
+			// The translation is written on one line
+			continue
+		}
+		d.keep(field + " ")
+		unit := msgid
+		if plural >= 0 && field != "msgstr[0]" {
+			unit = plural
+		}
+		d.addUnit(unit, strconv.Quote)
+		if strings.HasSuffix(line, "\n") {
+			d.keep("\n")
+		}
+	}
+}
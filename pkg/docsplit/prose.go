@@ -0,0 +1,121 @@
+package docsplit
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	paragraphBreak = regexp.MustCompile(`\n[ \t]*\n\s*`)
+	sentenceEnd    = regexp.MustCompile(`[.!?。！？]["')\]]*\s+`)
+	fence          = regexp.MustCompile("^[ \t]*(```|~~~)")
+)
+
+// splitProse groups paragraphs into chunks of at most maxChars, the blank
+// lines between chunks are kept as they are.
+func (d *Document) splitProse(text string, maxChars int) {
+	body := strings.TrimLeft(text, " \t\n")
+	d.keep(text[:len(text)-len(body)])
+
+	var chunk strings.Builder
+	flush := func() {
+		d.addTrimmed(chunk.String())
+		chunk.Reset()
+	}
+
+	breaks := paragraphBreak.FindAllStringIndex(body, -1)
+	start := 0
+	for i := 0; i <= len(breaks); i++ {
+		end, next := len(body), len(body)
+		if i < len(breaks) {
+			end, next = breaks[i][0], breaks[i][1]
+		}
+		// paragraph includes the blank lines after it
+		paragraph, length := body[start:next], end-start
+		start = next
+
+		if length > maxChars {
+			flush()
+			d.splitSentences(paragraph, maxChars)
+			continue
+		}
+		if chunk.Len() > 0 && chunk.Len()+len(paragraph) > maxChars {
+			flush()
+		}
+		chunk.WriteString(paragraph)
+	}
+	flush()
+}
+
+// addTrimmed adds the text as a unit and keeps its trailing whitespace.
+func (d *Document) addTrimmed(text string) {
+	body := strings.TrimRight(text, " \t\n")
+	if body != "" {
+		d.add(body, nil)
+	}
+	d.keep(text[len(body):])
+}
+
+// splitSentences breaks a paragraph that is too long on sentence endings.
+func (d *Document) splitSentences(paragraph string, maxChars int) {
+	var chunk strings.Builder
+	start := 0
+	ends := sentenceEnd.FindAllStringIndex(paragraph, -1)
+	for i := 0; i <= len(ends); i++ {
+		end := len(paragraph)
+		if i < len(ends) {
+			end = ends[i][1]
+		}
+		sentence := paragraph[start:end]
+		start = end
+		if chunk.Len() > 0 && chunk.Len()+len(sentence) > maxChars {
+			d.addTrimmed(chunk.String())
+			chunk.Reset()
+		}
+		chunk.WriteString(sentence)
+	}
+	d.addTrimmed(chunk.String())
+}
+
+// splitMarkdown keeps front matter and fenced code blocks as they are and
+// splits the prose around them.
+func (d *Document) splitMarkdown(text string, maxChars int) {
+	lines := strings.SplitAfter(text, "\n")
+	var prose strings.Builder
+	flushProse := func() {
+		if prose.Len() > 0 {
+			d.splitProse(prose.String(), maxChars)
+			prose.Reset()
+		}
+	}
+
+	i := 0
+	if len(lines) > 0 && strings.TrimRight(lines[0], "\n") == "---" {
+		for end := 1; end < len(lines); end++ {
+			if strings.TrimRight(lines[end], "\n") == "---" {
+				d.keep(strings.Join(lines[:end+1], ""))
+				i = end + 1
+				break
+			}
+		}
+	}
+
+	for ; i < len(lines); i++ {
+		match := fence.FindStringSubmatch(lines[i])
+		if match == nil {
+			prose.WriteString(lines[i])
+			continue
+		}
+		flushProse()
+		end := i + 1
+		for end < len(lines) && !strings.HasPrefix(strings.TrimLeft(lines[end], " \t"), match[1]) {
+			end++
+		}
+		if end >= len(lines) {
+			end = len(lines) - 1
+		}
+		d.keep(strings.Join(lines[i:end+1], ""))
+		i = end
+	}
+	flushProse()
+}
@@ -0,0 +1,27 @@
+package docsplit
+
+import (
+	"strings"
+)
+
+// splitSRT keeps the number and timing of each subtitle and makes a unit of
+// its text.
+func (d *Document) splitSRT(text string) {
+	blocks := strings.SplitAfter(text, "\n\n")
+	for _, block := range blocks {
+		lines := strings.SplitAfter(block, "\n")
+		timing := -1
+		for i, line := range lines {
+			if strings.Contains(line, "-->") {
+				timing = i
+				break
+			}
+		}
+		if timing < 0 {
+			d.keep(block)
+			continue
+		}
+		d.keep(strings.Join(lines[:timing+1], ""))
+		d.addTrimmed(strings.Join(lines[timing+1:], ""))
+	}
+}
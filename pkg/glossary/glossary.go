@@ -0,0 +1,141 @@
+// Package glossary keeps product names and technical terms intact when text
+// is translated or revised by an AI model. The terms are turned into prompt
+// instructions and the response is checked afterward for terms the model
+// changed anyway.
+package glossary
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Term is a glossary entry.
+type Term struct {
+	ID *int64 `json:"id"`
+	// Source is the term as it is written in the original text.
+	Source string `json:"source"`
+	// Translations maps a language name, e.g. "Spanish", to the term in that language.
+	Translations map[string]string `json:"translations"`
+	// DoNotTranslate keeps the term as it is in every language.
+	DoNotTranslate bool `json:"doNotTranslate"`
+	// CaseSensitive only matches the term with the same capitalisation.
+	CaseSensitive bool `json:"caseSensitive"`
+}
+
+// Glossary is a list of terms.
+type Glossary []Term
+
+// Violation is a term the model didn't keep as instructed.
+type Violation struct {
+	Term Term
+	// Expected is how the term should have been written in the response.
+	Expected string
+}
+
+func (v Violation) String() string {
+	if v.Expected == v.Term.Source {
+		return fmt.Sprintf("%q was changed", v.Term.Source)
+	}
+	return fmt.Sprintf("%q should have been written as %q", v.Term.Source, v.Expected)
+}
+
+// expected returns how the term should appear in a response in the language,
+// an empty language means the text was revised rather than translated.
+func (t Term) expected(language string) (string, bool) {
+	if language == "" || t.DoNotTranslate {
+		return t.Source, true
+	}
+	translation, ok := t.Translations[language]
+	if !ok || translation == "" {
+		return "", false
+	}
+	return translation, true
+}
+
+// pattern matches the text as a whole word.
+func pattern(text string, caseSensitive bool) *regexp.Regexp {
+	flags := ""
+	if !caseSensitive {
+		flags = "(?i)"
+	}
+	return regexp.MustCompile(flags + `(^|[^\pL\pN])` + regexp.QuoteMeta(text) + `($|[^\pL\pN])`)
+}
+
+// Contains reports whether the term is used in the text.
+func (t Term) Contains(text string) bool {
+	if strings.TrimSpace(t.Source) == "" {
+		return false
+	}
+	return pattern(t.Source, t.CaseSensitive).MatchString(text)
+}
+
+// Relevant returns the terms used in the text, longest first so "Ctrl+Revise
+// Pro" is considered before "Ctrl+Revise".
+func (g Glossary) Relevant(text string) Glossary {
+	var relevant Glossary
+	for _, t := range g {
+		if t.Contains(text) {
+			relevant = append(relevant, t)
+		}
+	}
+	sort.SliceStable(relevant, func(i, j int) bool {
+		return len(relevant[i].Source) > len(relevant[j].Source)
+	})
+	return relevant
+}
+
+// Instructions returns the prompt text telling the model how to handle the
+// terms found in the text, language is the language being translated to and
+// is empty for revisions. Nothing is returned when no term is used.
+func (g Glossary) Instructions(text, language string) string {
+	var keep, translate []string
+	for _, t := range g.Relevant(text) {
+		expected, ok := t.expected(language)
+		if !ok {
+			continue
+		}
+		if expected == t.Source {
+			keep = append(keep, `"`+t.Source+`"`)
+		} else {
+			translate = append(translate, `"`+t.Source+`" as "`+expected+`"`)
+		}
+	}
+
+	var b strings.Builder
+	if len(keep) > 0 {
+		b.WriteString(" Keep these terms exactly as they are written, do not translate or change them: ")
+		b.WriteString(strings.Join(keep, ", "))
+		b.WriteString(".")
+	}
+	if len(translate) > 0 {
+		b.WriteString(" Always translate ")
+		b.WriteString(strings.Join(translate, ", "))
+		b.WriteString(".")
+	}
+	return b.String()
+}
+
+// Verify checks the response for terms of the input the model didn't keep as
+// instructed, language is empty for revisions. A revision may reword the
+// other terms, so only the terms that are never translated are checked.
+func (g Glossary) Verify(input, output, language string) []Violation {
+	var violations []Violation
+	for _, t := range g.Relevant(input) {
+		if language == "" && !t.DoNotTranslate {
+			continue
+		}
+		expected, ok := t.expected(language)
+		if !ok {
+			continue
+		}
+		// Translations are matched without case, a term at the start of a
+		// sentence gets a capital letter in most languages
+		caseSensitive := t.CaseSensitive && expected == t.Source
+		if !pattern(expected, caseSensitive).MatchString(output) {
+			violations = append(violations, Violation{Term: t, Expected: expected})
+		}
+	}
+	return violations
+}
@@ -0,0 +1,84 @@
+package glossary_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bahelit/ctrl_plus_revise/pkg/glossary"
+)
+
+var terms = glossary.Glossary{
+	{Source: "Ctrl+Revise", DoNotTranslate: true, CaseSensitive: true},
+	{Source: "Ctrl+Revise Pro", DoNotTranslate: true, CaseSensitive: true},
+	{Source: "shortcut", Translations: map[string]string{"Spanish": "atajo", "German": "Tastenkürzel"}},
+	{Source: "Ollama"},
+}
+
+func Test_Relevant(t *testing.T) {
+	relevant := terms.Relevant("Buy Ctrl+Revise Pro today")
+	if len(relevant) != 2 {
+		t.Fatalf("Expected 2 terms, received %d", len(relevant))
+	}
+	if relevant[0].Source != "Ctrl+Revise Pro" {
+		t.Errorf("Expected the longest term first, received %s", relevant[0].Source)
+	}
+	if len(terms.Relevant("ctrl+revise shortcuts")) != 0 {
+		t.Error("Expected case sensitive terms and partial words not to match")
+	}
+	if len(terms.Relevant("OLLAMA is running")) != 1 {
+		t.Error("Expected case insensitive terms to match")
+	}
+}
+
+func Test_Instructions(t *testing.T) {
+	got := terms.Instructions("Press the shortcut in Ctrl+Revise", "Spanish")
+	for _, want := range []string{`"Ctrl+Revise"`, `"shortcut" as "atajo"`} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Expected %s in %q", want, got)
+		}
+	}
+	got = terms.Instructions("Press the shortcut in Ctrl+Revise", "French")
+	if strings.Contains(got, "shortcut") {
+		t.Errorf("Expected terms without a translation to be left out, received %q", got)
+	}
+	got = terms.Instructions("Press the shortcut", "")
+	if !strings.Contains(got, `exactly as they are written, do not translate or change them: "shortcut"`) {
+		t.Errorf("Expected revisions to keep every term, received %q", got)
+	}
+	if got = terms.Instructions("Nothing to see here", "Spanish"); got != "" {
+		t.Errorf("Expected no instructions, received %q", got)
+	}
+}
+
+var verifyTable = []struct {
+	Input      string
+	Output     string
+	Language   string
+	Violations int
+}{
+	{"Open Ctrl+Revise", "Abre Ctrl+Revise", "Spanish", 0},
+	{"Open Ctrl+Revise", "Abre Control+Revisar", "Spanish", 1},
+	{"Open Ctrl+Revise", "Abre ctrl+revise", "Spanish", 1},
+	{"Use the shortcut", "Usa el atajo", "Spanish", 0},
+	{"Use the shortcut", "Usa el acceso directo", "Spanish", 1},
+	{"Use the shortcut", "Utilisez le raccourci", "French", 0},
+	{"Use the Ollama shortcut", "You can use the ollama shortcut", "", 0},
+	{"Use the Ollama shortcut", "You can use the shortcut", "", 0},
+	{"Open Ctrl+Revise", "Launch Control Revise", "", 1},
+}
+
+func Test_Verify(t *testing.T) {
+	for _, v := range verifyTable {
+		violations := terms.Verify(v.Input, v.Output, v.Language)
+		if len(violations) != v.Violations {
+			t.Errorf("Expected %d violations for %q -> %q, received %v", v.Violations, v.Input, v.Output, violations)
+		}
+	}
+}
+
+func Test_ViolationString(t *testing.T) {
+	violations := terms.Verify("Use the shortcut", "Usa el acceso directo", "Spanish")
+	if len(violations) != 1 || violations[0].String() != `"shortcut" should have been written as "atajo"` {
+		t.Errorf("Unexpected violation %v", violations)
+	}
+}
@@ -0,0 +1,148 @@
+// Package household describes who is eating, so a meal can be portioned for
+// the people at the table and respect what each of them can eat.
+package household
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/bahelit/ctrl_plus_revise/pkg/diet"
+)
+
+// AgeGroup scales the portion of a member.
+type AgeGroup string
+
+const (
+	Toddler AgeGroup = "Toddler"
+	Child   AgeGroup = "Child"
+	Teen    AgeGroup = "Teen"
+	Adult   AgeGroup = "Adult"
+	Senior  AgeGroup = "Senior"
+)
+
+// AgeGroups returns the age groups from youngest to oldest.
+func AgeGroups() []AgeGroup {
+	return []AgeGroup{Toddler, Child, Teen, Adult, Senior}
+}
+
+// Appetite scales the portion of a member.
+type Appetite string
+
+const (
+	Light  Appetite = "Light"
+	Normal Appetite = "Normal"
+	Hearty Appetite = "Hearty"
+)
+
+// Appetites returns the appetites from smallest to biggest.
+func Appetites() []Appetite {
+	return []Appetite{Light, Normal, Hearty}
+}
+
+// agePortions is how much of an adult serving each age group eats.
+var agePortions = map[AgeGroup]float64{Toddler: 0.4, Child: 0.65, Teen: 1.1, Adult: 1, Senior: 0.85}
+
+var appetitePortions = map[Appetite]float64{Light: 0.75, Normal: 1, Hearty: 1.3}
+
+// Member is someone in the household.
+type Member struct {
+	Name     string   `json:"name"`
+	AgeGroup AgeGroup `json:"ageGroup"`
+	Appetite Appetite `json:"appetite"`
+	// Restrictions are the member's allergies, diets and dislikes.
+	Restrictions diet.Restrictions `json:"restrictions"`
+	Likes        []string          `json:"likes,omitempty"`
+}
+
+// Portion is how many adult servings the member eats, unknown age groups
+// and appetites count as an adult with a normal appetite.
+func (m Member) Portion() float64 {
+	age, ok := agePortions[m.AgeGroup]
+	if !ok {
+		age = 1
+	}
+	appetite, ok := appetitePortions[m.Appetite]
+	if !ok {
+		appetite = 1
+	}
+	return age * appetite
+}
+
+// Describe returns the member for a prompt, e.g. "Sam (child, hearty appetite)".
+func (m Member) Describe() string {
+	var details []string
+	if m.AgeGroup != "" {
+		details = append(details, strings.ToLower(string(m.AgeGroup)))
+	}
+	if m.Appetite != "" && m.Appetite != Normal {
+		details = append(details, strings.ToLower(string(m.Appetite))+" appetite")
+	}
+	if len(details) == 0 {
+		return m.Name
+	}
+	return m.Name + " (" + strings.Join(details, ", ") + ")"
+}
+
+// Members are the people eating a meal.
+type Members []Member
+
+// Find returns the members with the names, in the order of the household.
+func (ms Members) Find(names []string) Members {
+	var found Members
+	for _, m := range ms {
+		if slices.ContainsFunc(names, func(n string) bool { return strings.EqualFold(n, m.Name) }) {
+			found = append(found, m)
+		}
+	}
+	return found
+}
+
+// Names returns the names of the members.
+func (ms Members) Names() []string {
+	var names []string
+	for _, m := range ms {
+		names = append(names, m.Name)
+	}
+	return names
+}
+
+// Servings is how many servings to cook, the portions of everybody rounded up.
+func (ms Members) Servings() int {
+	var portions float64
+	for _, m := range ms {
+		portions += m.Portion()
+	}
+	// Leave some room for rounding so 2.0000001 stays 2.
+	return int(math.Ceil(portions - 0.01))
+}
+
+// Restrictions returns the restrictions of everybody, a meal has to be safe
+// for every member eating it.
+func (ms Members) Restrictions() diet.Restrictions {
+	var restrictions diet.Restrictions
+	for _, m := range ms {
+		restrictions = restrictions.Merge(m.Restrictions)
+	}
+	return restrictions
+}
+
+// Prompt describes who is eating for a meal prompt, it starts with a period
+// so it can be appended to the prompt and is empty without members.
+func (ms Members) Prompt() string {
+	if len(ms) == 0 {
+		return ""
+	}
+	var people []string
+	for _, m := range ms {
+		people = append(people, m.Describe())
+	}
+	prompt := fmt.Sprintf(". We are cooking %d servings for %s, portion it for them", ms.Servings(), strings.Join(people, ", "))
+	for _, m := range ms {
+		if len(m.Likes) > 0 {
+			prompt += fmt.Sprintf(". %s likes %s", m.Name, strings.Join(m.Likes, ", "))
+		}
+	}
+	return prompt
+}
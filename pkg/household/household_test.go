@@ -0,0 +1,71 @@
+package household_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/bahelit/ctrl_plus_revise/pkg/diet"
+	"github.com/bahelit/ctrl_plus_revise/pkg/household"
+)
+
+var family = household.Members{
+	{Name: "Alex", AgeGroup: household.Adult, Appetite: household.Hearty, Likes: []string{"spicy food"}},
+	{Name: "Robin", AgeGroup: household.Adult, Restrictions: diet.Restrictions{Diets: []string{"Vegetarian"}}},
+	{Name: "Sam", AgeGroup: household.Child, Appetite: household.Normal,
+		Restrictions: diet.Restrictions{Allergies: []string{"Peanut"}, Dislikes: []string{"mushrooms"}}},
+	{Name: "Kit", AgeGroup: household.Toddler, Appetite: household.Light},
+}
+
+func TestPortion(t *testing.T) {
+	tests := []struct {
+		member household.Member
+		want   float64
+	}{
+		{household.Member{AgeGroup: household.Adult, Appetite: household.Normal}, 1},
+		{household.Member{AgeGroup: household.Teen, Appetite: household.Hearty}, 1.43},
+		{household.Member{AgeGroup: household.Toddler, Appetite: household.Light}, 0.3},
+		{household.Member{}, 1},
+	}
+	for _, tt := range tests {
+		if got := tt.member.Portion(); fmt.Sprintf("%.2f", got) != fmt.Sprintf("%.2f", tt.want) {
+			t.Errorf("Portion(%+v) = %.2f, want %.2f", tt.member, got, tt.want)
+		}
+	}
+}
+
+func TestServings(t *testing.T) {
+	tests := []struct {
+		names []string
+		want  int
+	}{
+		{[]string{"Alex", "Robin", "Sam", "Kit"}, 4},
+		{[]string{"Robin"}, 1},
+		{[]string{"robin", "sam"}, 2},
+		{[]string{"Robin", "Sam", "Kit"}, 2},
+		{nil, 0},
+	}
+	for _, tt := range tests {
+		if got := family.Find(tt.names).Servings(); got != tt.want {
+			t.Errorf("Servings(%q) = %d, want %d", tt.names, got, tt.want)
+		}
+	}
+}
+
+func TestRestrictions(t *testing.T) {
+	got := family.Find([]string{"Robin", "Sam"}).Restrictions()
+	want := diet.Restrictions{Allergies: []string{"Peanut"}, Diets: []string{"Vegetarian"}, Dislikes: []string{"mushrooms"}}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("Restrictions() = %+v, want %+v", got, want)
+	}
+}
+
+func TestPrompt(t *testing.T) {
+	want := ". We are cooking 3 servings for Alex (adult, hearty appetite), Sam (child), Kit (toddler, light appetite)" +
+		", portion it for them. Alex likes spicy food"
+	if got := family.Find([]string{"Alex", "Sam", "Kit"}).Prompt(); got != want {
+		t.Errorf("Prompt() = %q, want %q", got, want)
+	}
+	if got := household.Members(nil).Prompt(); got != "" {
+		t.Errorf("Prompt() without members = %q", got)
+	}
+}
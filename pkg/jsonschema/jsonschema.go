@@ -0,0 +1,222 @@
+// Package jsonschema describes the JSON a model should answer with and checks
+// the answer, it supports the subset of JSON Schema needed for Go structs.
+package jsonschema
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ErrInvalidJSON is returned for a document that isn't JSON at all.
+var ErrInvalidJSON = errors.New("invalid JSON")
+
+// Schema is a JSON Schema, e.g. {"type": "object", "properties": {...}}.
+type Schema struct {
+	Type        string             `json:"type"`
+	Description string             `json:"description,omitempty"`
+	Properties  map[string]*Schema `json:"properties,omitempty"`
+	Required    []string           `json:"required,omitempty"`
+	Items       *Schema            `json:"items,omitempty"`
+	Enum        []string           `json:"enum,omitempty"`
+}
+
+// String returns the schema as JSON for a prompt.
+func (s *Schema) String() string {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return "{}"
+	}
+	return string(data)
+}
+
+// Reflect returns the schema of a Go value, struct fields are named by their
+// json tag and are required unless tagged omitempty. A desc tag adds a
+// description and an enum tag lists the allowed values separated by |.
+func Reflect(v any) *Schema {
+	return reflectType(reflect.TypeOf(v))
+}
+
+func reflectType(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		s := &Schema{Type: "object", Properties: map[string]*Schema{}}
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			name, omitEmpty := fieldName(field)
+			if name == "-" {
+				continue
+			}
+			prop := reflectType(field.Type)
+			prop.Description = field.Tag.Get("desc")
+			if enum := field.Tag.Get("enum"); enum != "" {
+				prop.Enum = strings.Split(enum, "|")
+			}
+			s.Properties[name] = prop
+			if !omitEmpty {
+				s.Required = append(s.Required, name)
+			}
+		}
+		return s
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: reflectType(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	}
+	return &Schema{}
+}
+
+func fieldName(field reflect.StructField) (string, bool) {
+	tag := field.Tag.Get("json")
+	name, options, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = field.Name
+	}
+	return name, strings.Contains(options, "omitempty")
+}
+
+// ValidationError lists every problem found in a JSON document.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "the JSON doesn't match the schema: " + strings.Join(e.Problems, "; ")
+}
+
+// Validate checks the JSON document against the schema, the error is a
+// *ValidationError when the JSON is valid but doesn't match.
+func (s *Schema) Validate(data []byte) error {
+	var doc any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidJSON, err)
+	}
+	var problems []string
+	s.validate("$", doc, &problems)
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+func (s *Schema) validate(path string, v any, problems *[]string) {
+	fail := func(format string, args ...any) {
+		*problems = append(*problems, path+": "+fmt.Sprintf(format, args...))
+	}
+	switch s.Type {
+	case "object":
+		obj, ok := v.(map[string]any)
+		if !ok {
+			fail("expected an object, got %s", typeName(v))
+			return
+		}
+		for _, name := range s.Required {
+			if _, found := obj[name]; !found {
+				fail("missing required property %q", name)
+			}
+		}
+		names := make([]string, 0, len(s.Properties))
+		for name := range s.Properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if value, found := obj[name]; found {
+				s.Properties[name].validate(path+"."+name, value, problems)
+			}
+		}
+	case "array":
+		items, ok := v.([]any)
+		if !ok {
+			fail("expected an array, got %s", typeName(v))
+			return
+		}
+		if s.Items != nil {
+			for i, item := range items {
+				s.Items.validate(fmt.Sprintf("%s[%d]", path, i), item, problems)
+			}
+		}
+	case "string":
+		str, ok := v.(string)
+		if !ok {
+			fail("expected a string, got %s", typeName(v))
+			return
+		}
+		if len(s.Enum) > 0 && !contains(s.Enum, str) {
+			fail("%q is not one of %s", str, strings.Join(s.Enum, ", "))
+		}
+	case "integer":
+		n, ok := v.(float64)
+		if !ok || n != float64(int64(n)) {
+			fail("expected an integer, got %s", typeName(v))
+		}
+	case "number":
+		if _, ok := v.(float64); !ok {
+			fail("expected a number, got %s", typeName(v))
+		}
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			fail("expected a boolean, got %s", typeName(v))
+		}
+	}
+}
+
+func typeName(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case map[string]any:
+		return "an object"
+	case []any:
+		return "an array"
+	case string:
+		return "a string"
+	case float64:
+		return "a number"
+	case bool:
+		return "a boolean"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+func contains(values []string, v string) bool {
+	for _, value := range values {
+		if strings.EqualFold(value, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// Decode validates the JSON document against the schema of out and parses it.
+func Decode(data []byte, out any) error {
+	if err := Reflect(out).Validate(data); err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
+
+// IsValidationError reports whether err is a problem with the document rather
+// than something else going wrong.
+func IsValidationError(err error) bool {
+	var v *ValidationError
+	return errors.As(err, &v) || errors.Is(err, ErrInvalidJSON)
+}
@@ -0,0 +1,99 @@
+package jsonschema_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/bahelit/ctrl_plus_revise/pkg/jsonschema"
+)
+
+type item struct {
+	Name     string  `json:"name" desc:"What to buy"`
+	Quantity float64 `json:"quantity"`
+	Count    int     `json:"count,omitempty"`
+	Aisle    string  `json:"aisle" enum:"produce|dairy|other"`
+	internal string
+}
+
+type list struct {
+	Items []item   `json:"items"`
+	Tips  []string `json:"tips,omitempty"`
+	Done  bool     `json:"done"`
+	Skip  string   `json:"-"`
+}
+
+func Test_Reflect(t *testing.T) {
+	s := jsonschema.Reflect(list{})
+	if s.Type != "object" || strings.Join(s.Required, ",") != "items,done" {
+		t.Fatalf("Unexpected schema %s", s)
+	}
+	if _, ok := s.Properties["Skip"]; ok {
+		t.Error("Expected fields tagged - to be left out")
+	}
+	items := s.Properties["items"]
+	if items.Type != "array" || items.Items.Type != "object" {
+		t.Fatalf("Unexpected items schema %s", items)
+	}
+	name := items.Items.Properties["name"]
+	if name.Type != "string" || name.Description != "What to buy" {
+		t.Errorf("Unexpected name schema %s", name)
+	}
+	if items.Items.Properties["count"].Type != "integer" || items.Items.Properties["quantity"].Type != "number" {
+		t.Errorf("Unexpected number types %s", items.Items)
+	}
+	if len(items.Items.Properties["aisle"].Enum) != 3 {
+		t.Errorf("Expected the enum of aisle, received %s", items.Items.Properties["aisle"])
+	}
+	if _, ok := items.Items.Properties["internal"]; ok {
+		t.Error("Expected unexported fields to be left out")
+	}
+}
+
+var validateTable = []struct {
+	Name     string
+	JSON     string
+	Problems []string
+}{
+	{"valid", `{"items":[{"name":"milk","quantity":1,"aisle":"dairy"}],"done":false}`, nil},
+	{"missing", `{"items":[{"name":"milk","aisle":"dairy"}]}`, []string{`$: missing required property "done"`, `$.items[0]: missing required property "quantity"`}},
+	{"types", `{"items":{"name":"milk"},"done":"no"}`, []string{"$.done: expected a boolean, got a string", "$.items: expected an array, got an object"}},
+	{"enum", `{"items":[{"name":"milk","quantity":1,"aisle":"frozen"}],"done":true}`, []string{`$.items[0].aisle: "frozen" is not one of produce, dairy, other`}},
+	{"integer", `{"items":[{"name":"milk","quantity":1,"count":1.5,"aisle":"dairy"}],"done":true}`, []string{"$.items[0].count: expected an integer, got a number"}},
+}
+
+func Test_Validate(t *testing.T) {
+	s := jsonschema.Reflect(list{})
+	for _, v := range validateTable {
+		err := s.Validate([]byte(v.JSON))
+		if v.Problems == nil {
+			if err != nil {
+				t.Errorf("%s: expected no error, received %v", v.Name, err)
+			}
+			continue
+		}
+		var verr *jsonschema.ValidationError
+		if !errors.As(err, &verr) {
+			t.Errorf("%s: expected a validation error, received %v", v.Name, err)
+			continue
+		}
+		if strings.Join(verr.Problems, "|") != strings.Join(v.Problems, "|") {
+			t.Errorf("%s: expected %v, received %v", v.Name, v.Problems, verr.Problems)
+		}
+	}
+}
+
+func Test_Decode(t *testing.T) {
+	var l list
+	err := jsonschema.Decode([]byte(`{"items":[{"name":"eggs","quantity":12,"aisle":"Dairy"}],"done":true}`), &l)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(l.Items) != 1 || l.Items[0].Name != "eggs" || l.Items[0].Quantity != 12 || !l.Done {
+		t.Errorf("Unexpected list %+v", l)
+	}
+	err = jsonschema.Decode([]byte(`{"items": [`), &l)
+	if !jsonschema.IsValidationError(err) {
+		t.Errorf("Expected broken JSON to be a validation error, received %v", err)
+	}
+}
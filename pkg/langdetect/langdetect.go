@@ -0,0 +1,169 @@
+// Package langdetect guesses the language of a piece of text without leaving
+// the machine. Scripts that are only used by one language (Hangul, Thai, Greek,
+// ...) are recognised from their characters, languages written in the Latin
+// or Cyrillic alphabets are told apart by their most common words.
+package langdetect
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/language/display"
+)
+
+// MinConfidence is the confidence below which a guess shouldn't be trusted.
+const MinConfidence = 0.5
+
+// Result is the outcome of Detect.
+type Result struct {
+	Tag language.Tag
+	// Confidence is between 0 and 1.
+	Confidence float64
+}
+
+// Reliable reports whether the guess is good enough to act on.
+func (r Result) Reliable() bool {
+	return r.Tag != language.Und && r.Confidence >= MinConfidence
+}
+
+// scripts maps a script only used by one common language to that language.
+var scripts = []struct {
+	table *unicode.RangeTable
+	tag   language.Tag
+}{
+	{unicode.Hangul, language.Korean},
+	{unicode.Hiragana, language.Japanese},
+	{unicode.Katakana, language.Japanese},
+	{unicode.Thai, language.Thai},
+	{unicode.Greek, language.Greek},
+	{unicode.Hebrew, language.Hebrew},
+	{unicode.Arabic, language.Arabic},
+	{unicode.Devanagari, language.Hindi},
+	{unicode.Armenian, language.Armenian},
+	{unicode.Georgian, language.Georgian},
+	{unicode.Bengali, language.Bengali},
+	{unicode.Tamil, language.Tamil},
+}
+
+// Detect guesses the language of text, language.Und is returned when there is
+// nothing to go on.
+func Detect(text string) Result {
+	var letters, latin, cyrillic, han int
+	counts := map[language.Tag]int{}
+	for _, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		letters++
+		switch {
+		case unicode.Is(unicode.Latin, r):
+			latin++
+			continue
+		case unicode.Is(unicode.Cyrillic, r):
+			cyrillic++
+			continue
+		case unicode.Is(unicode.Han, r):
+			han++
+			continue
+		}
+		for _, s := range scripts {
+			if unicode.Is(s.table, r) {
+				counts[s.tag]++
+				break
+			}
+		}
+	}
+	if letters == 0 {
+		return Result{Tag: language.Und}
+	}
+
+	// Japanese mixes kana with Chinese characters, any kana at all means Japanese
+	if counts[language.Japanese] > 0 {
+		counts[language.Japanese] += han
+		han = 0
+	}
+	counts[language.Chinese] = han
+
+	best, bestCount := language.Und, 0
+	for tag, count := range counts {
+		if count > bestCount {
+			best, bestCount = tag, count
+		}
+	}
+	if bestCount >= latin && bestCount >= cyrillic {
+		return Result{Tag: best, Confidence: float64(bestCount) / float64(letters)}
+	}
+
+	share := float64(latin) / float64(letters)
+	if cyrillic > latin {
+		share = float64(cyrillic) / float64(letters)
+	}
+	result := byWords(text, cyrillic > latin)
+	result.Confidence *= share
+	return result
+}
+
+// byWords scores the text against the common words of each language written
+// in the same alphabet.
+func byWords(text string, cyrillic bool) Result {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\''
+	})
+	if len(words) == 0 {
+		return Result{Tag: language.Und}
+	}
+
+	var best, second float64
+	bestTag := language.Und
+	for _, p := range profiles {
+		if p.cyrillic != cyrillic {
+			continue
+		}
+		var score float64
+		for _, w := range words {
+			if p.words[w] {
+				score++
+			}
+		}
+		for _, r := range text {
+			if strings.ContainsRune(p.letters, unicode.ToLower(r)) {
+				score += 0.5
+			}
+		}
+		switch {
+		case score > best:
+			second = best
+			best, bestTag = score, p.tag
+		case score > second:
+			second = score
+		}
+	}
+	if best == 0 {
+		return Result{Tag: language.Und}
+	}
+
+	// Confidence grows with how many words were recognised and how far ahead
+	// of the runner-up the winner is
+	coverage := best / float64(len(words))
+	if coverage > 1 {
+		coverage = 1
+	}
+	margin := (best - second) / best
+	confidence := 0.5*margin + 0.5*min(1, coverage*3)
+	return Result{Tag: bestTag, Confidence: confidence}
+}
+
+// Name returns the English name of the language, e.g. "Brazilian Portuguese"
+// for pt-BR.
+func Name(tag language.Tag) string {
+	if name := display.English.Tags().Name(tag); name != "" {
+		return name
+	}
+	return tag.String()
+}
+
+// Parse reads a BCP-47 tag such as "pt-BR" or "sw".
+func Parse(s string) (language.Tag, error) {
+	return language.Parse(strings.TrimSpace(s))
+}
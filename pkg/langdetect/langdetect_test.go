@@ -0,0 +1,73 @@
+package langdetect_test
+
+import (
+	"testing"
+
+	"golang.org/x/text/language"
+
+	"github.com/bahelit/ctrl_plus_revise/pkg/langdetect"
+)
+
+var detectTable = []struct {
+	Text string
+	Tag  language.Tag
+}{
+	{"The quick brown fox jumps over the lazy dog and it was a very good day for all of them.", language.English},
+	{"¿Dónde está la biblioteca? Quiero leer un libro sobre la historia de España con mis amigos.", language.Spanish},
+	{"Je pense que nous devons partir maintenant, sinon nous allons rater le train pour Paris.", language.French},
+	{"Ich habe keine Zeit, weil ich noch mit dem Hund spazieren gehen muss und es regnet.", language.German},
+	{"Non ho mai visto una cosa così bella, è davvero incredibile che sia successo anche a me.", language.Italian},
+	{"Eu não sei se você vai poder vir amanhã, mas seria muito bom ver todos os amigos.", language.Portuguese},
+	{"Ik heb geen idee waar hij is, maar hij zou er om acht uur zijn met de auto.", language.Dutch},
+	{"Bu akşam sinemaya gitmek için çok yorgunum ama yarın seninle buluşabiliriz.", language.Turkish},
+	{"Nie wiem, czy to jest dobry pomysł, ale może spróbujemy jutro rano.", language.Polish},
+	{"Я не знаю, что он хочет сказать, но это было очень интересно для всех нас.", language.Russian},
+	{"Я не знаю, що він хоче сказати, але це було дуже цікаво для всіх.", language.Ukrainian},
+	{"今日はとても良い天気ですね。散歩に行きましょう。", language.Japanese},
+	{"我们明天去北京看长城，你想一起去吗？", language.Chinese},
+	{"안녕하세요, 오늘 날씨가 정말 좋네요.", language.Korean},
+	{"Καλημέρα, τι κάνεις σήμερα;", language.Greek},
+	{"مرحبا، كيف حالك اليوم؟", language.Arabic},
+}
+
+func Test_Detect(t *testing.T) {
+	for _, v := range detectTable {
+		r := langdetect.Detect(v.Text)
+		if r.Tag != v.Tag {
+			t.Errorf("Expected %s for %q, received %s", v.Tag, v.Text, r.Tag)
+			continue
+		}
+		if !r.Reliable() {
+			t.Errorf("Expected a reliable guess for %q, confidence was %.2f", v.Text, r.Confidence)
+		}
+	}
+}
+
+func Test_DetectNothingToGoOn(t *testing.T) {
+	for _, text := range []string{"", "1234 5678", "!!! ???"} {
+		r := langdetect.Detect(text)
+		if r.Tag != language.Und || r.Reliable() {
+			t.Errorf("Expected an undetermined language for %q, received %s", text, r.Tag)
+		}
+	}
+}
+
+func Test_DetectUnknownWords(t *testing.T) {
+	r := langdetect.Detect("Xylophone zebra quokka")
+	if r.Reliable() {
+		t.Errorf("Expected an unreliable guess, received %s with confidence %.2f", r.Tag, r.Confidence)
+	}
+}
+
+func Test_Name(t *testing.T) {
+	tag, err := langdetect.Parse(" pt-BR ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name := langdetect.Name(tag); name != "Brazilian Portuguese" {
+		t.Errorf("Expected Brazilian Portuguese, received %s", name)
+	}
+	if _, err = langdetect.Parse("not a language"); err == nil {
+		t.Error("Expected an error for an invalid tag")
+	}
+}
@@ -0,0 +1,79 @@
+package langdetect
+
+import (
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// profile is the most common words of a language and the letters that are
+// mostly only found in that language.
+type profile struct {
+	tag      language.Tag
+	cyrillic bool
+	letters  string
+	words    map[string]bool
+}
+
+func newProfile(tag string, cyrillic bool, letters, words string) profile {
+	p := profile{
+		tag:      language.MustParse(tag),
+		cyrillic: cyrillic,
+		letters:  letters,
+		words:    map[string]bool{},
+	}
+	for _, w := range strings.Fields(words) {
+		p.words[w] = true
+	}
+	return p
+}
+
+var profiles = []profile{
+	newProfile("en", false, "",
+		"the of and to a in is it you that he was for on are with as i his they be at one have this from "+
+			"or had by not but what some we can out other were all there when up use your how said an each "+
+			"she which do their if will way about many then them would these so her him has more could"),
+	newProfile("es", false, "ñ¿¡",
+		"de la que el en y a los se del las un por con no una su para es al lo como más pero sus le ya o "+
+			"este sí porque esta entre cuando muy sin sobre también me hasta hay donde quien desde todo nos "+
+			"durante todos uno les ni contra otros ese eso ante ellos e esto mí antes algunos qué unos yo"),
+	newProfile("fr", false, "çœ",
+		"de la le et les des en un du une que est pour qui dans a par plus pas au sur ne se ce il sont "+
+			"avec ils mais comme ou son on nous vous leur elle été aux bien sa cette je tout fait était "+
+			"aussi très peut même ces sans entre deux"),
+	newProfile("de", false, "ßäöü",
+		"der die und in den von zu das mit sich des auf für ist im dem nicht ein eine als auch es an "+
+			"werden aus er hat dass sie nach wird bei einer um am sind noch wie einem über einen so zum "+
+			"war haben nur oder aber vor zur bis mehr durch man ich wir"),
+	newProfile("it", false, "",
+		"di e il la che in a per un è del non sono le con i da si una ma gli al come lo più della anche "+
+			"nel alla mi questo ha ci io se ho lei suo sua essere era cosa molto quando dei nella delle "+
+			"perché tutto questa loro"),
+	newProfile("pt", false, "ãõ",
+		"de a o que e do da em um para é com não uma os no se na por mais as dos como mas foi ao ele das "+
+			"tem à seu sua ou ser quando muito há nos já está eu também só pelo pela até isso ela entre "+
+			"era depois sem mesmo aos ter seus quem nas me esse eles você"),
+	newProfile("nl", false, "ĳ",
+		"de en van ik te dat die in een hij het niet zijn is was op aan met als voor had er maar om hem "+
+			"dan zou of wat mijn men dit zo door over ze zich bij ook tot je mij uit der daar haar naar "+
+			"heb hoe heeft hebben deze u want nog zal me zij nu ge geen omdat iets worden toch al waren"),
+	newProfile("tr", false, "ğışı",
+		"bir ve bu da de için ile ne o çok gibi daha ama sonra kadar ben en var mı diye olarak olan şey "+
+			"her ya yok sen biz onun bana şimdi mi nasıl değil göre iki olduğu"),
+	newProfile("pl", false, "ąęłńśźżć",
+		"i w nie na się z że do to jest jak o co ale tak po od za już jego a przez dla tylko czy może "+
+			"jej być są ich mnie go było ten tym jednak także oraz"),
+	newProfile("sv", false, "å",
+		"och i att det som en på är av för med till den har de inte om ett han men var jag sig från vi "+
+			"så kan man när år säger hon under också efter eller nu sin där vid mot ska skulle"),
+	newProfile("ru", true, "ыэъё",
+		"и в не на я быть он с что а по это она этот к но они мы как из у который то за свой что весь "+
+			"год от так о для ты же все тот мочь вы человек такой его сказать только или еще бы себя один "+
+			"как уже до время если сам когда другой вот говорить наш мой знать стать при чтобы дело жизнь"),
+	newProfile("uk", true, "іїєґ",
+		"і в не на що я з він та як це до за а від але ти про у вони все так ми його вона бути якщо "+
+			"мене був було або тому коли щоб ще вже цей тільки"),
+	newProfile("bg", true, "",
+		"и на да е в не се с за от че по това като са си ще но ме го той ли ако би има които или беше "+
+			"те които може също много"),
+}
@@ -0,0 +1,33 @@
+package mask
+
+import "regexp"
+
+var (
+	backtickFence = regexp.MustCompile("(?ms)^[ \t]*```.*?^[ \t]*```[ \t]*$")
+	tildeFence    = regexp.MustCompile("(?ms)^[ \t]*~~~.*?^[ \t]*~~~[ \t]*$")
+	inlineCode    = regexp.MustCompile("``[^\n]+?``|`[^`\n]+`")
+	// linkTarget is the "(url "title")" part of [text](url "title"), the link
+	// text is left for the model
+	linkTarget    = regexp.MustCompile(`\]\([^()\s]*(\([^()\s]*\))?[^()\s]*(\s+"[^"]*")?\)`)
+	linkReference = regexp.MustCompile(`(?m)^[ \t]*\[[^\]\n]+\]:[ \t]*\S+.*$`)
+	autoLink      = regexp.MustCompile(`<(https?|ftp|mailto):[^>\s]+>`)
+	url           = regexp.MustCompile(`\b((https?|ftp)://|www\.)[^\s<>()\[\]]*[^\s<>()\[\].,;:!?'"]`)
+	email         = regexp.MustCompile(`\b[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}\b`)
+)
+
+// Markdown masks everything in a selection that isn't prose: code blocks,
+// inline code, link targets, URLs, email addresses and placeholders. Plain
+// text without any of them comes back unchanged.
+func Markdown(text string) Masked {
+	return Patterns(text,
+		backtickFence,
+		tildeFence,
+		inlineCode,
+		linkReference,
+		linkTarget,
+		autoLink,
+		url,
+		email,
+		placeholder,
+	)
+}
@@ -0,0 +1,101 @@
+// Package mask hides the parts of a text an AI model must not touch, such as
+// the placeholders of a translation string, behind numbered tokens and puts
+// them back once the model has answered.
+package mask
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ErrLostToken is returned by Restore when the response is missing a token.
+var ErrLostToken = errors.New("the response lost a masked part of the text")
+
+// placeholder matches the variables of the common i18n and printf formats:
+// {name}, {{name}}, ${name}, %s, %1$s, %(name)s and HTML/XML tags.
+var placeholder = regexp.MustCompile(
+	`\{\{[^{}]+\}\}|\$\{[^{}]+\}|\{[A-Za-z0-9_.:-]*\}|%(\([A-Za-z0-9_]+\)|[0-9]+\$)?[-+#0]*[0-9]*(\.[0-9]+)?[sdifgxXocqvTtbeEpu%]|</?[A-Za-z][^<>]*>`)
+
+// token matches the tokens put in place of the masked parts.
+var token = regexp.MustCompile(`⟦(\d+)⟧`)
+
+// Masked is a text with parts replaced by tokens.
+type Masked struct {
+	// Text is what is sent to the model.
+	Text  string
+	parts []string
+}
+
+// Placeholders masks the placeholders of the text.
+func Placeholders(text string) Masked {
+	return Patterns(text, placeholder)
+}
+
+// Patterns masks everything matching one of the patterns.
+func Patterns(text string, patterns ...*regexp.Regexp) Masked {
+	m := Masked{Text: text}
+	for _, p := range patterns {
+		m.Text = p.ReplaceAllStringFunc(m.Text, func(s string) string {
+			if token.MatchString(s) {
+				// Already masked by an earlier pattern
+				return s
+			}
+			m.parts = append(m.parts, s)
+			return tokenFor(len(m.parts) - 1)
+		})
+	}
+	return m
+}
+
+func tokenFor(i int) string {
+	return "⟦" + strconv.Itoa(i) + "⟧"
+}
+
+// Len returns the number of masked parts.
+func (m Masked) Len() int {
+	return len(m.parts)
+}
+
+// Restore puts the masked parts back into the response, an error wrapping
+// ErrLostToken is returned if the model dropped or invented a token.
+func (m Masked) Restore(response string) (string, error) {
+	if len(m.parts) == 0 {
+		return response, nil
+	}
+	seen := make([]bool, len(m.parts))
+	var unknown []string
+	restored := token.ReplaceAllStringFunc(response, func(s string) string {
+		i, err := strconv.Atoi(token.FindStringSubmatch(s)[1])
+		if err != nil || i >= len(m.parts) {
+			unknown = append(unknown, s)
+			return s
+		}
+		seen[i] = true
+		return m.parts[i]
+	})
+
+	var missing []string
+	for i, ok := range seen {
+		if !ok {
+			missing = append(missing, m.parts[i])
+		}
+	}
+	if len(missing) > 0 {
+		return restored, fmt.Errorf("%w: %s", ErrLostToken, strings.Join(missing, ", "))
+	}
+	if len(unknown) > 0 {
+		return restored, fmt.Errorf("%w: unknown %s", ErrLostToken, strings.Join(unknown, ", "))
+	}
+	return restored, nil
+}
+
+// Instructions is the text to add to the prompt so the model keeps the tokens.
+func (m Masked) Instructions() string {
+	if len(m.parts) == 0 {
+		return ""
+	}
+	return " The text contains markers such as ⟦0⟧, keep every marker exactly as it is and in the right place."
+}
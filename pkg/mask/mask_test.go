@@ -0,0 +1,96 @@
+package mask_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/bahelit/ctrl_plus_revise/pkg/mask"
+)
+
+var placeholderTable = []struct {
+	Text   string
+	Masked string
+}{
+	{"Hello {name}, you have %d new messages", "Hello ⟦0⟧, you have ⟦1⟧ new messages"},
+	{"Welcome {{user.name}} to ${app}", "Welcome ⟦0⟧ to ⟦1⟧"},
+	{"%(count)s files in %1$s", "⟦0⟧ files in ⟦1⟧"},
+	{"Click <b>here</b> to continue", "Click ⟦0⟧here⟦1⟧ to continue"},
+	{"100% sure, 50 %", "100% sure, 50 %"},
+	{"No placeholders at all", "No placeholders at all"},
+}
+
+func Test_Placeholders(t *testing.T) {
+	for _, v := range placeholderTable {
+		m := mask.Placeholders(v.Text)
+		if m.Text != v.Masked {
+			t.Errorf("Expected %q, received %q", v.Masked, m.Text)
+			continue
+		}
+		restored, err := m.Restore(m.Text)
+		if err != nil || restored != v.Text {
+			t.Errorf("Expected %q back, received %q (%v)", v.Text, restored, err)
+		}
+	}
+}
+
+func Test_RestoreReordered(t *testing.T) {
+	m := mask.Placeholders("Hello {name}, you have %d new messages")
+	restored, err := m.Restore("Tienes ⟦1⟧ mensajes nuevos, ⟦0⟧")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored != "Tienes %d mensajes nuevos, {name}" {
+		t.Errorf("Unexpected restore %q", restored)
+	}
+}
+
+func Test_RestoreLostToken(t *testing.T) {
+	m := mask.Placeholders("Hello {name}, you have %d new messages")
+	_, err := m.Restore("Hola, tienes ⟦1⟧ mensajes nuevos")
+	if !errors.Is(err, mask.ErrLostToken) || !strings.Contains(err.Error(), "{name}") {
+		t.Errorf("Expected a lost token error naming {name}, received %v", err)
+	}
+	_, err = m.Restore("Hola ⟦0⟧ ⟦1⟧ ⟦7⟧")
+	if !errors.Is(err, mask.ErrLostToken) {
+		t.Errorf("Expected an error for an invented token, received %v", err)
+	}
+}
+
+func Test_Instructions(t *testing.T) {
+	if mask.Placeholders("plain").Instructions() != "" {
+		t.Error("Expected no instructions without placeholders")
+	}
+	if mask.Placeholders("{x}").Instructions() == "" {
+		t.Error("Expected instructions with placeholders")
+	}
+}
+
+var markdownTable = []struct {
+	Text   string
+	Masked string
+}{
+	{"Run `go test ./...` before pushing", "Run ⟦0⟧ before pushing"},
+	{"See the [docs](https://example.com/a_(b) \"Docs\") for more", "See the [docs⟦0⟧ for more"},
+	{"Visit https://ctrlplusrevise.com/docs. Or www.example.com!", "Visit ⟦0⟧. Or ⟦1⟧!"},
+	{"Mail support@example.com or <mailto:help@example.com>", "Mail ⟦1⟧ or ⟦0⟧"},
+	{"[1]: https://example.com \"Example\"\nthis are text", "⟦0⟧\nthis are text"},
+	{"Fix this:\n```go\nfmt.Println(\"{name} %s\")\n```\nthanks", "Fix this:\n⟦0⟧\nthanks"},
+	{"~~~\nraw text\n~~~", "⟦0⟧"},
+	{"Dear {name}, it costs 5%", "Dear ⟦0⟧, it costs 5%"},
+	{"Just plain text.", "Just plain text."},
+}
+
+func Test_Markdown(t *testing.T) {
+	for _, v := range markdownTable {
+		m := mask.Markdown(v.Text)
+		if m.Text != v.Masked {
+			t.Errorf("Expected %q, received %q", v.Masked, m.Text)
+			continue
+		}
+		restored, err := m.Restore(m.Text)
+		if err != nil || restored != v.Text {
+			t.Errorf("Expected %q back, received %q (%v)", v.Text, restored, err)
+		}
+	}
+}
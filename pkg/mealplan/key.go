@@ -0,0 +1,16 @@
+package mealplan
+
+import (
+	"strings"
+
+	"github.com/bahelit/ctrl_plus_revise/pkg/recipe"
+)
+
+// itemKey matches the same food written differently.
+func itemKey(name string) string {
+	words := strings.Fields(strings.ToLower(name))
+	if len(words) > 0 {
+		words[len(words)-1] = recipe.Singular(words[len(words)-1])
+	}
+	return strings.Join(words, " ")
+}
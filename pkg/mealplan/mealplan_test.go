@@ -0,0 +1,148 @@
+package mealplan_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bahelit/ctrl_plus_revise/pkg/mealplan"
+	"github.com/bahelit/ctrl_plus_revise/pkg/pantry"
+	"github.com/bahelit/ctrl_plus_revise/pkg/recipe"
+)
+
+var now = time.Date(2024, time.June, 12, 18, 30, 0, 0, time.UTC)
+
+func TestWeekStart(t *testing.T) {
+	tests := []struct {
+		day  time.Time
+		want string
+	}{
+		{now, "2024-06-10"},
+		{time.Date(2024, time.June, 10, 0, 0, 0, 0, time.UTC), "2024-06-10"},
+		{time.Date(2024, time.June, 16, 23, 0, 0, 0, time.UTC), "2024-06-10"},
+		{time.Date(2024, time.June, 17, 1, 0, 0, 0, time.UTC), "2024-06-17"},
+	}
+	for _, tt := range tests {
+		if got := mealplan.WeekStart(tt.day).Format(time.DateOnly); got != tt.want {
+			t.Errorf("WeekStart(%v) = %s, want %s", tt.day, got, tt.want)
+		}
+	}
+}
+
+func TestWeek(t *testing.T) {
+	w := mealplan.Week{Start: mealplan.WeekStart(now)}
+	tuesday := w.Days()[1]
+	w.Set(mealplan.Entry{Date: tuesday, Meal: "Dinner", RecipeID: 1, Title: "Soup"})
+	w.Set(mealplan.Entry{Date: tuesday.Add(3 * time.Hour), Meal: "Dinner", RecipeID: 2, Title: "Stew", Servings: 2})
+	w.Set(mealplan.Entry{Date: tuesday, Meal: "Lunch", RecipeID: 1, Title: "Soup"})
+
+	if len(w.Entries) != 2 {
+		t.Fatalf("Set kept %d entries, want 2", len(w.Entries))
+	}
+	if e, ok := w.Entry(tuesday, "Dinner"); !ok || e.Title != "Stew" {
+		t.Errorf("Entry(Tuesday, Dinner) = %v, %v, want Stew", e, ok)
+	}
+	book := map[int64]recipe.Recipe{2: {Title: "Stew", Servings: 4, Ingredients: []recipe.Ingredient{{Name: "beef", Amount: 800, Unit: "g"}}}}
+	recipes := w.Recipes(book)
+	if len(recipes) != 1 || recipes[0].Ingredients[0].Amount != 400 {
+		t.Errorf("Recipes() = %v, want the stew for 2", recipes)
+	}
+	w.Remove(tuesday, "Lunch")
+	if _, ok := w.Entry(tuesday, "Lunch"); ok {
+		t.Errorf("Remove didn't clear the lunch")
+	}
+}
+
+func TestSection(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Black pepper", "Herbs & Spices"},
+		{"red bell pepper", "Produce"},
+		{"Frozen peas", "Frozen"},
+		{"eggs", "Dairy & Eggs"},
+		{"Chicken breast", "Meat & Seafood"},
+		{"basmati rice", "Pantry"},
+		{"dish soap", "Other"},
+		{"eggplant", "Produce"},
+		{"peanut butter", "Pantry"},
+		{"hamburger buns", "Bakery"},
+		{"ham", "Meat & Seafood"},
+		{"butternut squash", "Produce"},
+		{"fish sauce", "Pantry"},
+		{"salted butter", "Dairy & Eggs"},
+		{"chicken broth", "Pantry"},
+		{"beef stock", "Pantry"},
+		{"rolled oats", "Pantry"},
+		{"dinner rolls", "Bakery"},
+		{"mixed berries", "Produce"},
+		{"bay leaves", "Herbs & Spices"},
+		{"2 large potatoes", "Produce"},
+		{"coconut milk", "Pantry"},
+	}
+	for _, tt := range tests {
+		if got := mealplan.Section(tt.name); got != tt.want {
+			t.Errorf("Section(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestList(t *testing.T) {
+	recipes := []recipe.Recipe{
+		{Ingredients: []recipe.Ingredient{
+			{Name: "Tomatoes", Amount: 2},
+			{Name: "flour", Amount: 1, Unit: "cup"},
+			{Name: "milk", Amount: 250, Unit: "ml"},
+			{Name: "salt"},
+			{Name: "rice", Amount: 200, Unit: "g"},
+		}},
+		{Ingredients: []recipe.Ingredient{
+			{Name: "tomato", Amount: 3},
+			{Name: "flour", Amount: 0.5, Unit: "cups"},
+			{Name: "milk", Amount: 0.5, Unit: "l"},
+			{Name: "garlic", Amount: 2, Unit: "cloves"},
+			{Name: "rice", Amount: 100, Unit: "grams"},
+		}},
+	}
+	stock := pantry.Inventory{
+		{Name: "salt", Category: pantry.HerbsSpices},
+		{Name: "milk", Quantity: 1, Unit: "l", Expires: now.AddDate(0, 0, -1)},
+		{Name: "tomato", Quantity: 1},
+		{Name: "rice", Quantity: 1, Unit: "kg"},
+	}
+	got := mealplan.List(recipes, stock, now)
+	want := []string{
+		"Produce: 2 cloves garlic",
+		"Produce: 4 Tomatoes",
+		"Dairy & Eggs: 750 ml milk",
+		"Pantry: 1 1/2 cup flour",
+	}
+	var lines []string
+	for _, item := range got {
+		lines = append(lines, item.Section+": "+item.Describe())
+	}
+	if strings.Join(lines, "\n") != strings.Join(want, "\n") {
+		t.Errorf("List() =\n%s\nwant\n%s", strings.Join(lines, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestExport(t *testing.T) {
+	items := []mealplan.Item{
+		{Name: "garlic", Amount: 2, Unit: "cloves", Section: "Produce"},
+		{Name: "flour", Amount: 1.5, Unit: "cup", Section: "Pantry"},
+		{Name: "salt", Section: "Herbs & Spices"},
+	}
+	wantText := "Produce:\n- 2 cloves garlic\n\nPantry:\n- 1 1/2 cup flour\n\nHerbs & Spices:\n- salt\n"
+	if got := mealplan.Text(items); got != wantText {
+		t.Errorf("Text() = %q, want %q", got, wantText)
+	}
+	wantMarkdown := "# Shopping List\n\n## Produce\n\n- [ ] 2 cloves garlic\n\n## Pantry\n\n- [ ] 1 1/2 cup flour\n\n## Herbs & Spices\n\n- [ ] salt\n"
+	if got := mealplan.Markdown(items); got != wantMarkdown {
+		t.Errorf("Markdown() = %q, want %q", got, wantMarkdown)
+	}
+	wantCSV := "Section,Item,Amount,Unit\nProduce,garlic,2,cloves\nPantry,flour,1.5,cup\nHerbs & Spices,salt,,\n"
+	if got, err := mealplan.CSV(items); err != nil || got != wantCSV {
+		t.Errorf("CSV() = %q, %v, want %q", got, err, wantCSV)
+	}
+}
@@ -0,0 +1,96 @@
+// Package mealplan plans the meals of a week and turns the recipes of the
+// plan into one shopping list.
+package mealplan
+
+import (
+	"time"
+
+	"github.com/bahelit/ctrl_plus_revise/pkg/recipe"
+)
+
+// DaysPerWeek is the number of columns of the planner.
+const DaysPerWeek = 7
+
+// Meals returns the meals of a day in the order they are eaten.
+func Meals() []string {
+	return []string{"Breakfast", "Lunch", "Dinner"}
+}
+
+// Entry is a recipe planned for a meal.
+type Entry struct {
+	Date     time.Time
+	Meal     string
+	RecipeID int64
+	Title    string
+	// Servings is how many people eat, zero for the recipe's servings.
+	Servings int
+	// Eaters are the names of the members of the household eating the meal.
+	Eaters []string
+}
+
+// Week is the plan of the week starting on Start.
+type Week struct {
+	Start   time.Time
+	Entries []Entry
+}
+
+// WeekStart returns the Monday of the week of t at midnight.
+func WeekStart(t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	offset := (int(day.Weekday()) + DaysPerWeek - 1) % DaysPerWeek
+	return day.AddDate(0, 0, -offset)
+}
+
+// Days returns the dates of the week.
+func (w Week) Days() []time.Time {
+	days := make([]time.Time, DaysPerWeek)
+	for i := range days {
+		days[i] = w.Start.AddDate(0, 0, i)
+	}
+	return days
+}
+
+// Entry returns what is planned for the meal of the day.
+func (w Week) Entry(day time.Time, meal string) (Entry, bool) {
+	for _, e := range w.Entries {
+		if sameDay(e.Date, day) && e.Meal == meal {
+			return e, true
+		}
+	}
+	return Entry{}, false
+}
+
+// Set plans the entry, replacing what was planned for the meal.
+func (w *Week) Set(entry Entry) {
+	w.Remove(entry.Date, entry.Meal)
+	w.Entries = append(w.Entries, entry)
+}
+
+// Remove clears the meal of the day.
+func (w *Week) Remove(day time.Time, meal string) {
+	entries := w.Entries[:0]
+	for _, e := range w.Entries {
+		if !sameDay(e.Date, day) || e.Meal != meal {
+			entries = append(entries, e)
+		}
+	}
+	w.Entries = entries
+}
+
+// Recipes returns the recipes of the week scaled to the servings of every
+// meal, recipes no longer in the book are left out.
+func (w Week) Recipes(book map[int64]recipe.Recipe) []recipe.Recipe {
+	var recipes []recipe.Recipe
+	for _, e := range w.Entries {
+		r, ok := book[e.RecipeID]
+		if !ok {
+			continue
+		}
+		recipes = append(recipes, r.Scale(e.Servings))
+	}
+	return recipes
+}
+
+func sameDay(a, b time.Time) bool {
+	return a.Year() == b.Year() && a.YearDay() == b.YearDay()
+}
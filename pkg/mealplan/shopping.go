@@ -0,0 +1,258 @@
+package mealplan
+
+import (
+	"bytes"
+	"encoding/csv"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/bahelit/ctrl_plus_revise/pkg/pantry"
+	"github.com/bahelit/ctrl_plus_revise/pkg/recipe"
+)
+
+// Sections returns the store sections in the order of a walk through the store.
+func Sections() []string {
+	return []string{"Produce", "Meat & Seafood", "Dairy & Eggs", "Bakery", "Pantry", "Frozen", "Herbs & Spices", "Other"}
+}
+
+// sectionKeywords find the section of an ingredient, the first match wins
+// so "black pepper" is a spice, "frozen peas" frozen and "chicken broth" a
+// pantry item. Keywords match whole words, or their plural, so "salted
+// butter" isn't salt and "rolled oats" aren't rolls. Exceptions are
+// ingredients that only look like they match, e.g. peanut butter isn't dairy.
+var sectionKeywords = []struct {
+	section    string
+	keywords   []string
+	exceptions []string
+}{
+	{"Frozen", []string{"frozen", "ice cream"}, nil},
+	{"Herbs & Spices", []string{"salt", "black pepper", "cumin", "paprika", "oregano", "basil", "thyme", "rosemary",
+		"cinnamon", "chili powder", "parsley", "cilantro", "dill", "nutmeg", "turmeric", "bay leaf", "bay leaves", "curry"}, nil},
+	{"Pantry", []string{"broth", "stock", "bouillon"}, nil},
+	{"Meat & Seafood", []string{"chicken", "beef", "pork", "turkey", "bacon", "sausage", "fish", "salmon", "tuna",
+		"shrimp", "lamb", "ham", "cod", "steak", "mince"}, []string{"fish sauce"}},
+	{"Dairy & Eggs", []string{"milk", "cheese", "butter", "yogurt", "yoghurt", "cream", "egg", "parmesan", "mozzarella"},
+		[]string{"peanut butter", "almond butter", "coconut milk"}},
+	{"Bakery", []string{"bread", "bun", "tortilla", "bagel", "pita", "baguette", "roll"}, nil},
+	{"Pantry", []string{"flour", "sugar", "rice", "pasta", "spaghetti", "oil", "vinegar", "bean", "lentil", "oat",
+		"sauce", "honey", "noodle", "chickpea", "canned", "baking", "yeast", "nut", "peanut", "almond butter",
+		"coconut milk", "quinoa"}, nil},
+	{"Produce", []string{"apple", "banana", "lemon", "lime", "orange", "berry", "berries", "tomato", "potato", "onion",
+		"garlic", "carrot", "lettuce", "spinach", "pepper", "cucumber", "zucchini", "broccoli", "mushroom", "celery",
+		"avocado", "kale", "cabbage", "ginger", "squash", "pea", "corn", "scallion", "leek", "eggplant", "fruit"}, nil},
+}
+
+// pantrySections are the store sections of the pantry categories.
+var pantrySections = map[pantry.Category]string{
+	pantry.Dairy:       "Dairy & Eggs",
+	pantry.Freezer:     "Frozen",
+	pantry.HerbsSpices: "Herbs & Spices",
+	pantry.Pantry:      "Pantry",
+	pantry.Vegetables:  "Produce",
+	pantry.Protein:     "Meat & Seafood",
+}
+
+// Section returns the store section of an ingredient.
+func Section(name string) string {
+	text := strings.ToLower(name)
+	for _, s := range sectionKeywords {
+		if slices.ContainsFunc(s.exceptions, func(exception string) bool {
+			return containsWord(text, exception)
+		}) {
+			continue
+		}
+		for _, keyword := range s.keywords {
+			if containsWord(text, keyword) {
+				return s.section
+			}
+		}
+	}
+	return "Other"
+}
+
+// containsWord reports whether the text has the keyword as a whole word, or
+// as a word ending in s or es.
+func containsWord(text, keyword string) bool {
+	for i := 0; i < len(text); {
+		j := strings.Index(text[i:], keyword)
+		if j < 0 {
+			return false
+		}
+		start, end := i+j, i+j+len(keyword)
+		if start == 0 || !isLetter(text[start-1]) {
+			rest := strings.TrimPrefix(text[end:], "s")
+			if strings.HasPrefix(text[end:], "es") {
+				rest = text[end+2:]
+			}
+			if rest == "" || !isLetter(rest[0]) {
+				return true
+			}
+		}
+		i = start + 1
+	}
+	return false
+}
+
+func isLetter(c byte) bool {
+	return 'a' <= c && c <= 'z' || c >= utf8.RuneSelf
+}
+
+// Item is a line of the shopping list, an amount of zero means some, e.g.
+// salt to taste.
+type Item struct {
+	Name    string
+	Amount  float64
+	Unit    string
+	Section string
+}
+
+// Describe returns the item for a list, e.g. "1 1/2 cup rice".
+func (i Item) Describe() string {
+	return recipe.Ingredient{Name: i.Name, Amount: i.Amount, Unit: i.Unit}.Describe()
+}
+
+// total adds up the amounts of one ingredient, base is in grams, milliliters
+// or a count.
+type total struct {
+	name    string
+	unit    recipe.Unit
+	base    float64
+	section string
+}
+
+// List adds up the ingredients of the recipes and subtracts what is in stock,
+// food in the pantry without a quantity counts as enough. The list is sorted
+// by section and name.
+func List(recipes []recipe.Recipe, stock pantry.Inventory, now time.Time) []Item {
+	var (
+		order  []string
+		totals = map[string]*total{}
+	)
+	for _, r := range recipes {
+		for _, ingredient := range r.Ingredients {
+			name := strings.TrimSpace(ingredient.Name)
+			if name == "" {
+				continue
+			}
+			u := recipe.LookupUnit(ingredient.Unit)
+			key := itemKey(name) + "|" + string(u.Dimension)
+			t, ok := totals[key]
+			if !ok {
+				t = &total{name: name, unit: u, section: Section(name)}
+				totals[key] = t
+				order = append(order, key)
+			}
+			t.base += ingredient.Amount * u.Factor
+		}
+	}
+
+	for _, item := range stock {
+		if days, ok := item.DaysLeft(now); ok && days < 0 {
+			continue
+		}
+		name := itemKey(item.Name)
+		if item.Quantity <= 0 {
+			for key := range totals {
+				if strings.HasPrefix(key, name+"|") {
+					delete(totals, key)
+				}
+			}
+			continue
+		}
+		u := recipe.LookupUnit(item.Unit)
+		key := name + "|" + string(u.Dimension)
+		t, ok := totals[key]
+		if !ok {
+			continue
+		}
+		if t.section == "Other" && pantrySections[item.Category] != "" {
+			t.section = pantrySections[item.Category]
+		}
+		t.base -= item.Quantity * u.Factor
+		if t.base <= 0.001 {
+			delete(totals, key)
+		}
+	}
+
+	var items []Item
+	for _, key := range order {
+		t, ok := totals[key]
+		if !ok {
+			continue
+		}
+		items = append(items, Item{
+			Name:    t.name,
+			Amount:  math.Round(t.base/t.unit.Factor*100) / 100,
+			Unit:    t.unit.Name,
+			Section: t.section,
+		})
+	}
+	rank := map[string]int{}
+	for i, s := range Sections() {
+		rank[s] = i
+	}
+	sort.SliceStable(items, func(a, b int) bool {
+		if items[a].Section != items[b].Section {
+			return rank[items[a].Section] < rank[items[b].Section]
+		}
+		return strings.ToLower(items[a].Name) < strings.ToLower(items[b].Name)
+	})
+	return items
+}
+
+// Markdown writes the list as a checklist under a heading per section.
+func Markdown(items []Item) string {
+	var b strings.Builder
+	b.WriteString("# Shopping List\n")
+	section := ""
+	for _, item := range items {
+		if item.Section != section {
+			section = item.Section
+			b.WriteString("\n## " + section + "\n\n")
+		}
+		b.WriteString("- [ ] " + item.Describe() + "\n")
+	}
+	return b.String()
+}
+
+// Text writes the list as plain text for a notes app.
+func Text(items []Item) string {
+	var b strings.Builder
+	section := ""
+	for _, item := range items {
+		if item.Section != section {
+			if section != "" {
+				b.WriteString("\n")
+			}
+			section = item.Section
+			b.WriteString(section + ":\n")
+		}
+		b.WriteString("- " + item.Describe() + "\n")
+	}
+	return b.String()
+}
+
+// CSV writes the list for a spreadsheet, the amount is a plain number.
+func CSV(items []Item) (string, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write([]string{"Section", "Item", "Amount", "Unit"}); err != nil {
+		return "", err
+	}
+	for _, item := range items {
+		amount := ""
+		if item.Amount > 0 {
+			amount = strconv.FormatFloat(item.Amount, 'f', -1, 64)
+		}
+		if err := w.Write([]string{item.Section, item.Name, amount, item.Unit}); err != nil {
+			return "", err
+		}
+	}
+	w.Flush()
+	return buf.String(), w.Error()
+}
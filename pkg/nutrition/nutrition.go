@@ -0,0 +1,198 @@
+// Package nutrition estimates the calories and macronutrients of a recipe
+// from an offline food composition table shipped with the program.
+package nutrition
+
+import (
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/bahelit/ctrl_plus_revise/pkg/recipe"
+)
+
+//go:embed foods.csv
+var foodsCSV string
+
+// Facts are the energy and macronutrients of an amount of food.
+type Facts struct {
+	Calories float64
+	Protein  float64
+	Carbs    float64
+	Fat      float64
+}
+
+// Add returns the sum of both.
+func (f Facts) Add(other Facts) Facts {
+	return Facts{f.Calories + other.Calories, f.Protein + other.Protein, f.Carbs + other.Carbs, f.Fat + other.Fat}
+}
+
+// Scale returns the facts times the factor.
+func (f Facts) Scale(factor float64) Facts {
+	return Facts{f.Calories * factor, f.Protein * factor, f.Carbs * factor, f.Fat * factor}
+}
+
+// String shows the facts on one line, e.g. "520 kcal, protein 31 g, carbs 45 g, fat 22 g".
+func (f Facts) String() string {
+	return fmt.Sprintf("%.0f kcal, protein %.0f g, carbs %.0f g, fat %.0f g", f.Calories, f.Protein, f.Carbs, f.Fat)
+}
+
+// Food is an entry of the food composition table.
+type Food struct {
+	Name    string
+	Aliases []string
+	// Per100g are the facts of 100 grams.
+	Per100g Facts
+	// GramsEach is the weight of one, e.g. an egg, zero when it isn't counted.
+	GramsEach float64
+	// GramsPerML converts cups and spoons to grams, zero when unknown.
+	GramsPerML float64
+}
+
+// Foods returns the food composition table.
+var Foods = sync.OnceValue(func() []Food {
+	foods, err := parseFoods(foodsCSV)
+	if err != nil {
+		panic("nutrition: the embedded food table is invalid: " + err.Error())
+	}
+	return foods
+})
+
+func parseFoods(data string) ([]Food, error) {
+	r := csv.NewReader(strings.NewReader(data))
+	r.Comment = '#'
+	r.FieldsPerRecord = 8
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	var foods []Food
+	for _, record := range records {
+		var numbers [6]float64
+		for i, field := range record[2:] {
+			if field == "" {
+				continue
+			}
+			if numbers[i], err = strconv.ParseFloat(field, 64); err != nil {
+				return nil, fmt.Errorf("%s: %w", record[0], err)
+			}
+		}
+		food := Food{
+			Name:       record[0],
+			Per100g:    Facts{numbers[0], numbers[1], numbers[2], numbers[3]},
+			GramsEach:  numbers[4],
+			GramsPerML: numbers[5],
+		}
+		if record[1] != "" {
+			food.Aliases = strings.Split(record[1], "|")
+		}
+		foods = append(foods, food)
+	}
+	return foods, nil
+}
+
+// Match returns the food of an ingredient, the longest name or alias found
+// at the start of a word wins so "olive oil" beats "oil" and "peanut butter"
+// beats "butter".
+func Match(ingredient string) (Food, bool) {
+	text := " " + strings.Join(strings.Fields(strings.ToLower(ingredient)), " ")
+	var (
+		best    Food
+		longest int
+	)
+	for _, food := range Foods() {
+		for _, name := range append([]string{food.Name}, food.Aliases...) {
+			if len(name) > longest && strings.Contains(text, " "+name) {
+				best, longest = food, len(name)
+			}
+		}
+	}
+	return best, longest > 0
+}
+
+// unitGrams are the weights of units that aren't a measure.
+var unitGrams = map[string]float64{
+	"clove": 3, "slice": 28, "can": 400, "tin": 400, "pinch": 0.4, "dash": 0.6, "stick": 113,
+	"bunch": 100, "handful": 30, "sprig": 1, "head": 500, "fillet": 170, "strip": 12, "packet": 250, "jar": 350,
+}
+
+// sizes scale the weight of one, e.g. 2 large eggs.
+var sizes = map[string]float64{"": 1, "each": 1, "piece": 1, "whole": 1, "item": 1, "medium": 1, "large": 1.25, "small": 0.75}
+
+// Grams returns the weight of the ingredient, ok is false when the unit
+// can't be converted for the food.
+func Grams(i recipe.Ingredient, food Food) (grams float64, ok bool) {
+	if i.Amount <= 0 {
+		return 0, true
+	}
+	u := recipe.LookupUnit(i.Unit)
+	switch u.Dimension {
+	case recipe.Mass:
+		return i.Amount * u.Factor, true
+	case recipe.Volume:
+		if food.GramsPerML == 0 {
+			return 0, false
+		}
+		return i.Amount * u.Factor * food.GramsPerML, true
+	}
+	if size, ok := sizes[u.Name]; ok && food.GramsEach > 0 {
+		return i.Amount * size * food.GramsEach, true
+	}
+	if grams, ok := unitGrams[recipe.Singular(u.Name)]; ok {
+		return i.Amount * grams, true
+	}
+	return 0, false
+}
+
+// Estimate is the nutrition of a recipe, ingredients that aren't in the table
+// or can't be weighed are left out and listed.
+type Estimate struct {
+	Total      Facts
+	PerServing Facts
+	Unmatched  []string
+}
+
+// Recipe estimates the nutrition of the recipe, a recipe without servings
+// counts as one serving.
+func Recipe(r recipe.Recipe) Estimate {
+	var e Estimate
+	for _, ingredient := range r.Ingredients {
+		food, ok := Match(ingredient.Name)
+		if !ok {
+			e.Unmatched = append(e.Unmatched, ingredient.Name)
+			continue
+		}
+		grams, ok := Grams(ingredient, food)
+		if !ok {
+			e.Unmatched = append(e.Unmatched, ingredient.Name)
+			continue
+		}
+		e.Total = e.Total.Add(food.Per100g.Scale(grams / 100))
+	}
+	e.PerServing = e.Total.Scale(1 / float64(max(r.Servings, 1)))
+	return e
+}
+
+// PerPerson adds up a serving of every recipe, e.g. what one person eats in
+// a week of planned meals.
+func PerPerson(recipes []recipe.Recipe) Facts {
+	var total Facts
+	for _, r := range recipes {
+		total = total.Add(Recipe(r).PerServing)
+	}
+	return total
+}
+
+// Markdown shows the estimate below a recipe.
+func (e Estimate) Markdown() string {
+	var b strings.Builder
+	b.WriteString("## Nutrition per Serving\n\n")
+	b.WriteString(fmt.Sprintf("**%.0f kcal** - protein %.0f g - carbs %.0f g - fat %.0f g\n",
+		e.PerServing.Calories, e.PerServing.Protein, e.PerServing.Carbs, e.PerServing.Fat))
+	if len(e.Unmatched) > 0 {
+		b.WriteString("\n*Estimated without " + strings.Join(e.Unmatched, ", ") + ".*\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,108 @@
+package nutrition_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/bahelit/ctrl_plus_revise/pkg/nutrition"
+	"github.com/bahelit/ctrl_plus_revise/pkg/recipe"
+)
+
+func TestFoods(t *testing.T) {
+	foods := nutrition.Foods()
+	if len(foods) < 50 {
+		t.Fatalf("Foods() has %d entries", len(foods))
+	}
+	seen := map[string]bool{}
+	for _, f := range foods {
+		if seen[f.Name] {
+			t.Errorf("%s is in the table twice", f.Name)
+		}
+		seen[f.Name] = true
+		// Carbs include fiber, which has fewer calories, so the sum is only close.
+		macros := f.Per100g.Protein*4 + f.Per100g.Carbs*4 + f.Per100g.Fat*9
+		if math.Abs(macros-f.Per100g.Calories) > 0.3*f.Per100g.Calories+15 {
+			t.Errorf("%s: %.0f kcal don't add up with the macros, %.0f kcal", f.Name, f.Per100g.Calories, macros)
+		}
+	}
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		ingredient string
+		want       string
+	}{
+		{"Extra Virgin Olive Oil", "olive oil"},
+		{"creamy peanut butter", "peanut butter"},
+		{"unsalted butter", "butter"},
+		{"boneless chicken breasts", "chicken breast"},
+		{"low sodium chicken broth", "chicken broth"},
+		{"large eggs", "egg"},
+		{"eggplant", "eggplant"},
+		{"red bell pepper", "bell pepper"},
+		{"freshly ground black pepper", "black pepper"},
+		{"dragon fruit", ""},
+	}
+	for _, tt := range tests {
+		food, ok := nutrition.Match(tt.ingredient)
+		if food.Name != tt.want || ok != (tt.want != "") {
+			t.Errorf("Match(%q) = %q, %v, want %q", tt.ingredient, food.Name, ok, tt.want)
+		}
+	}
+}
+
+func TestGrams(t *testing.T) {
+	flour, _ := nutrition.Match("flour")
+	egg, _ := nutrition.Match("egg")
+	garlic, _ := nutrition.Match("garlic")
+	tests := []struct {
+		ingredient recipe.Ingredient
+		food       nutrition.Food
+		want       float64
+		ok         bool
+	}{
+		{recipe.Ingredient{Amount: 250, Unit: "g"}, flour, 250, true},
+		{recipe.Ingredient{Amount: 1, Unit: "lb"}, flour, 453.592, true},
+		{recipe.Ingredient{Amount: 1, Unit: "cup"}, flour, 125.39, true},
+		{recipe.Ingredient{Amount: 2}, egg, 100, true},
+		{recipe.Ingredient{Amount: 2, Unit: "large"}, egg, 125, true},
+		{recipe.Ingredient{Amount: 3, Unit: "cloves"}, garlic, 9, true},
+		{recipe.Ingredient{Amount: 2}, flour, 0, false},
+		{recipe.Ingredient{}, flour, 0, true},
+	}
+	for _, tt := range tests {
+		got, ok := nutrition.Grams(tt.ingredient, tt.food)
+		if math.Abs(got-tt.want) > 0.01 || ok != tt.ok {
+			t.Errorf("Grams(%v, %s) = %.2f, %v, want %.2f, %v", tt.ingredient, tt.food.Name, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestRecipe(t *testing.T) {
+	r := recipe.Recipe{
+		Servings: 2,
+		Ingredients: []recipe.Ingredient{
+			{Name: "chicken breast", Amount: 400, Unit: "g"},
+			{Name: "white rice", Amount: 150, Unit: "g"},
+			{Name: "olive oil", Amount: 1, Unit: "tbsp"},
+			{Name: "salt"},
+			{Name: "saffron", Amount: 1, Unit: "pinch"},
+		},
+	}
+	e := nutrition.Recipe(r)
+	want := nutrition.Facts{Calories: 480 + 547.5 + 120.4, Protein: 90 + 10.65, Carbs: 120, Fat: 10.4 + 1.05 + 13.6}
+	if math.Abs(e.Total.Calories-want.Calories) > 1 || math.Abs(e.Total.Protein-want.Protein) > 0.1 ||
+		math.Abs(e.Total.Carbs-want.Carbs) > 0.1 || math.Abs(e.Total.Fat-want.Fat) > 0.1 {
+		t.Errorf("Recipe().Total = %v, want %v", e.Total, want)
+	}
+	if math.Abs(e.PerServing.Calories-e.Total.Calories/2) > 0.01 {
+		t.Errorf("Recipe().PerServing = %v, want half of %v", e.PerServing, e.Total)
+	}
+	if len(e.Unmatched) != 1 || e.Unmatched[0] != "saffron" {
+		t.Errorf("Recipe().Unmatched = %q, want saffron", e.Unmatched)
+	}
+	week := nutrition.PerPerson([]recipe.Recipe{r, r})
+	if math.Abs(week.Calories-e.Total.Calories) > 0.01 {
+		t.Errorf("PerPerson() = %v, want %v", week, e.Total)
+	}
+}
@@ -0,0 +1,155 @@
+// Package pantry keeps track of the food in the kitchen, how much of it there
+// is and when it goes off.
+package pantry
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DateLayout is how dates are written and stored.
+const DateLayout = time.DateOnly
+
+// Category groups items the way the meal planner does.
+type Category string
+
+const (
+	Dairy       Category = "Dairy"
+	Freezer     Category = "Freezer"
+	HerbsSpices Category = "Herbs & Spices"
+	Pantry      Category = "Pantry"
+	Vegetables  Category = "Vegetables"
+	Protein     Category = "Protein"
+)
+
+// Categories returns every category in the order of the meal planner tabs.
+func Categories() []Category {
+	return []Category{HerbsSpices, Vegetables, Dairy, Pantry, Protein, Freezer}
+}
+
+// Item is something in the kitchen.
+type Item struct {
+	ID       *int64
+	Name     string
+	Category Category
+	Quantity float64
+	// Unit is e.g. lb, g, cups or empty for a count.
+	Unit      string
+	Purchased time.Time
+	// Expires is zero for food that keeps.
+	Expires time.Time
+}
+
+// DaysLeft returns the days until the item expires, negative when it already
+// has, ok is false for food that keeps.
+func (i Item) DaysLeft(now time.Time) (days int, ok bool) {
+	if i.Expires.IsZero() {
+		return 0, false
+	}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	expires := time.Date(i.Expires.Year(), i.Expires.Month(), i.Expires.Day(), 0, 0, 0, 0, time.UTC)
+	return int(expires.Sub(today).Hours() / 24), true
+}
+
+// Amount returns the quantity with its unit, e.g. "2 lb" or "3".
+func (i Item) Amount() string {
+	if i.Quantity == 0 {
+		return ""
+	}
+	amount := strconv.FormatFloat(i.Quantity, 'f', -1, 64)
+	if i.Unit != "" {
+		amount += " " + i.Unit
+	}
+	return amount
+}
+
+// Describe returns the item for a prompt, e.g. "2 lb chicken breast".
+func (i Item) Describe() string {
+	if amount := i.Amount(); amount != "" {
+		return amount + " " + i.Name
+	}
+	return i.Name
+}
+
+// Expiry describes when the item goes off, e.g. "expires in 3 days".
+func (i Item) Expiry(now time.Time) string {
+	days, ok := i.DaysLeft(now)
+	switch {
+	case !ok:
+		return ""
+	case days < 0:
+		return "expired"
+	case days == 0:
+		return "expires today"
+	case days == 1:
+		return "expires tomorrow"
+	}
+	return fmt.Sprintf("expires in %d days", days)
+}
+
+// Inventory is everything in the kitchen.
+type Inventory []Item
+
+// InCategory returns the items of the category.
+func (inv Inventory) InCategory(c Category) Inventory {
+	var items Inventory
+	for _, i := range inv {
+		if i.Category == c {
+			items = append(items, i)
+		}
+	}
+	return items
+}
+
+// ExpiringSoon returns the items that haven't expired yet but will within
+// the days, the first to expire first.
+func (inv Inventory) ExpiringSoon(now time.Time, within int) Inventory {
+	var items Inventory
+	for _, i := range inv {
+		if days, ok := i.DaysLeft(now); ok && days >= 0 && days <= within {
+			items = append(items, i)
+		}
+	}
+	sort.SliceStable(items, func(a, b int) bool { return items[a].Expires.Before(items[b].Expires) })
+	return items
+}
+
+// Expired returns the items past their expiry date.
+func (inv Inventory) Expired(now time.Time) Inventory {
+	var items Inventory
+	for _, i := range inv {
+		if days, ok := i.DaysLeft(now); ok && days < 0 {
+			items = append(items, i)
+		}
+	}
+	return items
+}
+
+// Describe lists the items for a prompt, e.g. "2 lb chicken breast, 1 onion".
+func (inv Inventory) Describe() string {
+	var names []string
+	for _, i := range inv {
+		names = append(names, i.Describe())
+	}
+	return strings.Join(names, ", ")
+}
+
+// ParseDate reads a date written as YYYY-MM-DD, an empty date is zero.
+func ParseDate(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(DateLayout, s)
+}
+
+// FormatDate writes a date as YYYY-MM-DD, zero is empty.
+func FormatDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(DateLayout)
+}
@@ -0,0 +1,78 @@
+package pantry_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bahelit/ctrl_plus_revise/pkg/pantry"
+)
+
+var now = time.Date(2024, 10, 1, 18, 30, 0, 0, time.UTC)
+
+func day(offset int) time.Time {
+	return time.Date(2024, 10, 1+offset, 0, 0, 0, 0, time.UTC)
+}
+
+var inventory = pantry.Inventory{
+	{Name: "milk", Category: pantry.Dairy, Quantity: 0.5, Unit: "gal", Expires: day(3)},
+	{Name: "rice", Category: pantry.Pantry, Quantity: 2, Unit: "lb"},
+	{Name: "spinach", Category: pantry.Vegetables, Quantity: 1, Unit: "bag", Expires: day(1)},
+	{Name: "yogurt", Category: pantry.Dairy, Quantity: 4, Expires: day(-2)},
+	{Name: "chicken", Category: pantry.Protein, Quantity: 1.5, Unit: "lb", Expires: day(10)},
+	{Name: "salt", Category: pantry.HerbsSpices},
+}
+
+var expiryTable = []struct {
+	Item   pantry.Item
+	Expiry string
+}{
+	{inventory[0], "expires in 3 days"},
+	{inventory[1], ""},
+	{inventory[2], "expires tomorrow"},
+	{inventory[3], "expired"},
+	{pantry.Item{Expires: day(0)}, "expires today"},
+}
+
+func Test_Expiry(t *testing.T) {
+	for _, v := range expiryTable {
+		if got := v.Item.Expiry(now); got != v.Expiry {
+			t.Errorf("Expected %q for %s, received %q", v.Expiry, v.Item.Name, got)
+		}
+	}
+}
+
+func Test_ExpiringSoon(t *testing.T) {
+	soon := inventory.ExpiringSoon(now, 5)
+	if len(soon) != 2 || soon[0].Name != "spinach" || soon[1].Name != "milk" {
+		t.Errorf("Expected spinach and milk, received %v", soon.Describe())
+	}
+	if expired := inventory.Expired(now); len(expired) != 1 || expired[0].Name != "yogurt" {
+		t.Errorf("Expected yogurt to have expired, received %v", expired.Describe())
+	}
+}
+
+func Test_Describe(t *testing.T) {
+	got := inventory.InCategory(pantry.Dairy).Describe()
+	if want := "0.5 gal milk, 4 yogurt"; got != want {
+		t.Errorf("Expected %q, received %q", want, got)
+	}
+	if got = inventory[5].Describe(); got != "salt" {
+		t.Errorf("Expected salt without an amount, received %q", got)
+	}
+}
+
+func Test_Dates(t *testing.T) {
+	d, err := pantry.ParseDate("2024-10-04")
+	if err != nil || !d.Equal(day(3)) {
+		t.Errorf("Expected 2024-10-04, received %v, %v", d, err)
+	}
+	if d, err = pantry.ParseDate(" "); err != nil || !d.IsZero() {
+		t.Errorf("Expected an empty date to be zero, received %v, %v", d, err)
+	}
+	if _, err = pantry.ParseDate("10/04/2024"); err == nil {
+		t.Error("Expected an error for a date in another format")
+	}
+	if pantry.FormatDate(time.Time{}) != "" || pantry.FormatDate(day(3)) != "2024-10-04" {
+		t.Error("Unexpected formatted dates")
+	}
+}
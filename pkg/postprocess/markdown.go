@@ -0,0 +1,56 @@
+package postprocess
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	headingRe    = regexp.MustCompile(`^#{1,6}\s+`)
+	blockquoteRe = regexp.MustCompile(`^>\s?`)
+	bulletRe     = regexp.MustCompile(`^(\s*)[*+]\s+`)
+	ruleRe       = regexp.MustCompile(`^\s*(?:-{3,}|\*{3,}|_{3,})\s*$`)
+	imageRe      = regexp.MustCompile(`!\[([^\]]*)\]\([^)]*\)`)
+	linkRe       = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)[^)]*\)`)
+	boldRe       = regexp.MustCompile(`(\*\*|__)(\S(?:.*?\S)?)(\*\*|__)`)
+	italicRe     = regexp.MustCompile(`(^|[^\w*])[*_](\S(?:[^*_]*?\S)?)[*_]([^\w*]|$)`)
+	strikeRe     = regexp.MustCompile(`~~(.+?)~~`)
+	codeRe       = regexp.MustCompile("`([^`]+)`")
+)
+
+// ToPlainText removes Markdown formatting, links keep their address in
+// brackets and bullet lists use dashes.
+func ToPlainText(markdown string) string {
+	var lines []string
+	inFence := false
+	for _, line := range strings.Split(markdown, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			lines = append(lines, line)
+			continue
+		}
+		if ruleRe.MatchString(line) {
+			continue
+		}
+		line = headingRe.ReplaceAllString(line, "")
+		line = blockquoteRe.ReplaceAllString(line, "")
+		line = bulletRe.ReplaceAllString(line, "$1- ")
+		line = imageRe.ReplaceAllString(line, "$1")
+		line = linkRe.ReplaceAllStringFunc(line, func(link string) string {
+			m := linkRe.FindStringSubmatch(link)
+			if m[1] == m[2] {
+				return m[1]
+			}
+			return m[1] + " (" + m[2] + ")"
+		})
+		line = codeRe.ReplaceAllString(line, "$1")
+		line = boldRe.ReplaceAllString(line, "$2")
+		line = italicRe.ReplaceAllString(line, "$1$2$3")
+		line = strikeRe.ReplaceAllString(line, "$1")
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
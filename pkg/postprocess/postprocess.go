@@ -0,0 +1,97 @@
+// Package postprocess cleans up AI responses before they replace the
+// highlighted text, small models often add "Here is the revised text:" or wrap
+// the text in quotes even when the prompt asks them not to.
+package postprocess
+
+import (
+	"strings"
+)
+
+// Rule is one clean-up step of the pipeline.
+type Rule string
+
+const (
+	StripPreamble  Rule = "StripPreamble"
+	StripEpilogue  Rule = "StripEpilogue"
+	StripCodeFence Rule = "StripCodeFence"
+	StripQuotes    Rule = "StripQuotes"
+	MarkdownToText Rule = "MarkdownToText"
+	MatchSpacing   Rule = "MatchSpacing"
+)
+
+// Rules returns every rule in the order the pipeline applies them.
+func Rules() []Rule {
+	return []Rule{StripPreamble, StripEpilogue, StripCodeFence, StripQuotes, MarkdownToText, MatchSpacing}
+}
+
+// DefaultRules are used until the user picks their own, converting Markdown
+// is left out as it changes what the model meant to write.
+func DefaultRules() []Rule {
+	return []Rule{StripPreamble, StripEpilogue, StripCodeFence, StripQuotes, MatchSpacing}
+}
+
+// Description explains the rule to the user.
+func (r Rule) Description() string {
+	switch r {
+	case StripPreamble:
+		return "Remove openings such as \"Here is the revised text:\""
+	case StripEpilogue:
+		return "Remove closings such as \"I hope this helps!\" or notes about the changes"
+	case StripCodeFence:
+		return "Remove a code block wrapped around the whole response"
+	case StripQuotes:
+		return "Remove quotes wrapped around the whole response"
+	case MarkdownToText:
+		return "Convert Markdown formatting to plain text"
+	case MatchSpacing:
+		return "Keep the leading and trailing spaces and new lines of the highlighted text"
+	}
+	return string(r)
+}
+
+// rules are given the original text and the response and return the cleaned
+// response.
+var rules = map[Rule]func(original, response string) string{
+	StripPreamble:  stripPreamble,
+	StripEpilogue:  stripEpilogue,
+	StripCodeFence: stripCodeFence,
+	StripQuotes:    stripQuotes,
+	MarkdownToText: func(_, response string) string { return ToPlainText(response) },
+	MatchSpacing:   matchSpacing,
+}
+
+// Apply runs the enabled rules on the response, in the order of Rules.
+func Apply(enabled []Rule, original, response string) string {
+	for _, r := range Rules() {
+		if !contains(enabled, r) {
+			continue
+		}
+		cleaned := rules[r](original, response)
+		if strings.TrimSpace(cleaned) == "" {
+			// Never throw away the whole response
+			continue
+		}
+		response = cleaned
+	}
+	return response
+}
+
+// ParseRules reads rules saved as strings, unknown rules are ignored.
+func ParseRules(names []string) []Rule {
+	var parsed []Rule
+	for _, name := range names {
+		if _, ok := rules[Rule(name)]; ok {
+			parsed = append(parsed, Rule(name))
+		}
+	}
+	return parsed
+}
+
+func contains(enabled []Rule, r Rule) bool {
+	for _, e := range enabled {
+		if e == r {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,121 @@
+package postprocess_test
+
+import (
+	"testing"
+
+	"github.com/bahelit/ctrl_plus_revise/pkg/postprocess"
+)
+
+type ruleCase struct {
+	Name     string
+	Original string
+	Response string
+	Expected string
+}
+
+func runRule(t *testing.T, rule postprocess.Rule, table []ruleCase) {
+	t.Helper()
+	for _, v := range table {
+		got := postprocess.Apply([]postprocess.Rule{rule}, v.Original, v.Response)
+		if got != v.Expected {
+			t.Errorf("%s: expected %q, received %q", v.Name, v.Expected, got)
+		}
+	}
+}
+
+var preambleTable = []ruleCase{
+	{"colon", "their going", "Here is the revised text:\nThey're going", "They're going"},
+	{"sure", "their going", "Sure! Here's the corrected version:\n\nThey're going", "They're going"},
+	{"no colon", "their going", "Here is the corrected text.\nThey're going", "They're going"},
+	{"label", "their going", "Corrected text:\nThey're going", "They're going"},
+	{"single line", "their going", "Here is the revised text:", "Here is the revised text:"},
+	{"real sentence", "here is my plan\nwe go", "Here is my plan.\nWe go.", "Here is my plan.\nWe go."},
+	{"same opening", "Here is the list:\n- a", "Here is the list:\n- a", "Here is the list:\n- a"},
+}
+
+func Test_StripPreamble(t *testing.T) {
+	runRule(t, postprocess.StripPreamble, preambleTable)
+}
+
+var epilogueTable = []ruleCase{
+	{"changes", "their going", "They're going.\n\nI corrected \"their\" to \"they're\".", "They're going."},
+	{"i have", "their going", "They're going.\n\nI have made the following changes: fixed the verb.", "They're going."},
+	{"hope", "their going", "They're going.\n\n---\n\nI hope this helps!", "They're going."},
+	{"note", "their going", "They're going.\n\n(Note: the meaning is unchanged.)", "They're going."},
+	{"let me know", "hi", "Hello.\n\nLet me know if you want another tone.", "Hello."},
+	{"real paragraph", "a\n\nb", "First.\n\nSecond paragraph.", "First.\n\nSecond paragraph."},
+	{"same ending", "Thanks.\n\nLet me know if you have questions.", "Thanks!\n\nLet me know if you have any questions.", "Thanks!\n\nLet me know if you have any questions."},
+	{"one paragraph", "hi", "I hope this helps", "I hope this helps"},
+}
+
+func Test_StripEpilogue(t *testing.T) {
+	runRule(t, postprocess.StripEpilogue, epilogueTable)
+}
+
+var fenceTable = []ruleCase{
+	{"plain", "their going", "```\nThey're going\n```", "They're going"},
+	{"language", "their going", "```text\nThey're going\nnow\n```", "They're going\nnow"},
+	{"original code", "```go\nx:=1\n```", "```go\nx := 1\n```", "```go\nx := 1\n```"},
+	{"partial", "text", "Use this:\n```\ncode\n```", "Use this:\n```\ncode\n```"},
+}
+
+func Test_StripCodeFence(t *testing.T) {
+	runRule(t, postprocess.StripCodeFence, fenceTable)
+}
+
+var quotesTable = []ruleCase{
+	{"double", "their going", `"They're going"`, "They're going"},
+	{"curly", "their going", "“They're going”", "They're going"},
+	{"guillemets", "ils vont", "«Ils vont»", "Ils vont"},
+	{"quoted original", `"their going"`, `"They're going"`, `"They're going"`},
+	{"two quotes", "one and two", `"One" and "two"`, `"One" and "two"`},
+	{"quoted original words", `He said "hi" and left`, `"Hi," he said, "and bye."`, `"Hi," he said, "and bye."`},
+	{"not wrapped", "go", `He said "go"`, `He said "go"`},
+}
+
+func Test_StripQuotes(t *testing.T) {
+	runRule(t, postprocess.StripQuotes, quotesTable)
+}
+
+var spacingTable = []ruleCase{
+	{"trailing newline", "their going\n", "They're going", "They're going\n"},
+	{"no newline", "their going", "They're going\n\n", "They're going"},
+	{"leading", "  indented\n", "\nIndented ", "  Indented\n"},
+}
+
+func Test_MatchSpacing(t *testing.T) {
+	runRule(t, postprocess.MatchSpacing, spacingTable)
+}
+
+var markdownTable = []ruleCase{
+	{"heading", "", "## Title\nText", "Title\nText"},
+	{"emphasis", "", "This is **bold**, *italic*, __strong__ and ~~gone~~", "This is bold, italic, strong and gone"},
+	{"snake case", "", "Set my_var_name to `true`", "Set my_var_name to true"},
+	{"link", "", "See [the docs](https://example.com) and [https://a.b](https://a.b)", "See the docs (https://example.com) and https://a.b"},
+	{"image", "", "![logo](logo.png)", "logo"},
+	{"bullets", "", "* one\n  + two\n- three", "- one\n  - two\n- three"},
+	{"quote and rule", "", "> quoted\n\n---\nafter", "quoted\n\nafter"},
+	{"fence", "", "```go\nx := *p\n```", "x := *p"},
+}
+
+func Test_MarkdownToText(t *testing.T) {
+	runRule(t, postprocess.MarkdownToText, markdownTable)
+}
+
+func Test_Apply(t *testing.T) {
+	response := "Sure! Here is the revised text:\n\n\"They're going to the park.\"\n\nI hope this helps!"
+	got := postprocess.Apply(postprocess.DefaultRules(), "their going to the park\n", response)
+	if want := "They're going to the park.\n"; got != want {
+		t.Errorf("Expected %q, received %q", want, got)
+	}
+	if got = postprocess.Apply(nil, "a", response); got != response {
+		t.Errorf("Expected no changes without rules, received %q", got)
+	}
+}
+
+func Test_ParseRules(t *testing.T) {
+	rules := postprocess.ParseRules([]string{"StripQuotes", "Unknown", "MatchSpacing"})
+	if len(rules) != 2 || rules[0] != postprocess.StripQuotes || rules[1] != postprocess.MatchSpacing {
+		t.Errorf("Unexpected rules %v", rules)
+	}
+}
@@ -0,0 +1,113 @@
+package postprocess
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var (
+	// preambleRe matches an opening line announcing the result, it has to end
+	// with a colon or mention what kind of text follows.
+	preambleRe = regexp.MustCompile(`(?i)^(?:(?:sure|certainly|of course|okay|ok|absolutely|great)[!,.]*\s*)?` +
+		`(?:(?:here(?:'s| is| are)|below is|this is)\b.*:|(?:here(?:'s| is| are)|below is)\b.*\b(?:revised|corrected|rewritten|improved|edited|translated|translation|version|text|summary|list|headline)\b.*[.:!]?|` +
+		`(?:the )?(?:revised|corrected|rewritten|improved|edited|translated|updated|professional|friendly) (?:text|version|sentence|paragraph|message)\s*:)\s*$`)
+	// epilogueRe matches a closing paragraph commenting on the result.
+	epilogueRe = regexp.MustCompile(`(?i)^\(?(?:note:|i(?: have|'ve)? (?:made|corrected|changed|fixed|revised|rewritten|rewrote|replaced|kept)\b|` +
+		`(?:the )?changes (?:i made|made)|let me know|i hope (?:this|that) helps|feel free to|this (?:revised|corrected|rewritten) (?:text|version)|` +
+		`(?:the )?(?:above|revised|corrected) (?:text|version) (?:is|has|maintains|keeps))`)
+	fenceRe = regexp.MustCompile("(?s)^```[\\w+-]*[ \\t]*\\n(.*?)\\n?```$")
+)
+
+// quotePairs are the quotes models wrap responses with.
+var quotePairs = [][2]string{{`"`, `"`}, {"“", "”"}, {"'", "'"}, {"‘", "’"}, {"«", "»"}, {"„", "“"}}
+
+// stripPreamble removes the first line when it only announces the result and
+// the original text doesn't start the same way.
+func stripPreamble(original, response string) string {
+	trimmed := strings.TrimSpace(response)
+	first, rest, found := strings.Cut(trimmed, "\n")
+	if !found || len(first) > 160 || !preambleRe.MatchString(strings.TrimSpace(first)) {
+		return response
+	}
+	if preambleRe.MatchString(firstLine(original)) {
+		return response
+	}
+	return strings.TrimSpace(rest)
+}
+
+// stripEpilogue removes the last paragraph when it comments on the result and
+// the original text doesn't end the same way.
+func stripEpilogue(original, response string) string {
+	trimmed := strings.TrimSpace(response)
+	i := strings.LastIndex(trimmed, "\n\n")
+	if i < 0 {
+		return response
+	}
+	last := strings.TrimSpace(trimmed[i:])
+	if !epilogueRe.MatchString(last) || epilogueRe.MatchString(lastParagraph(original)) {
+		return response
+	}
+	// Remove separators left above the comment, e.g. "---"
+	body := strings.TrimRightFunc(trimmed[:i], unicode.IsSpace)
+	body = strings.TrimSuffix(body, "---")
+	return strings.TrimSpace(body)
+}
+
+// stripCodeFence removes a code block wrapped around the whole response when
+// the original text isn't a code block.
+func stripCodeFence(original, response string) string {
+	if strings.HasPrefix(strings.TrimSpace(original), "```") {
+		return response
+	}
+	match := fenceRe.FindStringSubmatch(strings.TrimSpace(response))
+	if match == nil {
+		return response
+	}
+	return match[1]
+}
+
+// stripQuotes removes quotes wrapped around the whole response when the
+// original text isn't quoted the same way.
+func stripQuotes(original, response string) string {
+	trimmed := strings.TrimSpace(response)
+	o := strings.TrimSpace(original)
+	for _, pair := range quotePairs {
+		open, closing := pair[0], pair[1]
+		if !strings.HasPrefix(trimmed, open) || !strings.HasSuffix(trimmed, closing) || len(trimmed) < len(open)+len(closing) {
+			continue
+		}
+		if strings.HasPrefix(o, open) && strings.HasSuffix(o, closing) {
+			return response
+		}
+		inner := trimmed[len(open) : len(trimmed)-len(closing)]
+		if open == closing && strings.Contains(inner, open) {
+			// "One" and "two" isn't wrapped in quotes
+			return response
+		}
+		return inner
+	}
+	return response
+}
+
+// matchSpacing gives the response the leading and trailing whitespace of the
+// original, so pasting over a line keeps its new line.
+func matchSpacing(original, response string) string {
+	body := strings.TrimSpace(response)
+	lead := original[:len(original)-len(strings.TrimLeftFunc(original, unicode.IsSpace))]
+	trail := original[len(strings.TrimRightFunc(original, unicode.IsSpace)):]
+	return lead + body + trail
+}
+
+func firstLine(text string) string {
+	first, _, _ := strings.Cut(strings.TrimSpace(text), "\n")
+	return strings.TrimSpace(first)
+}
+
+func lastParagraph(text string) string {
+	text = strings.TrimSpace(text)
+	if i := strings.LastIndex(text, "\n\n"); i >= 0 {
+		return strings.TrimSpace(text[i:])
+	}
+	return text
+}
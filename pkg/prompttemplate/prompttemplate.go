@@ -0,0 +1,141 @@
+// Package prompttemplate renders user-written prompts with text/template, e.g.
+//
+//	Rewrite the following text in {{.Language}} for {{.AppName}}: {{.Selection}}
+//
+// Custom variables are read with {{.Vars.name}}.
+package prompttemplate
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// ErrNoSelection is returned by Validate for a template that never uses the
+// highlighted text.
+var ErrNoSelection = errors.New("the template must use {{.Selection}}")
+
+// Data is what a template can use.
+type Data struct {
+	// Selection is the highlighted text.
+	Selection string
+	// Language is the language the user translates to.
+	Language string
+	// AppName is the application the text was highlighted in.
+	AppName string
+	// Clipboard is what was in the clipboard before the text was copied.
+	Clipboard string
+	// Date is today's date, e.g. 2024-09-30.
+	Date string
+	// Vars are the user's own variables.
+	Vars map[string]string
+}
+
+// Variable documents a value available to templates.
+type Variable struct {
+	Name        string
+	Description string
+}
+
+// Variables returns the documented variables, in the order they are listed
+// to the user.
+func Variables() []Variable {
+	return []Variable{
+		{"{{.Selection}}", "The highlighted text"},
+		{"{{.Language}}", "The language translations are made to"},
+		{"{{.AppName}}", "The application the text was highlighted in"},
+		{"{{.Clipboard}}", "What was in the clipboard before the text was copied"},
+		{"{{.Date}}", "Today's date, e.g. " + time.Now().Format(time.DateOnly)},
+		{"{{.Vars.name}}", "Your own variable called name"},
+		{"{{upper .Selection}}", "Also lower and trim"},
+	}
+}
+
+// Today returns Date for the current day.
+func Today() string {
+	return time.Now().Format(time.DateOnly)
+}
+
+var funcs = template.FuncMap{
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"trim":  strings.TrimSpace,
+}
+
+// Template is a parsed prompt template.
+type Template struct {
+	tmpl *template.Template
+}
+
+// Parse reads the template, unknown variables are an error when rendering.
+func Parse(name, text string) (*Template, error) {
+	tmpl, err := template.New(name).Funcs(funcs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	return &Template{tmpl: tmpl}, nil
+}
+
+// Render fills in the template.
+func (t *Template) Render(data Data) (string, error) {
+	var b bytes.Buffer
+	if err := t.tmpl.Execute(&b, data); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// Validate checks a template before it is saved: it has to parse, use the
+// selection and only use known variables and the custom variables in vars.
+func Validate(text string, vars map[string]string) error {
+	t, err := Parse("validate", text)
+	if err != nil {
+		return err
+	}
+	// The marker has no letters so upper and lower keep it intact
+	const marker = "\x00§\x00"
+	rendered, err := t.Render(Sample(marker, vars))
+	if err != nil {
+		return err
+	}
+	if !strings.Contains(rendered, marker) {
+		return ErrNoSelection
+	}
+	return nil
+}
+
+// Sample returns data for previewing a template against sample text.
+func Sample(selection string, vars map[string]string) Data {
+	if vars == nil {
+		vars = map[string]string{}
+	}
+	return Data{
+		Selection: selection,
+		Language:  "Spanish",
+		AppName:   "Text Editor",
+		Clipboard: "Text copied earlier",
+		Date:      Today(),
+		Vars:      vars,
+	}
+}
+
+// ParseVars reads custom variables written one per line as name=value.
+func ParseVars(text string) (map[string]string, error) {
+	vars := map[string]string{}
+	for i, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, value, ok := strings.Cut(line, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" || strings.ContainsAny(name, " .{}") {
+			return nil, fmt.Errorf("line %d: expected name=value", i+1)
+		}
+		vars[name] = strings.TrimSpace(value)
+	}
+	return vars, nil
+}
@@ -0,0 +1,70 @@
+package prompttemplate_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bahelit/ctrl_plus_revise/pkg/prompttemplate"
+)
+
+func Test_Render(t *testing.T) {
+	tmpl, err := prompttemplate.Parse("test", "Write to {{.Vars.boss}} in {{.Language}} from {{.AppName}} on {{.Date}}: {{trim .Selection}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := tmpl.Render(prompttemplate.Data{
+		Selection: "  hello  ",
+		Language:  "French",
+		AppName:   "Slack",
+		Date:      "2024-09-30",
+		Vars:      map[string]string{"boss": "Sam"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "Write to Sam in French from Slack on 2024-09-30: hello"
+	if got != want {
+		t.Errorf("Expected %q, received %q", want, got)
+	}
+}
+
+var validateTable = []struct {
+	Text  string
+	Valid bool
+}{
+	{"Fix the grammar: {{.Selection}}", true},
+	{"Fix the grammar for {{.Vars.team}}: {{upper .Selection}}", true},
+	{"Fix the grammar: {{.Selection}", false},
+	{"Fix the grammar: {{.Selecton}}", false},
+	{"Fix the grammar for {{.Vars.unknown}}: {{.Selection}}", false},
+	{"Fix the grammar: {{.Clipboard}}", false},
+}
+
+func Test_Validate(t *testing.T) {
+	vars := map[string]string{"team": "support"}
+	for _, v := range validateTable {
+		err := prompttemplate.Validate(v.Text, vars)
+		if (err == nil) != v.Valid {
+			t.Errorf("Expected valid=%v for %q, received %v", v.Valid, v.Text, err)
+		}
+	}
+	if err := prompttemplate.Validate("No selection", nil); !errors.Is(err, prompttemplate.ErrNoSelection) {
+		t.Errorf("Expected ErrNoSelection, received %v", err)
+	}
+}
+
+func Test_ParseVars(t *testing.T) {
+	vars, err := prompttemplate.ParseVars("# team details\nteam = support\n\nsignature=Sam, Support Lead\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vars["team"] != "support" || vars["signature"] != "Sam, Support Lead" || len(vars) != 2 {
+		t.Errorf("Unexpected variables %v", vars)
+	}
+	if _, err = prompttemplate.ParseVars("not a variable"); err == nil {
+		t.Error("Expected an error for a line without =")
+	}
+	if _, err = prompttemplate.ParseVars("my var=1"); err == nil {
+		t.Error("Expected an error for a name with a space")
+	}
+}
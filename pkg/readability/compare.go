@@ -0,0 +1,71 @@
+package readability
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Trend says whether a change of a metric makes the text easier to read.
+type Trend int
+
+const (
+	// Unchanged is also used for counts, which are neither easier nor harder.
+	Unchanged Trend = iota
+	Easier
+	Harder
+)
+
+// Row compares one metric of two versions of a text.
+type Row struct {
+	Name   string
+	Before string
+	After  string
+	Trend  Trend
+}
+
+// Compare lists the metrics of a text before and after a revision.
+func Compare(before, after Metrics) []Row {
+	return []Row{
+		{"Words", strconv.Itoa(before.Words), strconv.Itoa(after.Words), Unchanged},
+		{"Sentences", strconv.Itoa(before.Sentences), strconv.Itoa(after.Sentences), Unchanged},
+		number("Words per sentence", before.AverageSentenceLength(), after.AverageSentenceLength(), -1),
+		number("Reading ease", before.ReadingEase(), after.ReadingEase(), 1),
+		number("Grade level", before.Grade(), after.Grade(), -1),
+		ratio("Passive voice", before.PassiveRatio(), after.PassiveRatio(), -1),
+		{"Reading time", FormatDuration(before.ReadingTime()), FormatDuration(after.ReadingTime()), Unchanged},
+	}
+}
+
+// Describe lists the metrics of a single text the same way.
+func Describe(m Metrics) []Row {
+	rows := Compare(m, m)
+	for i := range rows {
+		rows[i].Before = ""
+	}
+	return rows
+}
+
+// number compares a score, easier is 1 when a higher score is easier to
+// read and -1 when a lower one is.
+func number(name string, before, after, easier float64) Row {
+	b, a := fmt.Sprintf("%.1f", before), fmt.Sprintf("%.1f", after)
+	return Row{name, b, a, trend(b, a, before, after, easier)}
+}
+
+// ratio compares a share shown as a percentage.
+func ratio(name string, before, after, easier float64) Row {
+	b, a := fmt.Sprintf("%.0f%%", before*100), fmt.Sprintf("%.0f%%", after*100)
+	return Row{name, b, a, trend(b, a, before, after, easier)}
+}
+
+// trend compares the values as they are shown, so a change that rounds
+// away is unchanged.
+func trend(shownBefore, shownAfter string, before, after, easier float64) Trend {
+	switch {
+	case shownBefore == shownAfter:
+		return Unchanged
+	case (after-before)*easier > 0:
+		return Easier
+	}
+	return Harder
+}
@@ -0,0 +1,61 @@
+package readability
+
+import "strings"
+
+// toBe are the forms of "to be" and "to get" that make the passive voice.
+var toBe = map[string]bool{
+	"am": true, "is": true, "are": true, "was": true, "were": true,
+	"be": true, "been": true, "being": true, "isn't": true, "aren't": true,
+	"wasn't": true, "weren't": true, "get": true, "gets": true, "got": true, "gotten": true,
+}
+
+// irregular are past participles that don't end in -ed.
+var irregular = map[string]bool{
+	"become": true, "begun": true, "bitten": true, "blown": true, "born": true,
+	"bought": true, "broken": true, "brought": true, "built": true, "caught": true,
+	"chosen": true, "cut": true, "dealt": true, "done": true, "drawn": true,
+	"driven": true, "eaten": true, "fed": true, "felt": true, "forgiven": true,
+	"forgotten": true, "found": true, "frozen": true, "given": true, "grown": true,
+	"heard": true, "held": true, "hidden": true, "hit": true, "hung": true,
+	"hurt": true, "kept": true, "known": true, "laid": true, "led": true,
+	"left": true, "lent": true, "lost": true, "made": true, "meant": true,
+	"met": true, "paid": true, "put": true, "read": true, "ridden": true,
+	"run": true, "said": true, "seen": true, "sent": true, "set": true,
+	"shaken": true, "shown": true, "shut": true, "sold": true, "spent": true,
+	"spoken": true, "stolen": true, "struck": true, "taken": true, "taught": true,
+	"thought": true, "thrown": true, "told": true, "torn": true, "understood": true,
+	"won": true, "worn": true, "written": true,
+}
+
+// notParticiples end in -ed without being verbs.
+var notParticiples = map[string]bool{
+	"bed": true, "red": true, "need": true, "seed": true, "speed": true,
+	"feed": true, "shed": true, "hundred": true, "sacred": true, "naked": true,
+	"wicked": true, "indeed": true, "tired": true, "interested": true,
+}
+
+// isPassive reports whether the words have a form of "to be" followed by a
+// past participle, e.g. "the report was written" or "it is usually done".
+func isPassive(words []string) bool {
+	for i, w := range words {
+		if !toBe[w] {
+			continue
+		}
+		for _, next := range words[i+1 : min(i+3, len(words))] {
+			if isParticiple(next) {
+				return true
+			}
+			if !strings.HasSuffix(next, "ly") && next != "not" && next != "never" {
+				break
+			}
+		}
+	}
+	return false
+}
+
+func isParticiple(word string) bool {
+	if irregular[word] {
+		return true
+	}
+	return len(word) > 3 && strings.HasSuffix(word, "ed") && !notParticiples[word]
+}
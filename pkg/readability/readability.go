@@ -0,0 +1,166 @@
+// Package readability measures how easy an English text is to read: word and
+// sentence counts, the Flesch reading ease and Flesch-Kincaid grade, the
+// share of sentences in the passive voice and the reading time.
+package readability
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// WordsPerMinute is the average silent reading speed of adults reading
+// non-fiction.
+const WordsPerMinute = 238
+
+var (
+	sentenceEnd = regexp.MustCompile(`[.!?。！？]+["')\]]*(\s+|$)`)
+	// lineBreak ends a sentence at blank lines, headings and list items
+	// which often have no punctuation.
+	lineBreak = regexp.MustCompile(`\n[ \t]*(\n\s*|[-*+#>]+\s+|\d+[.)]\s+)`)
+	vowels    = regexp.MustCompile(`[aeiouy]+`)
+)
+
+// Metrics describes a text.
+type Metrics struct {
+	Words     int
+	Sentences int
+	Syllables int
+	// Passive is the number of sentences in the passive voice.
+	Passive int
+}
+
+// Analyze measures the text.
+func Analyze(text string) Metrics {
+	var m Metrics
+	for _, sentence := range sentences(text) {
+		words := words(sentence)
+		if len(words) == 0 {
+			continue
+		}
+		m.Sentences++
+		m.Words += len(words)
+		for _, w := range words {
+			m.Syllables += Syllables(w)
+		}
+		if isPassive(words) {
+			m.Passive++
+		}
+	}
+	return m
+}
+
+// AverageSentenceLength is the number of words per sentence.
+func (m Metrics) AverageSentenceLength() float64 {
+	if m.Sentences == 0 {
+		return 0
+	}
+	return float64(m.Words) / float64(m.Sentences)
+}
+
+func (m Metrics) syllablesPerWord() float64 {
+	if m.Words == 0 {
+		return 0
+	}
+	return float64(m.Syllables) / float64(m.Words)
+}
+
+// ReadingEase is the Flesch reading ease, 60 to 70 is plain English, higher
+// is easier.
+func (m Metrics) ReadingEase() float64 {
+	if m.Words == 0 {
+		return 0
+	}
+	return 206.835 - 1.015*m.AverageSentenceLength() - 84.6*m.syllablesPerWord()
+}
+
+// Grade is the Flesch-Kincaid grade level, the US school grade that can
+// read the text.
+func (m Metrics) Grade() float64 {
+	if m.Words == 0 {
+		return 0
+	}
+	return math.Max(0, 0.39*m.AverageSentenceLength()+11.8*m.syllablesPerWord()-15.59)
+}
+
+// PassiveRatio is the share of sentences in the passive voice, from 0 to 1.
+func (m Metrics) PassiveRatio() float64 {
+	if m.Sentences == 0 {
+		return 0
+	}
+	return float64(m.Passive) / float64(m.Sentences)
+}
+
+// ReadingTime is how long the text takes to read, to the second.
+func (m Metrics) ReadingTime() time.Duration {
+	return (time.Duration(m.Words) * time.Minute / WordsPerMinute).Round(time.Second)
+}
+
+// Summary describes the metrics in one line, e.g.
+// "120 words, 6 sentences, grade 8.2, 30s to read".
+func (m Metrics) Summary() string {
+	return fmt.Sprintf("%d words, %d sentences, grade %.1f, %s to read",
+		m.Words, m.Sentences, m.Grade(), FormatDuration(m.ReadingTime()))
+}
+
+// FormatDuration writes a reading time, e.g. "45s" or "3m 10s".
+func FormatDuration(d time.Duration) string {
+	if d < time.Minute {
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	}
+	minutes, seconds := int(d.Minutes()), int(d.Seconds())%60
+	if seconds == 0 {
+		return fmt.Sprintf("%dm", minutes)
+	}
+	return fmt.Sprintf("%dm %ds", minutes, seconds)
+}
+
+func sentences(text string) []string {
+	var all []string
+	for _, block := range lineBreak.Split(text, -1) {
+		all = append(all, sentenceEnd.Split(block, -1)...)
+	}
+	return all
+}
+
+// words returns the lowercase words of the text, numbers and symbols aren't
+// words.
+func words(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\'' && r != '’'
+	})
+}
+
+// Syllables estimates the syllables of an English word from its vowel groups.
+func Syllables(word string) int {
+	word = strings.Trim(strings.ToLower(word), "'’")
+	if word == "" {
+		return 0
+	}
+	if len(word) <= 3 {
+		return 1
+	}
+	stem := word
+	switch {
+	case strings.HasSuffix(stem, "es") && !hasAnySuffix(stem, "ses", "zes", "xes", "ces", "ges", "ches", "shes"),
+		strings.HasSuffix(stem, "ed") && !hasAnySuffix(stem, "ted", "ded"):
+		stem = stem[:len(stem)-2]
+	case strings.HasSuffix(stem, "e") && !strings.HasSuffix(stem, "le"):
+		stem = stem[:len(stem)-1]
+	}
+	// a leading y is a consonant
+	count := len(vowels.FindAllString(strings.TrimPrefix(stem, "y"), -1))
+	return max(count, 1)
+}
+
+func hasAnySuffix(s string, suffixes ...string) bool {
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(s, suffix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,156 @@
+package readability_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/bahelit/ctrl_plus_revise/pkg/readability"
+)
+
+func TestSyllables(t *testing.T) {
+	tests := []struct {
+		word string
+		want int
+	}{
+		{"the", 1},
+		{"cat", 1},
+		{"make", 1},
+		{"makes", 1},
+		{"table", 2},
+		{"walked", 1},
+		{"wanted", 2},
+		{"boxes", 2},
+		{"places", 2},
+		{"yellow", 2},
+		{"readability", 5},
+		{"beautiful", 3},
+		{"don't", 1},
+		{"", 0},
+	}
+	for _, tt := range tests {
+		if got := readability.Syllables(tt.word); got != tt.want {
+			t.Errorf("Syllables(%q) = %d, want %d", tt.word, got, tt.want)
+		}
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want readability.Metrics
+	}{
+		{"empty", "  ", readability.Metrics{}},
+		{"numbers are not words", "42 + 7 = 49", readability.Metrics{}},
+		{
+			name: "sentences",
+			text: "The cat sat on the mat. Was it happy? Yes!",
+			want: readability.Metrics{Words: 10, Sentences: 3, Syllables: 11},
+		},
+		{
+			name: "passive",
+			text: "The report was written by Sam. Sam wrote the report. The code is not tested.",
+			want: readability.Metrics{Words: 15, Sentences: 3, Syllables: 19, Passive: 2},
+		},
+		{
+			name: "list items without punctuation",
+			text: "# Shopping\n- eggs\n- milk\n\nBuy them today",
+			want: readability.Metrics{Words: 6, Sentences: 4, Syllables: 8},
+		},
+		{
+			name: "wrapped lines stay one sentence",
+			text: "This sentence is wrapped\nover two lines.",
+			want: readability.Metrics{Words: 7, Sentences: 1, Syllables: 9, Passive: 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := readability.Analyze(tt.text); got != tt.want {
+				t.Errorf("Analyze() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScores(t *testing.T) {
+	m := readability.Metrics{Words: 100, Sentences: 5, Syllables: 150, Passive: 1}
+	tests := []struct {
+		name      string
+		got, want float64
+	}{
+		{"average sentence length", m.AverageSentenceLength(), 20},
+		{"reading ease", m.ReadingEase(), 206.835 - 1.015*20 - 84.6*1.5},
+		{"grade", m.Grade(), 0.39*20 + 11.8*1.5 - 15.59},
+		{"passive ratio", m.PassiveRatio(), 0.2},
+		{"empty grade", readability.Metrics{}.Grade(), 0},
+		{"easy text has no negative grade", readability.Metrics{Words: 3, Sentences: 3, Syllables: 3}.Grade(), 0},
+	}
+	for _, tt := range tests {
+		if math.Abs(tt.got-tt.want) > 1e-9 {
+			t.Errorf("%s = %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestReadingTime(t *testing.T) {
+	tests := []struct {
+		words int
+		want  string
+	}{
+		{0, "0s"},
+		{119, "30s"},
+		{238, "1m"},
+		{600, "2m 31s"},
+	}
+	for _, tt := range tests {
+		got := readability.FormatDuration(readability.Metrics{Words: tt.words}.ReadingTime())
+		if got != tt.want {
+			t.Errorf("reading time of %d words = %s, want %s", tt.words, got, tt.want)
+		}
+	}
+	if got := readability.FormatDuration(90 * time.Second); got != "1m 30s" {
+		t.Errorf("FormatDuration() = %s", got)
+	}
+}
+
+func TestSummary(t *testing.T) {
+	got := readability.Metrics{Words: 100, Sentences: 5, Syllables: 150}.Summary()
+	if want := "100 words, 5 sentences, grade 9.9, 25s to read"; got != want {
+		t.Errorf("Summary() = %q, want %q", got, want)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	before := readability.Analyze("The decision was made by the committee after the extraordinarily lengthy deliberations had concluded.")
+	after := readability.Analyze("The committee decided. The talks were long.")
+	rows := readability.Compare(before, after)
+
+	want := map[string]readability.Trend{
+		"Words":              readability.Unchanged,
+		"Words per sentence": readability.Easier,
+		"Reading ease":       readability.Easier,
+		"Grade level":        readability.Easier,
+		"Passive voice":      readability.Easier,
+		"Reading time":       readability.Unchanged,
+	}
+	for _, row := range rows {
+		if trend, ok := want[row.Name]; ok && row.Trend != trend {
+			t.Errorf("%s: %s → %s is %v, want %v", row.Name, row.Before, row.After, row.Trend, trend)
+		}
+	}
+	if rows[5].Before != "100%" || rows[5].After != "0%" {
+		t.Errorf("Passive voice = %s → %s, want 100%% → 0%%", rows[5].Before, rows[5].After)
+	}
+
+	for _, row := range readability.Compare(after, after) {
+		if row.Trend != readability.Unchanged {
+			t.Errorf("%s changed comparing a text with itself", row.Name)
+		}
+	}
+	for _, row := range readability.Describe(after) {
+		if row.Before != "" || row.After == "" {
+			t.Errorf("Describe() row %+v", row)
+		}
+	}
+}
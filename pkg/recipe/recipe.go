@@ -0,0 +1,194 @@
+// Package recipe holds the recipes the meal planner asks the AI for, they can
+// be scaled to another number of servings and written out as Markdown or
+// plain text.
+package recipe
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxRating is the number of stars of a five star rating.
+const MaxRating = 5
+
+// Ingredient is one line of the ingredient list.
+type Ingredient struct {
+	Name   string  `json:"name" desc:"The ingredient, e.g. chicken breast"`
+	Amount float64 `json:"amount" desc:"How much for the servings of the recipe, 0 for to taste"`
+	Unit   string  `json:"unit" desc:"The unit of the amount, e.g. g, cup or tbsp, empty for whole items"`
+}
+
+// Recipe is a recipe, the fields without a JSON name are kept by the recipe
+// book and not asked from the AI.
+type Recipe struct {
+	ID           *int64       `json:"-"`
+	Title        string       `json:"title"`
+	Servings     int          `json:"servings" desc:"How many people the recipe feeds"`
+	Ingredients  []Ingredient `json:"ingredients"`
+	Steps        []string     `json:"steps" desc:"The instructions, one step per item without numbering"`
+	PrepMinutes  int          `json:"prep_minutes"`
+	CookMinutes  int          `json:"cook_minutes"`
+	CostEstimate string       `json:"cost_estimate" desc:"The estimated cost of the ingredients, e.g. $12"`
+	Rating       int          `json:"-"`
+	Notes        string       `json:"-"`
+	Saved        time.Time    `json:"-"`
+}
+
+// TotalMinutes is the prep and cook time.
+func (r Recipe) TotalMinutes() int {
+	return r.PrepMinutes + r.CookMinutes
+}
+
+// Scale returns the recipe for the number of servings, the amounts of the
+// ingredients change with it. Recipes without servings are returned as is.
+func (r Recipe) Scale(servings int) Recipe {
+	if r.Servings <= 0 || servings <= 0 || servings == r.Servings {
+		return r
+	}
+	factor := float64(servings) / float64(r.Servings)
+	scaled := r
+	scaled.Servings = servings
+	scaled.Ingredients = make([]Ingredient, len(r.Ingredients))
+	for i, ingredient := range r.Ingredients {
+		ingredient.Amount *= factor
+		scaled.Ingredients[i] = ingredient
+	}
+	return scaled
+}
+
+// Matches reports whether the query is in the title, an ingredient or the
+// notes, ignoring case. Every word of the query has to match.
+func (r Recipe) Matches(query string) bool {
+	var b strings.Builder
+	b.WriteString(r.Title + "\n" + r.Notes)
+	for _, ingredient := range r.Ingredients {
+		b.WriteString("\n" + ingredient.Name)
+	}
+	text := strings.ToLower(b.String())
+	for _, word := range strings.Fields(strings.ToLower(query)) {
+		if !strings.Contains(text, word) {
+			return false
+		}
+	}
+	return true
+}
+
+// Stars shows the rating, e.g. "★★★☆☆".
+func (r Recipe) Stars() string {
+	rating := min(max(r.Rating, 0), MaxRating)
+	return strings.Repeat("★", rating) + strings.Repeat("☆", MaxRating-rating)
+}
+
+// Describe returns the ingredient for a list, e.g. "1 1/2 cup rice".
+func (i Ingredient) Describe() string {
+	amount := FormatAmount(i.Amount)
+	switch {
+	case amount == "":
+		return i.Name
+	case i.Unit == "":
+		return amount + " " + i.Name
+	}
+	return amount + " " + i.Unit + " " + i.Name
+}
+
+// Markdown writes the recipe out for the result tab.
+func (r Recipe) Markdown() string {
+	var b strings.Builder
+	b.WriteString("# " + r.Title + "\n\n")
+	b.WriteString(r.summary(" | ") + "\n\n")
+	b.WriteString("## Ingredients\n\n")
+	for _, ingredient := range r.Ingredients {
+		b.WriteString("- " + ingredient.Describe() + "\n")
+	}
+	b.WriteString("\n## Steps\n\n")
+	for i, step := range r.Steps {
+		fmt.Fprintf(&b, "%d. %s\n", i+1, step)
+	}
+	if r.Notes != "" {
+		b.WriteString("\n## Notes\n\n" + r.Notes + "\n")
+	}
+	return b.String()
+}
+
+// Text writes the recipe out as plain text for the clipboard.
+func (r Recipe) Text() string {
+	var b strings.Builder
+	b.WriteString(r.Title + "\n" + r.summary(", ") + "\n\nIngredients:\n")
+	for _, ingredient := range r.Ingredients {
+		b.WriteString("- " + ingredient.Describe() + "\n")
+	}
+	b.WriteString("\nSteps:\n")
+	for i, step := range r.Steps {
+		fmt.Fprintf(&b, "%d. %s\n", i+1, step)
+	}
+	if r.Notes != "" {
+		b.WriteString("\nNotes:\n" + r.Notes + "\n")
+	}
+	return b.String()
+}
+
+// summary is the servings, times and cost on one line.
+func (r Recipe) summary(sep string) string {
+	var parts []string
+	if r.Servings > 0 {
+		parts = append(parts, "Serves "+strconv.Itoa(r.Servings))
+	}
+	if r.PrepMinutes > 0 {
+		parts = append(parts, "Prep "+FormatMinutes(r.PrepMinutes))
+	}
+	if r.CookMinutes > 0 {
+		parts = append(parts, "Cook "+FormatMinutes(r.CookMinutes))
+	}
+	if r.CostEstimate != "" {
+		parts = append(parts, "Cost "+r.CostEstimate)
+	}
+	return strings.Join(parts, sep)
+}
+
+// FormatMinutes writes a duration the way recipes do, e.g. "1 h 15 min".
+func FormatMinutes(minutes int) string {
+	h, m := minutes/60, minutes%60
+	switch {
+	case h == 0:
+		return strconv.Itoa(m) + " min"
+	case m == 0:
+		return strconv.Itoa(h) + " h"
+	}
+	return fmt.Sprintf("%d h %d min", h, m)
+}
+
+// fractions are the fractions found on measuring cups and spoons.
+var fractions = []struct {
+	value float64
+	text  string
+}{
+	{1.0 / 8, "1/8"}, {1.0 / 4, "1/4"}, {1.0 / 3, "1/3"}, {1.0 / 2, "1/2"}, {2.0 / 3, "2/3"}, {3.0 / 4, "3/4"},
+}
+
+// FormatAmount writes an amount the way recipes do, "1 1/2" rather than 1.5.
+// Amounts that aren't close to a common fraction are rounded to two decimals
+// and zero is empty.
+func FormatAmount(amount float64) string {
+	if amount <= 0 {
+		return ""
+	}
+	whole, frac := math.Modf(amount)
+	if frac < 0.02 {
+		return strconv.FormatFloat(whole, 'f', -1, 64)
+	}
+	if frac > 0.98 {
+		return strconv.FormatFloat(whole+1, 'f', -1, 64)
+	}
+	for _, f := range fractions {
+		if math.Abs(frac-f.value) < 0.02 {
+			if whole == 0 {
+				return f.text
+			}
+			return strconv.FormatFloat(whole, 'f', -1, 64) + " " + f.text
+		}
+	}
+	return strconv.FormatFloat(math.Round(amount*100)/100, 'f', -1, 64)
+}
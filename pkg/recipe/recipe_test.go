@@ -0,0 +1,173 @@
+package recipe_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bahelit/ctrl_plus_revise/pkg/recipe"
+)
+
+func pancakes() recipe.Recipe {
+	return recipe.Recipe{
+		Title:    "Pancakes",
+		Servings: 4,
+		Ingredients: []recipe.Ingredient{
+			{Name: "flour", Amount: 1.5, Unit: "cup"},
+			{Name: "eggs", Amount: 2},
+			{Name: "salt"},
+		},
+		Steps:        []string{"Mix everything.", "Fry in a hot pan."},
+		PrepMinutes:  10,
+		CookMinutes:  75,
+		CostEstimate: "$3",
+	}
+}
+
+func TestFormatAmount(t *testing.T) {
+	tests := []struct {
+		amount float64
+		want   string
+	}{
+		{0, ""},
+		{1, "1"},
+		{0.5, "1/2"},
+		{1.5, "1 1/2"},
+		{0.333, "1/3"},
+		{2.66, "2 2/3"},
+		{0.99, "1"},
+		{1.4, "1.4"},
+		{250, "250"},
+	}
+	for _, tt := range tests {
+		if got := recipe.FormatAmount(tt.amount); got != tt.want {
+			t.Errorf("FormatAmount(%v) = %q, want %q", tt.amount, got, tt.want)
+		}
+	}
+}
+
+func TestFormatMinutes(t *testing.T) {
+	tests := []struct {
+		minutes int
+		want    string
+	}{
+		{5, "5 min"},
+		{60, "1 h"},
+		{85, "1 h 25 min"},
+	}
+	for _, tt := range tests {
+		if got := recipe.FormatMinutes(tt.minutes); got != tt.want {
+			t.Errorf("FormatMinutes(%d) = %q, want %q", tt.minutes, got, tt.want)
+		}
+	}
+}
+
+func TestScale(t *testing.T) {
+	r := pancakes()
+	tests := []struct {
+		servings int
+		want     []string
+	}{
+		{4, []string{"1 1/2 cup flour", "2 eggs", "salt"}},
+		{2, []string{"3/4 cup flour", "1 eggs", "salt"}},
+		{6, []string{"2 1/4 cup flour", "3 eggs", "salt"}},
+		{0, []string{"1 1/2 cup flour", "2 eggs", "salt"}},
+	}
+	for _, tt := range tests {
+		scaled := r.Scale(tt.servings)
+		var got []string
+		for _, i := range scaled.Ingredients {
+			got = append(got, i.Describe())
+		}
+		if strings.Join(got, ", ") != strings.Join(tt.want, ", ") {
+			t.Errorf("Scale(%d) = %q, want %q", tt.servings, got, tt.want)
+		}
+	}
+	if r.Ingredients[0].Amount != 1.5 {
+		t.Errorf("Scale changed the original recipe")
+	}
+}
+
+func TestMatches(t *testing.T) {
+	r := pancakes()
+	r.Notes = "Kids love them"
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{"", true},
+		{"pancake", true},
+		{"FLOUR eggs", true},
+		{"kids", true},
+		{"flour milk", false},
+	}
+	for _, tt := range tests {
+		if got := r.Matches(tt.query); got != tt.want {
+			t.Errorf("Matches(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestStars(t *testing.T) {
+	tests := []struct {
+		rating int
+		want   string
+	}{
+		{0, "☆☆☆☆☆"},
+		{3, "★★★☆☆"},
+		{9, "★★★★★"},
+	}
+	for _, tt := range tests {
+		if got := (recipe.Recipe{Rating: tt.rating}).Stars(); got != tt.want {
+			t.Errorf("Stars(%d) = %q, want %q", tt.rating, got, tt.want)
+		}
+	}
+}
+
+func TestText(t *testing.T) {
+	want := `Pancakes
+Serves 4, Prep 10 min, Cook 1 h 15 min, Cost $3
+
+Ingredients:
+- 1 1/2 cup flour
+- 2 eggs
+- salt
+
+Steps:
+1. Mix everything.
+2. Fry in a hot pan.
+`
+	if got := pancakes().Text(); got != want {
+		t.Errorf("Text() = %q, want %q", got, want)
+	}
+	if md := pancakes().Markdown(); !strings.Contains(md, "# Pancakes") || !strings.Contains(md, "2. Fry in a hot pan.") {
+		t.Errorf("Markdown() = %q", md)
+	}
+}
+
+func TestLookupUnit(t *testing.T) {
+	tests := []struct {
+		name      string
+		want      string
+		dimension recipe.Dimension
+	}{
+		{"Tablespoons", "tbsp", recipe.Volume},
+		{"lbs.", "lb", recipe.Mass},
+		{"g", "g", recipe.Mass},
+		{"cloves", "cloves", "count:clove"},
+		{"", "", "count:"},
+	}
+	for _, tt := range tests {
+		u := recipe.LookupUnit(tt.name)
+		if u.Name != tt.want || u.Dimension != tt.dimension {
+			t.Errorf("LookupUnit(%q) = %+v, want %s %s", tt.name, u, tt.want, tt.dimension)
+		}
+	}
+}
+
+func TestSingular(t *testing.T) {
+	for word, want := range map[string]string{"tomatoes": "tomato", "berries": "berry", "eggs": "egg", "glass": "glass", "gas": "gas"} {
+		if got := recipe.Singular(word); got != want {
+			t.Errorf("Singular(%q) = %q, want %q", word, got, want)
+		}
+	}
+}
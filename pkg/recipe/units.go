@@ -0,0 +1,68 @@
+package recipe
+
+import "strings"
+
+// Dimension is what a unit measures, amounts of the same dimension can be
+// added up.
+type Dimension string
+
+const (
+	Mass   Dimension = "mass"
+	Volume Dimension = "volume"
+)
+
+// Unit converts an amount to grams or milliliters, Factor is the grams or
+// milliliters of one unit.
+type Unit struct {
+	Name      string
+	Dimension Dimension
+	Factor    float64
+}
+
+var units = map[string]Unit{
+	"g":     {"g", Mass, 1},
+	"kg":    {"kg", Mass, 1000},
+	"oz":    {"oz", Mass, 28.3495},
+	"lb":    {"lb", Mass, 453.592},
+	"ml":    {"ml", Volume, 1},
+	"l":     {"l", Volume, 1000},
+	"tsp":   {"tsp", Volume, 4.92892},
+	"tbsp":  {"tbsp", Volume, 14.7868},
+	"cup":   {"cup", Volume, 236.588},
+	"fl oz": {"fl oz", Volume, 29.5735},
+}
+
+var unitAliases = map[string]string{
+	"gram": "g", "grams": "g", "kilogram": "kg", "kilograms": "kg",
+	"ounce": "oz", "ounces": "oz", "pound": "lb", "pounds": "lb", "lbs": "lb",
+	"milliliter": "ml", "milliliters": "ml", "millilitre": "ml", "millilitres": "ml",
+	"liter": "l", "liters": "l", "litre": "l", "litres": "l",
+	"teaspoon": "tsp", "teaspoons": "tsp", "tablespoon": "tbsp", "tablespoons": "tbsp", "tbs": "tbsp",
+	"cups": "cup", "c": "cup", "fluid ounce": "fl oz", "fluid ounces": "fl oz",
+}
+
+// LookupUnit returns the unit, units that can't be converted, such as cans
+// or cloves, have a dimension of their own and only add up with themselves.
+func LookupUnit(name string) Unit {
+	name = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(name), "."))
+	if alias, ok := unitAliases[name]; ok {
+		name = alias
+	}
+	if u, ok := units[name]; ok {
+		return u
+	}
+	return Unit{Name: name, Dimension: Dimension("count:" + Singular(name)), Factor: 1}
+}
+
+// Singular is a rough English singular so "tomatoes" and "tomato" match.
+func Singular(word string) string {
+	switch {
+	case strings.HasSuffix(word, "oes"):
+		return strings.TrimSuffix(word, "es")
+	case strings.HasSuffix(word, "ies") && len(word) > 4:
+		return strings.TrimSuffix(word, "ies") + "y"
+	case strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss") && len(word) > 3:
+		return strings.TrimSuffix(word, "s")
+	}
+	return word
+}
@@ -0,0 +1,38 @@
+package speech
+
+import (
+	"context"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// ESpeak speaks through espeak-ng, or the older espeak if that is all there is.
+type ESpeak struct{}
+
+func (e *ESpeak) Name() string { return "espeak-ng" }
+
+func (e *ESpeak) Available() bool {
+	_, ok := lookPath("espeak-ng", "espeak")
+	return ok
+}
+
+func (e *ESpeak) Speak(ctx context.Context, text string, opts Options) error {
+	bin, ok := lookPath("espeak-ng", "espeak")
+	if !ok {
+		return ErrNoEngine
+	}
+	args := []string{"--stdin"}
+	switch {
+	case opts.Voice != "":
+		args = append(args, "-v", opts.Voice)
+	case opts.Language != "":
+		args = append(args, "-v", strings.ToLower(opts.Language))
+	}
+	if opts.Rate > 0 {
+		args = append(args, "-s", strconv.Itoa(opts.Rate))
+	}
+	cmd := exec.CommandContext(ctx, bin, args...)
+	cmd.Stdin = strings.NewReader(text)
+	return cmd.Run()
+}
@@ -0,0 +1,78 @@
+package speech
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// defaultRate is the words per minute most engines speak at by default.
+const defaultRate = 175
+
+// Piper speaks through the piper neural voices. Piper only synthesises audio,
+// the result is played back with the platform audio player.
+type Piper struct{}
+
+func (p *Piper) Name() string { return "piper" }
+
+func (p *Piper) Available() bool {
+	if _, ok := lookPath("piper"); !ok {
+		return false
+	}
+	_, ok := lookPath(players()...)
+	return ok
+}
+
+// Speak requires opts.Voice to be the path of a piper .onnx voice model.
+func (p *Piper) Speak(ctx context.Context, text string, opts Options) error {
+	bin, ok := lookPath("piper")
+	if !ok {
+		return ErrNoEngine
+	}
+	if opts.Voice == "" {
+		return fmt.Errorf("piper needs a voice model, set the voice to the path of a .onnx file")
+	}
+
+	wav, err := os.CreateTemp("", "ctrl_plus_revise_*.wav")
+	if err != nil {
+		return err
+	}
+	_ = wav.Close()
+	defer os.Remove(wav.Name())
+
+	args := []string{"--model", opts.Voice, "--output_file", wav.Name()}
+	if opts.Rate > 0 {
+		// piper stretches the audio, bigger is slower
+		scale := float64(defaultRate) / float64(opts.Rate)
+		args = append(args, "--length_scale", strconv.FormatFloat(scale, 'f', 2, 64))
+	}
+	cmd := exec.CommandContext(ctx, bin, args...)
+	cmd.Stdin = strings.NewReader(text)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("piper failed: %w, output: %s", err, string(output))
+	}
+	return playWav(ctx, wav.Name())
+}
+
+// players lists the command line audio players that can play a wav file.
+func players() []string {
+	return []string{"paplay", "aplay", "pw-play", "afplay", "powershell"}
+}
+
+func playWav(ctx context.Context, path string) error {
+	player, ok := lookPath(players()...)
+	if !ok {
+		return fmt.Errorf("no audio player found to play %s", path)
+	}
+	var cmd *exec.Cmd
+	if strings.Contains(strings.ToLower(player), "powershell") {
+		cmd = exec.CommandContext(ctx, player, "-NoProfile", "-Command",
+			"(New-Object Media.SoundPlayer '"+path+"').PlaySync()")
+	} else {
+		cmd = exec.CommandContext(ctx, player, path)
+	}
+	return cmd.Run()
+}
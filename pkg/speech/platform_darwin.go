@@ -0,0 +1,33 @@
+//go:build darwin
+
+package speech
+
+import (
+	"context"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// Platform speaks through the macOS say command.
+type Platform struct{}
+
+func (p *Platform) Name() string { return "say" }
+
+func (p *Platform) Available() bool {
+	_, ok := lookPath("say")
+	return ok
+}
+
+func (p *Platform) Speak(ctx context.Context, text string, opts Options) error {
+	var args []string
+	if opts.Voice != "" {
+		args = append(args, "-v", opts.Voice)
+	}
+	if opts.Rate > 0 {
+		args = append(args, "-r", strconv.Itoa(opts.Rate))
+	}
+	cmd := exec.CommandContext(ctx, "say", args...)
+	cmd.Stdin = strings.NewReader(text)
+	return cmd.Run()
+}
@@ -0,0 +1,47 @@
+//go:build freebsd || linux || netbsd || openbsd || solaris || dragonfly
+
+package speech
+
+import (
+	"context"
+	"os/exec"
+	"strconv"
+)
+
+// Platform speaks through speech-dispatcher, the desktop speech service on Linux.
+type Platform struct{}
+
+func (p *Platform) Name() string { return "speech-dispatcher" }
+
+func (p *Platform) Available() bool {
+	_, ok := lookPath("spd-say")
+	return ok
+}
+
+func (p *Platform) Speak(ctx context.Context, text string, opts Options) error {
+	bin, ok := lookPath("spd-say")
+	if !ok {
+		return ErrNoEngine
+	}
+	// -w waits until the message is spoken so the queue stays in order
+	args := []string{"-w"}
+	if opts.Voice != "" {
+		args = append(args, "-y", opts.Voice)
+	}
+	if opts.Language != "" {
+		args = append(args, "-l", baseLanguage(opts.Language))
+	}
+	if opts.Rate > 0 {
+		// spd-say takes a rate between -100 and 100
+		rate := (opts.Rate - defaultRate) * 100 / defaultRate
+		args = append(args, "-r", strconv.Itoa(max(-100, min(100, rate))))
+	}
+	args = append(args, "--", text)
+	cmd := exec.CommandContext(ctx, bin, args...)
+	err := cmd.Run()
+	if ctx.Err() != nil {
+		// spd-say hands the text to a daemon, make sure it stops talking too
+		_ = exec.Command(bin, "-S").Run()
+	}
+	return err
+}
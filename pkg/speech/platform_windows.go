@@ -0,0 +1,37 @@
+//go:build windows
+
+package speech
+
+import (
+	"context"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// Platform speaks through the Windows speech synthesizer using PowerShell.
+type Platform struct{}
+
+func (p *Platform) Name() string { return "windows-speech" }
+
+func (p *Platform) Available() bool {
+	_, ok := lookPath("powershell")
+	return ok
+}
+
+func (p *Platform) Speak(ctx context.Context, text string, opts Options) error {
+	script := "Add-Type -AssemblyName System.Speech; " +
+		"$s = New-Object System.Speech.Synthesis.SpeechSynthesizer; "
+	if opts.Voice != "" {
+		script += "$s.SelectVoice('" + strings.ReplaceAll(opts.Voice, "'", "''") + "'); "
+	}
+	if opts.Rate > 0 {
+		// SpeechSynthesizer takes a rate between -10 and 10
+		rate := (opts.Rate - defaultRate) * 10 / defaultRate
+		script += "$s.Rate = " + strconv.Itoa(max(-10, min(10, rate))) + "; "
+	}
+	script += "$s.Speak([Console]::In.ReadToEnd())"
+	cmd := exec.CommandContext(ctx, "powershell", "-NoProfile", "-Command", script)
+	cmd.Stdin = strings.NewReader(text)
+	return cmd.Run()
+}
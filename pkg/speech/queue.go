@@ -0,0 +1,120 @@
+package speech
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+)
+
+// queueSize is how many utterances can wait before Say starts dropping them.
+const queueSize = 16
+
+// Queue speaks text one utterance at a time in the order it was added.
+// Interrupt stops the current utterance and throws away everything waiting.
+type Queue struct {
+	mu     sync.Mutex
+	engine Engine
+	opts   Options
+	items  chan utterance
+	cancel context.CancelFunc
+	// generation is bumped by Interrupt, anything queued with an older
+	// generation is skipped by the worker.
+	generation int
+}
+
+type utterance struct {
+	text       string
+	generation int
+}
+
+// NewQueue creates a Queue, call Start to begin speaking.
+func NewQueue(engine Engine, opts Options) *Queue {
+	return &Queue{
+		engine: engine,
+		opts:   opts,
+		items:  make(chan utterance, queueSize),
+	}
+}
+
+// Start processes the queue in the background until ctx is cancelled.
+func (q *Queue) Start(ctx context.Context) {
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case item := <-q.items:
+				q.speak(ctx, item)
+			}
+		}
+	}()
+}
+
+func (q *Queue) speak(ctx context.Context, item utterance) {
+	q.mu.Lock()
+	if item.generation != q.generation || q.engine == nil {
+		q.mu.Unlock()
+		return
+	}
+	speakCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	q.cancel = cancel
+	engine, opts := q.engine, q.opts
+	q.mu.Unlock()
+
+	err := engine.Speak(speakCtx, item.text, opts)
+	if err != nil && !errors.Is(speakCtx.Err(), context.Canceled) {
+		slog.Error("Failed to speak", "engine", engine.Name(), "error", err)
+	}
+
+	q.mu.Lock()
+	q.cancel = nil
+	q.mu.Unlock()
+}
+
+// Say adds the text to the end of the queue, it does not wait for it to be spoken.
+func (q *Queue) Say(text string) {
+	if text == "" {
+		return
+	}
+	q.mu.Lock()
+	item := utterance{text: text, generation: q.generation}
+	q.mu.Unlock()
+	select {
+	case q.items <- item:
+	default:
+		slog.Warn("Speech queue is full, dropping text")
+	}
+}
+
+// Interrupt stops the current utterance and clears the queue.
+func (q *Queue) Interrupt() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.generation++
+	if q.cancel != nil {
+		q.cancel()
+	}
+}
+
+// SetEngine changes the engine used for the next utterance.
+func (q *Queue) SetEngine(engine Engine) {
+	q.mu.Lock()
+	q.engine = engine
+	q.mu.Unlock()
+}
+
+// SetOptions changes the voice settings used for the next utterance.
+func (q *Queue) SetOptions(opts Options) {
+	q.mu.Lock()
+	q.opts = opts
+	q.mu.Unlock()
+}
+
+// Engine returns the engine currently in use, nil if there isn't one.
+func (q *Queue) Engine() Engine {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.engine
+}
@@ -0,0 +1,104 @@
+package speech_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bahelit/ctrl_plus_revise/pkg/speech"
+)
+
+// fakeEngine records what it was asked to say, each utterance takes delay.
+type fakeEngine struct {
+	mu     sync.Mutex
+	delay  time.Duration
+	spoken []string
+	done   chan string
+}
+
+func (f *fakeEngine) Name() string    { return "fake" }
+func (f *fakeEngine) Available() bool { return true }
+
+func (f *fakeEngine) Speak(ctx context.Context, text string, _ speech.Options) error {
+	select {
+	case <-time.After(f.delay):
+		f.mu.Lock()
+		f.spoken = append(f.spoken, text)
+		f.mu.Unlock()
+	case <-ctx.Done():
+	}
+	f.done <- text
+	return ctx.Err()
+}
+
+func (f *fakeEngine) Spoken() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.spoken...)
+}
+
+func TestQueueSpeaksInOrder(t *testing.T) {
+	engine := &fakeEngine{delay: time.Millisecond, done: make(chan string, 3)}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	q := speech.NewQueue(engine, speech.Options{})
+	q.Start(ctx)
+
+	q.Say("one")
+	q.Say("two")
+	q.Say("three")
+	for i := 0; i < 3; i++ {
+		<-engine.done
+	}
+
+	spoken := engine.Spoken()
+	want := []string{"one", "two", "three"}
+	if len(spoken) != len(want) {
+		t.Fatalf("Expected %v, received %v", want, spoken)
+	}
+	for i := range want {
+		if spoken[i] != want[i] {
+			t.Fatalf("Expected %v, received %v", want, spoken)
+		}
+	}
+}
+
+func TestQueueInterrupt(t *testing.T) {
+	engine := &fakeEngine{delay: time.Hour, done: make(chan string, 3)}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	q := speech.NewQueue(engine, speech.Options{})
+	q.Start(ctx)
+
+	q.Say("long story")
+	q.Say("never spoken")
+	// give the worker a moment to pick up the first utterance
+	time.Sleep(10 * time.Millisecond)
+	q.Interrupt()
+
+	select {
+	case text := <-engine.done:
+		if text != "long story" {
+			t.Fatalf("Expected the current utterance to be interrupted, received %q", text)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Interrupt did not stop the current utterance")
+	}
+
+	engine.delay = time.Millisecond
+	q.Say("after")
+	if text := <-engine.done; text != "after" {
+		t.Fatalf("Expected queued text to be dropped, received %q", text)
+	}
+	if spoken := engine.Spoken(); len(spoken) != 1 || spoken[0] != "after" {
+		t.Fatalf("Expected only \"after\" to be spoken, received %v", spoken)
+	}
+}
+
+func TestEnginesPiperLast(t *testing.T) {
+	engines := speech.Engines()
+	if last := engines[len(engines)-1].Name(); last != "piper" {
+		t.Errorf("last engine = %s, want piper, it needs a voice model before it can speak", last)
+	}
+}
@@ -0,0 +1,92 @@
+// Package speech reads text aloud through locally installed text-to-speech
+// engines. Nothing is sent over the network, every engine runs on the machine.
+package speech
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os/exec"
+	"strings"
+)
+
+// ErrNoEngine is returned when no text-to-speech engine could be found.
+var ErrNoEngine = errors.New("no text-to-speech engine available")
+
+// Options are the voice settings passed to an Engine for each utterance.
+type Options struct {
+	// Voice is engine specific, an espeak-ng voice name, a piper model path
+	// or a platform voice name. Empty uses the engine default.
+	Voice string
+	// Rate is the speaking rate in words per minute, 0 uses the engine default.
+	Rate int
+	// Language is a BCP-47 tag such as "en-US", used when Voice is empty.
+	Language string
+}
+
+// Engine is a text-to-speech backend.
+type Engine interface {
+	// Name is the stable identifier stored in the user preferences.
+	Name() string
+	// Available reports whether the engine can be used on this machine.
+	Available() bool
+	// Speak reads the text aloud and blocks until it is done or ctx is cancelled.
+	Speak(ctx context.Context, text string, opts Options) error
+}
+
+// Engines returns every known engine in order of preference. Piper comes
+// last because it can't speak until a voice model is set, it is only picked
+// when it is chosen in the settings or nothing else is installed.
+func Engines() []Engine {
+	return []Engine{
+		&ESpeak{},
+		&Platform{},
+		&Piper{},
+	}
+}
+
+// Detect returns the engines that are installed on this machine.
+func Detect() []Engine {
+	var found []Engine
+	for _, e := range Engines() {
+		if e.Available() {
+			found = append(found, e)
+		}
+	}
+	slog.Debug("Detected text-to-speech engines", "count", len(found))
+	return found
+}
+
+// Find returns the named engine if it is available, otherwise the first
+// available engine.
+func Find(name string) (Engine, error) {
+	available := Detect()
+	if len(available) == 0 {
+		return nil, ErrNoEngine
+	}
+	for _, e := range available {
+		if e.Name() == name {
+			return e, nil
+		}
+	}
+	return available[0], nil
+}
+
+// lookPath returns the first of the binaries that can be found in the PATH.
+func lookPath(binaries ...string) (string, bool) {
+	for _, bin := range binaries {
+		if path, err := exec.LookPath(bin); err == nil {
+			return path, true
+		}
+	}
+	return "", false
+}
+
+// baseLanguage returns the primary subtag of a BCP-47 tag, "en-US" -> "en".
+func baseLanguage(tag string) string {
+	tag = strings.ReplaceAll(tag, "_", "-")
+	if i := strings.Index(tag, "-"); i > 0 {
+		return strings.ToLower(tag[:i])
+	}
+	return strings.ToLower(tag)
+}
@@ -0,0 +1,82 @@
+// Package suggest keeps track of the grammar and style suggestions for the
+// paragraphs of a text and applies them one at a time.
+package suggest
+
+import (
+	"regexp"
+	"strings"
+)
+
+var paragraphBreak = regexp.MustCompile(`\n[ \t]*\n\s*`)
+
+// Suggestion is one change to a paragraph.
+type Suggestion struct {
+	Original    string `json:"original" desc:"The exact words of the paragraph to change, copied character for character"`
+	Replacement string `json:"replacement" desc:"The words to put in their place"`
+	Explanation string `json:"explanation" desc:"One sentence on why the change is better"`
+	Kind        string `json:"kind" enum:"grammar|spelling|punctuation|style|clarity"`
+}
+
+// Suggestions are the changes to one paragraph, empty when it is fine.
+type Suggestions struct {
+	Suggestions []Suggestion `json:"suggestions"`
+}
+
+// Paragraphs splits the text on blank lines, the paragraphs are trimmed and
+// empty ones are left out.
+func Paragraphs(text string) []string {
+	var paragraphs []string
+	for _, p := range paragraphBreak.Split(text, -1) {
+		if p = strings.TrimSpace(p); p != "" {
+			paragraphs = append(paragraphs, p)
+		}
+	}
+	return paragraphs
+}
+
+// Filter drops the suggestions that don't fit the paragraph, the model
+// sometimes paraphrases the original or suggests no change at all.
+func Filter(paragraph string, suggestions []Suggestion) []Suggestion {
+	var kept []Suggestion
+	seen := make(map[string]bool)
+	for _, s := range suggestions {
+		key := s.Original + "\x00" + s.Replacement
+		if s.Original == "" || s.Original == s.Replacement || seen[key] ||
+			!strings.Contains(paragraph, s.Original) {
+			continue
+		}
+		seen[key] = true
+		kept = append(kept, s)
+	}
+	return kept
+}
+
+// Apply makes the change in the paragraph of the text, it returns the new
+// text and paragraph, ok is false when the text no longer has the paragraph
+// or the paragraph no longer has the original words.
+func Apply(text, paragraph string, s Suggestion) (newText, newParagraph string, ok bool) {
+	start := strings.Index(text, paragraph)
+	if start < 0 || s.Original == "" {
+		return text, paragraph, false
+	}
+	at := strings.Index(paragraph, s.Original)
+	if at < 0 {
+		return text, paragraph, false
+	}
+	newParagraph = paragraph[:at] + s.Replacement + paragraph[at+len(s.Original):]
+	newText = text[:start] + newParagraph + text[start+len(paragraph):]
+	return newText, newParagraph, true
+}
+
+// Label describes the suggestion in a list, e.g. "Grammar: their → there".
+func (s Suggestion) Label() string {
+	kind := s.Kind
+	if kind == "" {
+		kind = "style"
+	}
+	replacement := s.Replacement
+	if replacement == "" {
+		replacement = "(remove)"
+	}
+	return strings.ToUpper(kind[:1]) + kind[1:] + ": " + s.Original + " → " + replacement
+}
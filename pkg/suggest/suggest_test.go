@@ -0,0 +1,112 @@
+package suggest_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/bahelit/ctrl_plus_revise/pkg/suggest"
+)
+
+func TestParagraphs(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"empty", " \n\n ", nil},
+		{"one", "  Hello world.\n", []string{"Hello world."}},
+		{"lines stay together", "One line\nnext line", []string{"One line\nnext line"}},
+		{"blank lines", "First.\n\n\nSecond.\n \t\nThird.", []string{"First.", "Second.", "Third."}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := suggest.Paragraphs(tt.text); !slices.Equal(got, tt.want) {
+				t.Errorf("Paragraphs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilter(t *testing.T) {
+	paragraph := "Their going to the store tomorow."
+	suggestions := []suggest.Suggestion{
+		{Original: "Their", Replacement: "They're", Kind: "grammar"},
+		{Original: "tomorow", Replacement: "tomorrow", Kind: "spelling"},
+		{Original: "tomorow", Replacement: "tomorrow", Kind: "spelling"},
+		{Original: "They is going", Replacement: "They are going"},
+		{Original: "store", Replacement: "store"},
+		{Original: "", Replacement: "Yes"},
+	}
+	got := suggest.Filter(paragraph, suggestions)
+	if len(got) != 2 || got[0].Original != "Their" || got[1].Original != "tomorow" {
+		t.Errorf("Filter() = %+v", got)
+	}
+}
+
+func TestApply(t *testing.T) {
+	text := "Intro.\n\nTheir going to the store tomorow.\n\nOutro."
+	paragraph := "Their going to the store tomorow."
+	tests := []struct {
+		name          string
+		paragraph     string
+		suggestion    suggest.Suggestion
+		wantText      string
+		wantParagraph string
+		wantOK        bool
+	}{
+		{
+			name:          "replace",
+			paragraph:     paragraph,
+			suggestion:    suggest.Suggestion{Original: "Their", Replacement: "They're"},
+			wantText:      "Intro.\n\nThey're going to the store tomorow.\n\nOutro.",
+			wantParagraph: "They're going to the store tomorow.",
+			wantOK:        true,
+		},
+		{
+			name:          "remove",
+			paragraph:     paragraph,
+			suggestion:    suggest.Suggestion{Original: " to the store", Replacement: ""},
+			wantText:      "Intro.\n\nTheir going tomorow.\n\nOutro.",
+			wantParagraph: "Their going tomorow.",
+			wantOK:        true,
+		},
+		{
+			name:          "paragraph edited since",
+			paragraph:     "Their going to the shop tomorow.",
+			suggestion:    suggest.Suggestion{Original: "Their", Replacement: "They're"},
+			wantText:      text,
+			wantParagraph: "Their going to the shop tomorow.",
+		},
+		{
+			name:          "words edited since",
+			paragraph:     paragraph,
+			suggestion:    suggest.Suggestion{Original: "recieve", Replacement: "receive"},
+			wantText:      text,
+			wantParagraph: paragraph,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotText, gotParagraph, ok := suggest.Apply(text, tt.paragraph, tt.suggestion)
+			if gotText != tt.wantText || gotParagraph != tt.wantParagraph || ok != tt.wantOK {
+				t.Errorf("Apply() = %q, %q, %v, want %q, %q, %v",
+					gotText, gotParagraph, ok, tt.wantText, tt.wantParagraph, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestLabel(t *testing.T) {
+	tests := []struct {
+		suggestion suggest.Suggestion
+		want       string
+	}{
+		{suggest.Suggestion{Original: "their", Replacement: "there", Kind: "grammar"}, "Grammar: their → there"},
+		{suggest.Suggestion{Original: "very", Replacement: ""}, "Style: very → (remove)"},
+	}
+	for _, tt := range tests {
+		if got := tt.suggestion.Label(); got != tt.want {
+			t.Errorf("Label() = %q, want %q", got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package transcribe
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// SampleRate is the sample rate whisper models are trained on.
+const SampleRate = 16000
+
+// ErrNoRecorder is returned when none of the supported recording tools are installed.
+var ErrNoRecorder = errors.New("no audio recorder found, install alsa-utils, sox or ffmpeg")
+
+// Recorder records the default microphone to a 16kHz mono wav file using
+// whichever command line recorder is installed.
+type Recorder struct {
+	mu    sync.Mutex
+	cmd   *exec.Cmd
+	stdin io.WriteCloser
+	path  string
+}
+
+// recordCommand returns the command that records to path.
+func recordCommand(path string) (*exec.Cmd, error) {
+	rate := fmt.Sprint(SampleRate)
+	if bin, err := exec.LookPath("arecord"); err == nil {
+		return exec.Command(bin, "-q", "-f", "S16_LE", "-r", rate, "-c", "1", "-t", "wav", path), nil
+	}
+	if bin, err := exec.LookPath("rec"); err == nil {
+		return exec.Command(bin, "-q", "-r", rate, "-c", "1", "-b", "16", path), nil
+	}
+	if bin, err := exec.LookPath("ffmpeg"); err == nil {
+		var input []string
+		switch runtime.GOOS {
+		case "darwin":
+			input = []string{"-f", "avfoundation", "-i", ":0"}
+		case "linux":
+			input = []string{"-f", "pulse", "-i", "default"}
+		default:
+			return nil, ErrNoRecorder
+		}
+		args := append([]string{"-hide_banner", "-loglevel", "error", "-y"}, input...)
+		args = append(args, "-ar", rate, "-ac", "1", "-c:a", "pcm_s16le", path)
+		return exec.Command(bin, args...), nil
+	}
+	return nil, ErrNoRecorder
+}
+
+// Available reports whether a recorder is installed.
+func (r *Recorder) Available() bool {
+	_, err := recordCommand(os.DevNull)
+	return err == nil
+}
+
+// Start begins recording to a new temporary file.
+func (r *Recorder) Start() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.cmd != nil {
+		return errors.New("already recording")
+	}
+
+	f, err := os.CreateTemp("", "ctrl_plus_revise_dictation_*.wav")
+	if err != nil {
+		return err
+	}
+	_ = f.Close()
+
+	cmd, err := recordCommand(f.Name())
+	if err != nil {
+		_ = os.Remove(f.Name())
+		return err
+	}
+	// ffmpeg stops cleanly when it reads q from stdin
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		_ = os.Remove(f.Name())
+		return err
+	}
+	if err = cmd.Start(); err != nil {
+		_ = os.Remove(f.Name())
+		return err
+	}
+	slog.Debug("Recording started", "recorder", cmd.Path, "file", f.Name())
+	r.cmd, r.stdin, r.path = cmd, stdin, f.Name()
+	return nil
+}
+
+// Recording reports whether Start has been called without Stop.
+func (r *Recorder) Recording() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.cmd != nil
+}
+
+// Stop ends the recording and returns the path of the wav file, the caller
+// is responsible for removing it.
+func (r *Recorder) Stop() (string, error) {
+	r.mu.Lock()
+	cmd, stdin, path := r.cmd, r.stdin, r.path
+	r.cmd, r.stdin, r.path = nil, nil, ""
+	r.mu.Unlock()
+	if cmd == nil {
+		return "", errors.New("not recording")
+	}
+
+	_, _ = stdin.Write([]byte("q"))
+	_ = stdin.Close()
+	// the recorders finish writing the wav header when interrupted
+	if err := cmd.Process.Signal(os.Interrupt); err != nil {
+		_ = cmd.Process.Kill()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		_ = cmd.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(3 * time.Second):
+		_ = cmd.Process.Kill()
+		<-done
+	}
+	slog.Debug("Recording stopped", "file", path)
+	return path, nil
+}
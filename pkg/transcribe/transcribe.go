@@ -0,0 +1,148 @@
+// Package transcribe records microphone audio and turns it into text with a
+// local whisper.cpp server or binary.
+package transcribe
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// MinDuration is the shortest recording worth sending for transcription,
+// anything shorter is most likely an accidental key press.
+const MinDuration = 300 * time.Millisecond
+
+// ErrTooShort is returned when the recording is shorter than MinDuration.
+var ErrTooShort = errors.New("recording is too short")
+
+// Transcriber turns a wav recording into text.
+type Transcriber interface {
+	Transcribe(ctx context.Context, wavPath string) (string, error)
+}
+
+// Server sends the recording to a whisper.cpp compatible server, the kind
+// started with "whisper-server -m model.bin".
+type Server struct {
+	// URL is the base address of the server, e.g. http://127.0.0.1:8080
+	URL string
+	// Language is an ISO 639-1 code or "auto".
+	Language string
+	Client   *http.Client
+}
+
+type serverResponse struct {
+	Text  string `json:"text"`
+	Error string `json:"error"`
+}
+
+func (s *Server) Transcribe(ctx context.Context, wavPath string) (string, error) {
+	if err := checkRecording(wavPath); err != nil {
+		return "", err
+	}
+	audio, err := os.Open(wavPath)
+	if err != nil {
+		return "", err
+	}
+	defer audio.Close()
+
+	body := &bytes.Buffer{}
+	form := multipart.NewWriter(body)
+	part, err := form.CreateFormFile("file", filepath.Base(wavPath))
+	if err != nil {
+		return "", err
+	}
+	if _, err = io.Copy(part, audio); err != nil {
+		return "", err
+	}
+	_ = form.WriteField("response_format", "json")
+	_ = form.WriteField("temperature", "0.0")
+	if s.Language != "" {
+		_ = form.WriteField("language", s.Language)
+	}
+	if err = form.Close(); err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(s.URL, "/")+"/inference", body)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", form.FormDataContentType())
+
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var result serverResponse
+	if err = json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("unexpected response from transcription server: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK || result.Error != "" {
+		return "", fmt.Errorf("transcription server returned %s: %s", resp.Status, result.Error)
+	}
+	return CleanTranscript(result.Text), nil
+}
+
+// Binary runs a whisper.cpp command line binary, whisper-cli or the older main.
+type Binary struct {
+	Path string
+	// Model is the path of the ggml model file.
+	Model    string
+	Language string
+}
+
+func (b *Binary) Transcribe(ctx context.Context, wavPath string) (string, error) {
+	if err := checkRecording(wavPath); err != nil {
+		return "", err
+	}
+	args := []string{"-m", b.Model, "-f", wavPath, "-nt", "-np"}
+	if b.Language != "" {
+		args = append(args, "-l", b.Language)
+	}
+	cmd := exec.CommandContext(ctx, b.Path, args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to transcribe: %w, output: %s", err, stderr.String())
+	}
+	return CleanTranscript(string(output)), nil
+}
+
+func checkRecording(wavPath string) error {
+	info, err := ReadWAVFile(wavPath)
+	if err != nil {
+		return err
+	}
+	if info.Duration() < MinDuration {
+		return ErrTooShort
+	}
+	return nil
+}
+
+// annotations matches the non-speech markers whisper adds, e.g. [BLANK_AUDIO] or (music).
+var annotations = regexp.MustCompile(`\[[A-Z_ ]+\]|\((?i:music|applause|laughter|silence|inaudible|noise)\)`)
+
+// CleanTranscript removes whisper's non-speech markers and joins the lines
+// into a single block of text.
+func CleanTranscript(text string) string {
+	text = annotations.ReplaceAllString(text, "")
+	return strings.Join(strings.Fields(text), " ")
+}
@@ -0,0 +1,140 @@
+package transcribe_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/bahelit/ctrl_plus_revise/pkg/transcribe"
+)
+
+var wavTable = []struct {
+	File     string
+	Duration time.Duration
+	Err      error
+}{
+	{"one_second.wav", time.Second, nil},
+	{"tap.wav", 100 * time.Millisecond, nil},
+	{"float.wav", 0, transcribe.ErrNotWAV},
+	{"not_audio.wav", 0, transcribe.ErrNotWAV},
+}
+
+func Test_ReadWAVFile(t *testing.T) {
+	for _, v := range wavTable {
+		info, err := transcribe.ReadWAVFile(filepath.Join("testdata", v.File))
+		if !errors.Is(err, v.Err) {
+			t.Fatalf("%s: expected error %v, received %v", v.File, v.Err, err)
+		}
+		if err != nil {
+			continue
+		}
+		if info.Duration() != v.Duration {
+			t.Fatalf("%s: expected %s, received %s", v.File, v.Duration, info.Duration())
+		}
+		if info.SampleRate != transcribe.SampleRate || info.Channels != 1 {
+			t.Fatalf("%s: expected 16kHz mono, received %+v", v.File, info)
+		}
+	}
+}
+
+var cleanTable = []struct {
+	Input  string
+	Result string
+}{
+	{" Hello world.\n", "Hello world."},
+	{"[BLANK_AUDIO]", ""},
+	{" Dear team,\n thanks for [MUSIC] the update. (music)", "Dear team, thanks for the update."},
+	{"Keep (parentheses) that are speech", "Keep (parentheses) that are speech"},
+}
+
+func Test_CleanTranscript(t *testing.T) {
+	for _, v := range cleanTable {
+		if result := transcribe.CleanTranscript(v.Input); result != v.Result {
+			t.Fatalf("Expected %q, received %q", v.Result, result)
+		}
+	}
+}
+
+// whisperServer stands in for a whisper.cpp server.
+func whisperServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/inference" || r.Method != http.MethodPost {
+			http.NotFound(w, r)
+			return
+		}
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			return
+		}
+		audio, _ := io.ReadAll(file)
+		if r.FormValue("response_format") != "json" || string(audio[0:4]) != "RIFF" {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "bad request"})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"text": " Please review the attached report.\n[BLANK_AUDIO]\n"})
+	}))
+}
+
+func Test_ServerTranscribe(t *testing.T) {
+	server := whisperServer(t)
+	defer server.Close()
+
+	transcriber := &transcribe.Server{URL: server.URL + "/", Language: "en"}
+	text, err := transcriber.Transcribe(context.Background(), filepath.Join("testdata", "one_second.wav"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text != "Please review the attached report." {
+		t.Fatalf("Unexpected transcript %q", text)
+	}
+
+	_, err = transcriber.Transcribe(context.Background(), filepath.Join("testdata", "tap.wav"))
+	if !errors.Is(err, transcribe.ErrTooShort) {
+		t.Fatalf("Expected %v, received %v", transcribe.ErrTooShort, err)
+	}
+}
+
+func Test_ServerError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "model not loaded"})
+	}))
+	defer server.Close()
+
+	transcriber := &transcribe.Server{URL: server.URL}
+	_, err := transcriber.Transcribe(context.Background(), filepath.Join("testdata", "one_second.wav"))
+	if err == nil {
+		t.Fatal("Expected the server error to be returned")
+	}
+}
+
+func Test_BinaryTranscribe(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script in place of whisper-cli")
+	}
+	script := filepath.Join(t.TempDir(), "whisper-cli")
+	err := os.WriteFile(script, []byte("#!/bin/sh\necho ' Meeting moved to Tuesday.'\n"), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	transcriber := &transcribe.Binary{Path: script, Model: "ggml-base.en.bin"}
+	text, err := transcriber.Transcribe(context.Background(), filepath.Join("testdata", "one_second.wav"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text != "Meeting moved to Tuesday." {
+		t.Fatalf("Unexpected transcript %q", text)
+	}
+}
@@ -0,0 +1,93 @@
+package transcribe
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// ErrNotWAV is returned when a recording isn't a PCM wav file.
+var ErrNotWAV = errors.New("not a PCM wav file")
+
+// WAVInfo describes the audio in a wav file.
+type WAVInfo struct {
+	Channels      int
+	SampleRate    int
+	BitsPerSample int
+	DataSize      int64
+}
+
+// Duration is the playing time of the audio data.
+func (w WAVInfo) Duration() time.Duration {
+	bytesPerSecond := int64(w.SampleRate * w.Channels * w.BitsPerSample / 8)
+	if bytesPerSecond == 0 {
+		return 0
+	}
+	return time.Duration(w.DataSize * int64(time.Second) / bytesPerSecond)
+}
+
+// ReadWAVInfo reads the header of a RIFF/WAVE file, only PCM audio is accepted
+// because that is what whisper.cpp expects.
+func ReadWAVInfo(r io.Reader) (WAVInfo, error) {
+	var info WAVInfo
+	header := make([]byte, 12)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return info, fmt.Errorf("%w: %v", ErrNotWAV, err)
+	}
+	if string(header[0:4]) != "RIFF" || string(header[8:12]) != "WAVE" {
+		return info, ErrNotWAV
+	}
+
+	foundFormat := false
+	chunk := make([]byte, 8)
+	for {
+		if _, err := io.ReadFull(r, chunk); err != nil {
+			return info, fmt.Errorf("%w: missing data chunk", ErrNotWAV)
+		}
+		id := string(chunk[0:4])
+		size := int64(binary.LittleEndian.Uint32(chunk[4:8]))
+		switch id {
+		case "fmt ":
+			if size < 16 {
+				return info, fmt.Errorf("%w: short fmt chunk", ErrNotWAV)
+			}
+			format := make([]byte, size)
+			if _, err := io.ReadFull(r, format); err != nil {
+				return info, fmt.Errorf("%w: %v", ErrNotWAV, err)
+			}
+			// 1 is integer PCM, 0xFFFE is WAVE_FORMAT_EXTENSIBLE which arecord uses for > 2 channels
+			audioFormat := binary.LittleEndian.Uint16(format[0:2])
+			if audioFormat != 1 && audioFormat != 0xFFFE {
+				return info, fmt.Errorf("%w: audio format %d", ErrNotWAV, audioFormat)
+			}
+			info.Channels = int(binary.LittleEndian.Uint16(format[2:4]))
+			info.SampleRate = int(binary.LittleEndian.Uint32(format[4:8]))
+			info.BitsPerSample = int(binary.LittleEndian.Uint16(format[14:16]))
+			foundFormat = true
+		case "data":
+			if !foundFormat {
+				return info, fmt.Errorf("%w: data before fmt chunk", ErrNotWAV)
+			}
+			info.DataSize = size
+			return info, nil
+		default:
+			// chunks are padded to an even size
+			if _, err := io.CopyN(io.Discard, r, size+size%2); err != nil {
+				return info, fmt.Errorf("%w: %v", ErrNotWAV, err)
+			}
+		}
+	}
+}
+
+// ReadWAVFile reads the wav header of the file at path.
+func ReadWAVFile(path string) (WAVInfo, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return WAVInfo{}, err
+	}
+	defer f.Close()
+	return ReadWAVInfo(f)
+}
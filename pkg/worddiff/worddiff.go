@@ -0,0 +1,225 @@
+// Package worddiff compares two versions of a text word by word and lets
+// the caller rebuild the text choosing which changes to keep.
+package worddiff
+
+import (
+	"strings"
+	"unicode"
+)
+
+// maxCells bounds the size of the comparison table, texts with more
+// words than that are treated as a single change.
+const maxCells = 4_000_000
+
+type Kind int
+
+const (
+	Equal Kind = iota
+	Delete
+	Insert
+)
+
+// Segment is a run of text that is the same in both versions, or that was
+// removed from the original or added in the revision.
+type Segment struct {
+	Kind Kind
+	Text string
+	// Hunk is the change the segment belongs to, -1 for Equal segments.
+	Hunk int
+}
+
+// Diff is the word level difference between two texts.
+type Diff struct {
+	Segments []Segment
+	Hunks    int
+}
+
+// Words compares the original and the revised text.
+func Words(original, revised string) Diff {
+	a, b := tokenize(original), tokenize(revised)
+	var ops []Segment
+	if len(a)*len(b) > maxCells {
+		ops = append(ops, Segment{Kind: Delete, Text: original}, Segment{Kind: Insert, Text: revised})
+	} else {
+		ops = lcs(a, b)
+	}
+	return group(merge(ops))
+}
+
+// tokenize splits text into words, runs of whitespace and single punctuation marks.
+func tokenize(text string) []string {
+	var (
+		tokens []string
+		start  = -1
+		class  = 0
+	)
+	classOf := func(r rune) int {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '\'' || r == '_':
+			return 1
+		case unicode.IsSpace(r):
+			return 2
+		default:
+			return 3
+		}
+	}
+	for i, r := range text {
+		c := classOf(r)
+		if start >= 0 && (c != class || c == 3) {
+			tokens = append(tokens, text[start:i])
+			start = -1
+		}
+		if start < 0 {
+			start, class = i, c
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, text[start:])
+	}
+	return tokens
+}
+
+// lcs diffs the tokens with a longest common subsequence table.
+func lcs(a, b []string) []Segment {
+	n, m := len(a), len(b)
+	table := make([][]int32, n+1)
+	for i := range table {
+		table[i] = make([]int32, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				table[i][j] = table[i+1][j+1] + 1
+			} else {
+				table[i][j] = max(table[i+1][j], table[i][j+1])
+			}
+		}
+	}
+
+	var ops []Segment
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, Segment{Kind: Equal, Text: a[i]})
+			i++
+			j++
+		case table[i+1][j] >= table[i][j+1]:
+			ops = append(ops, Segment{Kind: Delete, Text: a[i]})
+			i++
+		default:
+			ops = append(ops, Segment{Kind: Insert, Text: b[j]})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		ops = append(ops, Segment{Kind: Delete, Text: a[i]})
+	}
+	for ; j < m; j++ {
+		ops = append(ops, Segment{Kind: Insert, Text: b[j]})
+	}
+	return ops
+}
+
+// merge joins neighbouring segments of the same kind and folds whitespace
+// that sits between two changes into the change, so "the quick" -> "a slow"
+// is one change instead of two.
+func merge(ops []Segment) []Segment {
+	for i := 1; i < len(ops)-1; i++ {
+		if ops[i].Kind == Equal && strings.TrimSpace(ops[i].Text) == "" &&
+			ops[i-1].Kind != Equal && ops[i+1].Kind != Equal {
+			space := ops[i].Text
+			ops = append(ops[:i], append([]Segment{
+				{Kind: Delete, Text: space},
+				{Kind: Insert, Text: space},
+			}, ops[i+1:]...)...)
+			i++
+		}
+	}
+
+	var merged []Segment
+	for _, op := range ops {
+		if op.Text == "" {
+			continue
+		}
+		if len(merged) > 0 && merged[len(merged)-1].Kind == op.Kind {
+			merged[len(merged)-1].Text += op.Text
+			continue
+		}
+		// keep deletions in front of insertions within a change
+		if len(merged) > 1 && op.Kind == Delete && merged[len(merged)-1].Kind == Insert &&
+			merged[len(merged)-2].Kind == Delete {
+			merged[len(merged)-2].Text += op.Text
+			continue
+		}
+		merged = append(merged, op)
+	}
+	return merged
+}
+
+// group numbers the changes, a deletion followed by an insertion is one change.
+func group(ops []Segment) Diff {
+	diff := Diff{Segments: ops}
+	hunk := -1
+	for i := range diff.Segments {
+		seg := &diff.Segments[i]
+		switch {
+		case seg.Kind == Equal:
+			seg.Hunk = -1
+			continue
+		case i > 0 && diff.Segments[i-1].Kind != Equal:
+			seg.Hunk = hunk
+		default:
+			hunk++
+			seg.Hunk = hunk
+		}
+	}
+	diff.Hunks = hunk + 1
+	return diff
+}
+
+// Apply rebuilds the text, keeping the revision for the changes accept
+// returns true for and the original text for the rest.
+func (d Diff) Apply(accept func(hunk int) bool) string {
+	var sb strings.Builder
+	for _, seg := range d.Segments {
+		switch seg.Kind {
+		case Equal:
+			sb.WriteString(seg.Text)
+		case Delete:
+			if !accept(seg.Hunk) {
+				sb.WriteString(seg.Text)
+			}
+		case Insert:
+			if accept(seg.Hunk) {
+				sb.WriteString(seg.Text)
+			}
+		}
+	}
+	return sb.String()
+}
+
+// Original returns the text before the changes.
+func (d Diff) Original() string {
+	return d.Apply(func(int) bool { return false })
+}
+
+// Revised returns the text with every change applied.
+func (d Diff) Revised() string {
+	return d.Apply(func(int) bool { return true })
+}
+
+// Change returns the removed and added text of a hunk.
+func (d Diff) Change(hunk int) (removed, added string) {
+	for _, seg := range d.Segments {
+		if seg.Hunk != hunk {
+			continue
+		}
+		if seg.Kind == Delete {
+			removed += seg.Text
+		} else if seg.Kind == Insert {
+			added += seg.Text
+		}
+	}
+	return removed, added
+}
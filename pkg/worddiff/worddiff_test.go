@@ -0,0 +1,65 @@
+package worddiff_test
+
+import (
+	"testing"
+
+	"github.com/bahelit/ctrl_plus_revise/pkg/worddiff"
+)
+
+var diffTable = []struct {
+	Original string
+	Revised  string
+	Hunks    int
+}{
+	{"", "", 0},
+	{"same text", "same text", 0},
+	{"Their going to the store", "They're going to the store.", 2},
+	{"the quick brown fox", "a slow brown fox", 1},
+	{"one two three", "", 1},
+	{"", "brand new", 1},
+	{"Line one\nline two\n", "Line one\nLine two\n", 1},
+	{"I has a apple, and it are red.", "I have an apple, and it is red.", 2},
+	{"Its a nice day, isnt it", "It's a nice day, isn't it?", 3},
+}
+
+func Test_RoundTrip(t *testing.T) {
+	for _, v := range diffTable {
+		d := worddiff.Words(v.Original, v.Revised)
+		if d.Original() != v.Original {
+			t.Fatalf("Expected original %q, received %q", v.Original, d.Original())
+		}
+		if d.Revised() != v.Revised {
+			t.Fatalf("Expected revised %q, received %q", v.Revised, d.Revised())
+		}
+		if d.Hunks != v.Hunks {
+			t.Fatalf("%q -> %q: expected %d changes, received %d %+v", v.Original, v.Revised, v.Hunks, d.Hunks, d.Segments)
+		}
+	}
+}
+
+func Test_ApplySomeHunks(t *testing.T) {
+	d := worddiff.Words("Its a nice day, isnt it", "It's a nice day, isn't it?")
+	result := d.Apply(func(hunk int) bool { return hunk != 1 })
+	if result != "It's a nice day, isnt it?" {
+		t.Fatalf("Unexpected partial result %q", result)
+	}
+
+	removed, added := d.Change(0)
+	if removed != "Its" || added != "It's" {
+		t.Fatalf("Expected Its -> It's, received %q -> %q", removed, added)
+	}
+}
+
+func Test_MergedWhitespace(t *testing.T) {
+	d := worddiff.Words("the quick brown fox", "a slow brown fox")
+	removed, added := d.Change(0)
+	if removed != "the quick" || added != "a slow" {
+		t.Fatalf("Expected \"the quick\" -> \"a slow\", received %q -> %q", removed, added)
+	}
+
+	d = worddiff.Words("I has a apple, and it are red.", "I have an apple, and it is red.")
+	removed, added = d.Change(0)
+	if removed != "has a" || added != "have an" {
+		t.Fatalf("Expected \"has a\" -> \"have an\", received %q -> %q", removed, added)
+	}
+}
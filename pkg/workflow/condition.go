@@ -0,0 +1,61 @@
+package workflow
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Condition compares a measure of the text with a number, e.g. "words > 50".
+// The measures are chars, words and lines.
+type Condition string
+
+var measures = map[string]func(text string) int{
+	"chars": utf8.RuneCountInString,
+	"words": func(text string) int { return len(strings.Fields(text)) },
+	"lines": func(text string) int {
+		text = strings.TrimSpace(text)
+		if text == "" {
+			return 0
+		}
+		return strings.Count(text, "\n") + 1
+	},
+}
+
+// operators are checked in order so <= isn't read as <.
+var operators = []struct {
+	symbol  string
+	compare func(a, b int) bool
+}{
+	{"<=", func(a, b int) bool { return a <= b }},
+	{">=", func(a, b int) bool { return a >= b }},
+	{"!=", func(a, b int) bool { return a != b }},
+	{"==", func(a, b int) bool { return a == b }},
+	{"<", func(a, b int) bool { return a < b }},
+	{">", func(a, b int) bool { return a > b }},
+}
+
+// Eval reports whether the condition holds for the text, an empty condition
+// always holds.
+func (c Condition) Eval(text string) (bool, error) {
+	if strings.TrimSpace(string(c)) == "" {
+		return true, nil
+	}
+	for _, op := range operators {
+		left, right, found := strings.Cut(string(c), op.symbol)
+		if !found {
+			continue
+		}
+		measure, ok := measures[strings.ToLower(strings.TrimSpace(left))]
+		if !ok {
+			return false, fmt.Errorf("condition %q: unknown measure %q, use chars, words or lines", c, strings.TrimSpace(left))
+		}
+		value, err := strconv.Atoi(strings.TrimSpace(right))
+		if err != nil {
+			return false, fmt.Errorf("condition %q: %q is not a number", c, strings.TrimSpace(right))
+		}
+		return op.compare(measure(text), value), nil
+	}
+	return false, fmt.Errorf("condition %q: expected a comparison such as \"words > 50\"", c)
+}
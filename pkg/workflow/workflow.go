@@ -0,0 +1,117 @@
+// Package workflow runs a chain of prompts, each step works on the output of
+// the step before it, e.g. translate, correct the grammar and make a headline.
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Step is one prompt of a workflow.
+type Step struct {
+	// Action is what the step does, e.g. the name of a prompt.
+	Action string `json:"action"`
+	// Model runs the step with another model than the one in the settings.
+	Model string `json:"model,omitempty"`
+	// When only runs the step if the text matches the condition.
+	When Condition `json:"when,omitempty"`
+	// Else is the action used instead when the condition doesn't hold, the
+	// step is skipped when it is empty.
+	Else string `json:"else,omitempty"`
+}
+
+// Workflow is an ordered list of steps.
+type Workflow struct {
+	Name  string `json:"name"`
+	Steps []Step `json:"steps"`
+}
+
+// Executor runs an action with the model on the text and returns the new text,
+// model is empty for the model in the settings.
+type Executor func(ctx context.Context, action, model, text string) (string, error)
+
+// Result is the outcome of one step.
+type Result struct {
+	Step int
+	// Action is the action that ran, empty when the step was skipped.
+	Action string
+	Output string
+}
+
+// Skipped reports whether the condition of the step didn't hold.
+func (r Result) Skipped() bool {
+	return r.Action == ""
+}
+
+// Label describes the step for the user, e.g. "2. Correct Grammar".
+func (r Result) Label() string {
+	if r.Skipped() {
+		return fmt.Sprintf("%d. Skipped", r.Step+1)
+	}
+	return fmt.Sprintf("%d. %s", r.Step+1, r.Action)
+}
+
+// Validate checks the workflow can be run.
+func (w Workflow) Validate() error {
+	if strings.TrimSpace(w.Name) == "" {
+		return errors.New("the workflow needs a name")
+	}
+	if len(w.Steps) == 0 {
+		return errors.New("the workflow needs at least one step")
+	}
+	for i, step := range w.Steps {
+		if strings.TrimSpace(step.Action) == "" {
+			return fmt.Errorf("step %d has no action", i+1)
+		}
+		if _, err := step.When.Eval(""); err != nil {
+			return fmt.Errorf("step %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// Run executes the steps in order, progress is called after every step. The
+// results of the finished steps are returned with the error of a failed step.
+func (w Workflow) Run(ctx context.Context, input string, exec Executor, progress func(Result)) ([]Result, error) {
+	if err := w.Validate(); err != nil {
+		return nil, err
+	}
+	var results []Result
+	text := input
+	for i, step := range w.Steps {
+		if err := ctx.Err(); err != nil {
+			return results, err
+		}
+		action := step.Action
+		ok, err := step.When.Eval(text)
+		if err != nil {
+			return results, err
+		}
+		if !ok {
+			action = step.Else
+		}
+		result := Result{Step: i, Action: action, Output: text}
+		if action != "" {
+			result.Output, err = exec(ctx, action, step.Model, text)
+			if err != nil {
+				return results, fmt.Errorf("step %d, %s: %w", i+1, action, err)
+			}
+			text = result.Output
+		}
+		results = append(results, result)
+		if progress != nil {
+			progress(result)
+		}
+	}
+	return results, nil
+}
+
+// Output returns the text produced by the last step.
+func Output(input string, results []Result) string {
+	if len(results) == 0 {
+		return input
+	}
+	return results[len(results)-1].Output
+}
@@ -0,0 +1,118 @@
+package workflow_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/bahelit/ctrl_plus_revise/pkg/workflow"
+)
+
+var conditionTable = []struct {
+	Condition workflow.Condition
+	Text      string
+	Expected  bool
+	Err       bool
+}{
+	{"", "anything", true, false},
+	{"words > 2", "one two three", true, false},
+	{"words > 3", "one two three", false, false},
+	{"words <= 3", "one two three", true, false},
+	{"chars == 5", "héllo", true, false},
+	{"lines >= 2", "one\ntwo", true, false},
+	{"lines != 1", "one", false, false},
+	{"Chars < 10", "short", true, false},
+	{"size > 1", "text", false, true},
+	{"words > many", "text", false, true},
+	{"words", "text", false, true},
+}
+
+func Test_Condition(t *testing.T) {
+	for _, v := range conditionTable {
+		got, err := v.Condition.Eval(v.Text)
+		if (err != nil) != v.Err {
+			t.Errorf("Expected error=%v for %q, received %v", v.Err, v.Condition, err)
+			continue
+		}
+		if got != v.Expected {
+			t.Errorf("Expected %v for %q on %q, received %v", v.Expected, v.Condition, v.Text, got)
+		}
+	}
+}
+
+// fakeExecutor records the steps and marks the text with the action.
+func fakeExecutor(calls *[]string) workflow.Executor {
+	return func(_ context.Context, action, model, text string) (string, error) {
+		*calls = append(*calls, action+"@"+model)
+		if action == "Fail" {
+			return "", errors.New("model error")
+		}
+		if action == "Headline" {
+			return "HEADLINE", nil
+		}
+		return text + " +" + action, nil
+	}
+}
+
+func Test_Run(t *testing.T) {
+	w := workflow.Workflow{Name: "Publish", Steps: []workflow.Step{
+		{Action: "Translate"},
+		{Action: "Summarize", When: "words > 10", Else: "Correct", Model: "llama3.2:1b"},
+		{Action: "Expand", When: "words > 100"},
+		{Action: "Headline"},
+	}}
+	var calls []string
+	var progressed int
+	results, err := w.Run(context.Background(), "short text", fakeExecutor(&calls), func(workflow.Result) { progressed++ })
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"Translate@", "Correct@llama3.2:1b", "Headline@"}
+	if strings.Join(calls, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected calls %v, received %v", want, calls)
+	}
+	if len(results) != 4 || progressed != 4 {
+		t.Fatalf("Expected 4 results, received %d and %d progress calls", len(results), progressed)
+	}
+	if !results[2].Skipped() || results[2].Output != "short text +Translate +Correct" {
+		t.Errorf("Expected the third step to be skipped and keep the text, received %+v", results[2])
+	}
+	if results[1].Label() != "2. Correct" || results[2].Label() != "3. Skipped" {
+		t.Errorf("Unexpected labels %q and %q", results[1].Label(), results[2].Label())
+	}
+	if got := workflow.Output("short text", results); got != "HEADLINE" {
+		t.Errorf("Expected HEADLINE, received %q", got)
+	}
+}
+
+func Test_RunFailure(t *testing.T) {
+	w := workflow.Workflow{Name: "Broken", Steps: []workflow.Step{{Action: "Translate"}, {Action: "Fail"}, {Action: "Headline"}}}
+	var calls []string
+	results, err := w.Run(context.Background(), "text", fakeExecutor(&calls), nil)
+	if err == nil || !strings.Contains(err.Error(), "step 2") {
+		t.Fatalf("Expected the error of step 2, received %v", err)
+	}
+	if len(results) != 1 || len(calls) != 2 {
+		t.Errorf("Expected one finished step and two calls, received %d and %d", len(results), len(calls))
+	}
+}
+
+var validateTable = []struct {
+	Workflow workflow.Workflow
+	Valid    bool
+}{
+	{workflow.Workflow{Name: "ok", Steps: []workflow.Step{{Action: "Translate"}}}, true},
+	{workflow.Workflow{Name: "", Steps: []workflow.Step{{Action: "Translate"}}}, false},
+	{workflow.Workflow{Name: "empty"}, false},
+	{workflow.Workflow{Name: "no action", Steps: []workflow.Step{{}}}, false},
+	{workflow.Workflow{Name: "bad condition", Steps: []workflow.Step{{Action: "Translate", When: "size > 1"}}}, false},
+}
+
+func Test_Validate(t *testing.T) {
+	for _, v := range validateTable {
+		if err := v.Workflow.Validate(); (err == nil) != v.Valid {
+			t.Errorf("Expected valid=%v for %q, received %v", v.Valid, v.Workflow.Name, err)
+		}
+	}
+}
@@ -15,15 +15,9 @@ import (
 
 func sayHello() {
 	speakResponse := guiApp.Preferences().BoolWithFallback(config.SpeakAIResponseKey, false)
-	if speakResponse {
-		go func() {
-			prompt := guiApp.Preferences().StringWithFallback(config.CurrentPromptKey, ollama.CorrectGrammar.String())
-			_ = shortcuts.Speech.Speak("")
-			speakErr := shortcuts.Speech.Speak("Control Plus Revise is set to: " + prompt)
-			if speakErr != nil {
-				slog.Error("Failed to speak", "error", speakErr)
-			}
-		}()
+	if speakResponse && shortcuts.Speech != nil {
+		prompt := guiApp.Preferences().StringWithFallback(config.CurrentPromptKey, ollama.CorrectGrammar.String())
+		shortcuts.Speech.Say("Control Plus Revise is set to: " + prompt)
 	}
 }
 
@@ -15,13 +15,10 @@ import (
 
 	"github.com/bahelit/ctrl_plus_revise/internal/data"
 	"github.com/bahelit/ctrl_plus_revise/internal/gui/bindings"
-	"github.com/bahelit/ctrl_plus_revise/internal/gui/chat"
-	"github.com/bahelit/ctrl_plus_revise/internal/gui/food"
 	"github.com/bahelit/ctrl_plus_revise/internal/gui/menu"
-	"github.com/bahelit/ctrl_plus_revise/internal/gui/question"
+	"github.com/bahelit/ctrl_plus_revise/internal/gui/modules"
 	"github.com/bahelit/ctrl_plus_revise/internal/gui/settings"
 	"github.com/bahelit/ctrl_plus_revise/internal/gui/shortcuts"
-	"github.com/bahelit/ctrl_plus_revise/internal/gui/translator"
 )
 
 const (
@@ -44,6 +41,11 @@ func SetupSysTray(guiApp fyne.App, ollamaClient *ollamaApi.Client) fyne.Window {
 
 	setupTrayMenu(guiApp, ollamaClient, sysTray)
 	setupTrayWindowContent(guiApp, ollamaClient, sysTray)
+	modules.OnChange(func() {
+		sysTray.SetMainMenu(menu.MakeMenu(guiApp, ollamaClient, sysTray))
+		setupTrayMenu(guiApp, ollamaClient, sysTray)
+		setupTrayWindowContent(guiApp, ollamaClient, sysTray)
+	})
 
 	sysTray.SetCloseIntercept(func() {
 		sysTray.Hide()
@@ -54,19 +56,23 @@ func SetupSysTray(guiApp fyne.App, ollamaClient *ollamaApi.Client) fyne.Window {
 // setupTrayMenu sets up the system tray menu
 func setupTrayMenu(guiApp fyne.App, ollamaClient *ollamaApi.Client, sysTray fyne.Window) {
 	if desk, ok := guiApp.(desktop.App); ok {
-		desk.SetSystemTrayMenu(fyne.NewMenu(TrayMenuTitle,
-			fyne.NewMenuItem("Ask a Question", func() { question.AskQuestionWindow(guiApp, ollamaClient) }),
-			fyne.NewMenuItem("Chat with AI", func() { chat.ConversationManager(guiApp, ollamaClient) }),
-			fyne.NewMenuItem("Meal Planner", func() { food.MealPlanner(guiApp, ollamaClient) }),
-			fyne.NewMenuItem("Translate Window", func() { translator.TranslateText(guiApp, ollamaClient) }),
+		var items []*fyne.MenuItem
+		for _, m := range modules.Enabled(guiApp) {
+			item := fyne.NewMenuItem(m.Name, func() { m.Open(guiApp, ollamaClient) })
+			item.Icon = m.Icon
+			items = append(items, item)
+		}
+		items = append(items,
 			fyne.NewMenuItemSeparator(),
 			fyne.NewMenuItem("Home Screen", func() { sysTray.Show() }),
 			fyne.NewMenuItemSeparator(),
 			fyne.NewMenuItem("Settings", func() { settings.ShowSettings(guiApp, ollamaClient) }),
+			fyne.NewMenuItem("Modules", func() { settings.ShowModuleSettings(guiApp, ollamaClient) }),
 			fyne.NewMenuItem("Keyboard Shortcuts", func() { shortcuts.ShowShortcuts(guiApp) }),
 			fyne.NewMenuItemSeparator(),
 			fyne.NewMenuItem("About", func() { settings.ShowAbout(guiApp) }),
-		))
+		)
+		desk.SetSystemTrayMenu(fyne.NewMenu(TrayMenuTitle, items...))
 	}
 }
 
@@ -74,32 +80,14 @@ func setupTrayMenu(guiApp fyne.App, ollamaClient *ollamaApi.Client, sysTray fyne
 func setupTrayWindowContent(guiApp fyne.App, ollamaClient *ollamaApi.Client, sysTray fyne.Window) {
 	welcomeText := mainWindowText()
 
-	askQuestionsButton := widget.NewButton("Ask a Question", func() {
-		question.AskQuestionWindow(guiApp, ollamaClient)
-	})
-	askQuestionsButton.Importance = widget.HighImportance
-
-	chatButton := widget.NewButton("Chat with AI", func() {
-		chat.ConversationManager(guiApp, ollamaClient)
-	})
-	chatButton.Importance = widget.HighImportance
-
-	recipeButton := widget.NewButton("Meal Planner", func() {
-		food.MealPlanner(guiApp, ollamaClient)
-	})
-	recipeButton.Importance = widget.HighImportance
-
-	translatorButton := widget.NewButton("Translate Text", func() {
-		translator.TranslateText(guiApp, ollamaClient)
-	})
-	translatorButton.Importance = widget.HighImportance
-
-	buttons := container.NewVBox(
-		container.NewPadded(askQuestionsButton),
-		container.NewPadded(chatButton),
-		container.NewPadded(recipeButton),
-		container.NewPadded(translatorButton),
-	)
+	buttons := container.NewVBox()
+	for _, m := range modules.Enabled(guiApp) {
+		button := widget.NewButtonWithIcon(m.Name, m.Icon, func() {
+			m.Open(guiApp, ollamaClient)
+		})
+		button.Importance = widget.HighImportance
+		buttons.Add(container.NewPadded(button))
+	}
 	footer := footer()
 	sysTray.SetContent(container.NewBorder(welcomeText, footer, nil, nil, buttons))
 }